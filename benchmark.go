@@ -24,19 +24,43 @@ import (
 	"github.com/succinctlabs/gnark-plonky2-verifier/verifier"
 )
 
-func runBenchmark(plonky2Circuit string, proofSystem string, profileCircuit bool, dummy bool, saveArtifacts bool) {
-	commonCircuitData := types.ReadCommonCircuitData("testdata/" + plonky2Circuit + "/common_circuit_data.json")
+// Reads the proof of a fixture, and checks it against the common circuit data before deserializing
+// it into the witness.
+func readProofWithPublicInputs(circuitName string, commonCircuitData *types.CommonCircuitData) variables.ProofWithPublicInputs {
+	raw := types.ReadProofWithPublicInputs("testdata/" + circuitName + "/proof_with_public_inputs.json")
+	if violations := types.ValidateProofWithPublicInputs(&raw, commonCircuitData); len(violations) > 0 {
+		panic(fmt.Sprintf("invalid proof: %v", violations))
+	}
 
-	proofWithPis := variables.DeserializeProofWithPublicInputs(types.ReadProofWithPublicInputs("testdata/" + plonky2Circuit + "/proof_with_public_inputs.json"))
-	verifierOnlyCircuitData := variables.DeserializeVerifierOnlyCircuitData(types.ReadVerifierOnlyCircuitData("testdata/" + plonky2Circuit + "/verifier_only_circuit_data.json"))
+	proofWithPis, err := variables.DeserializeProofWithPublicInputs(raw)
+	if err != nil {
+		panic(err)
+	}
+	return proofWithPis
+}
 
-	circuit := verifier.ExampleVerifierCircuit{
-		Proof:                   proofWithPis.Proof,
-		PublicInputs:            proofWithPis.PublicInputs,
-		VerifierOnlyCircuitData: verifierOnlyCircuitData,
-		CommonCircuitData:       commonCircuitData,
+// Reads the verifier only circuit data of a fixture, and checks it against the common circuit data
+// before deserializing it into the witness.
+func readVerifierOnlyCircuitData(circuitName string, commonCircuitData *types.CommonCircuitData) variables.VerifierOnlyCircuitData {
+	raw := types.ReadVerifierOnlyCircuitData("testdata/" + circuitName + "/verifier_only_circuit_data.json")
+	if violations := types.ValidateVerifierOnlyCircuitData(&raw, commonCircuitData); len(violations) > 0 {
+		panic(fmt.Sprintf("invalid verifier only circuit data: %v", violations))
 	}
 
+	verifierOnlyCircuitData, err := variables.DeserializeVerifierOnlyCircuitData(raw)
+	if err != nil {
+		panic(err)
+	}
+	return verifierOnlyCircuitData
+}
+
+func runBenchmark(plonky2Circuit string, proofSystem string, profileCircuit bool, dummy bool, saveArtifacts bool) {
+	commonCircuitData := types.ReadCommonCircuitData("testdata/" + plonky2Circuit + "/common_circuit_data.json")
+	verifierOnlyCircuitData := readVerifierOnlyCircuitData(plonky2Circuit, &commonCircuitData)
+
+	// The proof is a witness of the circuit, so only the circuit's shape is needed to compile it.
+	circuit := verifier.NewExampleVerifierCircuit(verifierOnlyCircuitData, commonCircuitData)
+
 	var p *profile.Profile
 	if profileCircuit {
 		p = profile.Start()
@@ -83,8 +107,9 @@ func plonkProof(r1cs constraint.ConstraintSystem, circuitName string, dummy bool
 	var srs kzg.SRS = kzg.NewSRS(ecc.BN254)
 	var err error
 
-	proofWithPis := variables.DeserializeProofWithPublicInputs(types.ReadProofWithPublicInputs("testdata/" + circuitName + "/proof_with_public_inputs.json"))
-	verifierOnlyCircuitData := variables.DeserializeVerifierOnlyCircuitData(types.ReadVerifierOnlyCircuitData("testdata/" + circuitName + "/verifier_only_circuit_data.json"))
+	commonCircuitData := types.ReadCommonCircuitData("testdata/" + circuitName + "/common_circuit_data.json")
+	proofWithPis := readProofWithPublicInputs(circuitName, &commonCircuitData)
+	verifierOnlyCircuitData := readVerifierOnlyCircuitData(circuitName, &commonCircuitData)
 	assignment := verifier.ExampleVerifierCircuit{
 		Proof:                   proofWithPis.Proof,
 		PublicInputs:            proofWithPis.PublicInputs,
@@ -194,8 +219,9 @@ func groth16Proof(r1cs constraint.ConstraintSystem, circuitName string, dummy bo
 	var vk groth16.VerifyingKey
 	var err error
 
-	proofWithPis := variables.DeserializeProofWithPublicInputs(types.ReadProofWithPublicInputs("testdata/" + circuitName + "/proof_with_public_inputs.json"))
-	verifierOnlyCircuitData := variables.DeserializeVerifierOnlyCircuitData(types.ReadVerifierOnlyCircuitData("testdata/" + circuitName + "/verifier_only_circuit_data.json"))
+	commonCircuitData := types.ReadCommonCircuitData("testdata/" + circuitName + "/common_circuit_data.json")
+	proofWithPis := readProofWithPublicInputs(circuitName, &commonCircuitData)
+	verifierOnlyCircuitData := readVerifierOnlyCircuitData(circuitName, &commonCircuitData)
 	assignment := verifier.ExampleVerifierCircuit{
 		Proof:                   proofWithPis.Proof,
 		PublicInputs:            proofWithPis.PublicInputs,
@@ -0,0 +1,145 @@
+package challenger
+
+import (
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+)
+
+// The out of circuit counterpart of Chip.  It produces the same transcript as the chip, so its
+// challenges can be diffed against the ones that plonky2 computes for a proof.
+type Challenger struct {
+	poseidonHasher      *poseidon.GoldilocksHasher
+	poseidonBN254Hasher *poseidon.BN254Hasher
+	spongeState         poseidon.GoldilocksNativeState
+	inputBuffer         []goldilocks.Element
+	outputBuffer        []goldilocks.Element
+}
+
+func NewChallenger() *Challenger {
+	return &Challenger{
+		poseidonHasher:      poseidon.NewGoldilocksHasher(),
+		poseidonBN254Hasher: poseidon.NewBN254Hasher(),
+	}
+}
+
+func (c *Challenger) ObserveElement(element goldilocks.Element) {
+	// Clear the output buffer
+	c.outputBuffer = make([]goldilocks.Element, 0)
+	c.inputBuffer = append(c.inputBuffer, element)
+	if len(c.inputBuffer) == poseidon.SPONGE_RATE {
+		c.duplexing()
+	}
+}
+
+func (c *Challenger) ObserveElements(elements []goldilocks.Element) {
+	for i := 0; i < len(elements); i++ {
+		c.ObserveElement(elements[i])
+	}
+}
+
+func (c *Challenger) ObserveHash(hash poseidon.GoldilocksNativeHashOut) {
+	elements := c.poseidonHasher.ToVec(hash)
+	c.ObserveElements(elements)
+}
+
+func (c *Challenger) ObserveBN254Hash(hash poseidon.BN254NativeHashOut) {
+	elements := c.poseidonBN254Hasher.ToVec(hash)
+	c.ObserveElements(elements)
+}
+
+func (c *Challenger) ObserveCap(cap []poseidon.BN254NativeHashOut) {
+	for i := 0; i < len(cap); i++ {
+		c.ObserveBN254Hash(cap[i])
+	}
+}
+
+func (c *Challenger) ObserveExtensionElement(element gl.QuadraticExtension) {
+	c.ObserveElements(element[:])
+}
+
+func (c *Challenger) ObserveExtensionElements(elements []gl.QuadraticExtension) {
+	for i := 0; i < len(elements); i++ {
+		c.ObserveExtensionElement(elements[i])
+	}
+}
+
+// Observes the values of each opening batch, in the same order as fri.Chip.ToOpenings.
+func (c *Challenger) ObserveOpenings(openingBatches [][]gl.QuadraticExtension) {
+	for i := 0; i < len(openingBatches); i++ {
+		c.ObserveExtensionElements(openingBatches[i])
+	}
+}
+
+func (c *Challenger) GetChallenge() goldilocks.Element {
+	if len(c.inputBuffer) != 0 || len(c.outputBuffer) == 0 {
+		c.duplexing()
+	}
+
+	challenge := c.outputBuffer[len(c.outputBuffer)-1]
+	c.outputBuffer = c.outputBuffer[:len(c.outputBuffer)-1]
+
+	return challenge
+}
+
+func (c *Challenger) GetNChallenges(n uint64) []goldilocks.Element {
+	challenges := make([]goldilocks.Element, n)
+	for i := uint64(0); i < n; i++ {
+		challenges[i] = c.GetChallenge()
+	}
+	return challenges
+}
+
+func (c *Challenger) GetExtensionChallenge() gl.QuadraticExtension {
+	values := c.GetNChallenges(2)
+	return gl.NewQuadraticExtension(values[0], values[1])
+}
+
+func (c *Challenger) GetHash() poseidon.GoldilocksNativeHashOut {
+	return poseidon.GoldilocksNativeHashOut{c.GetChallenge(), c.GetChallenge(), c.GetChallenge(), c.GetChallenge()}
+}
+
+func (c *Challenger) GetFriChallenges(
+	commitPhaseMerkleCaps [][]poseidon.BN254NativeHashOut,
+	finalPoly []gl.QuadraticExtension,
+	powWitness goldilocks.Element,
+	config types.FriConfig,
+) types.FriChallengesRaw {
+	numFriQueries := config.NumQueryRounds
+	friAlpha := c.GetExtensionChallenge()
+
+	friBetas := make([][]uint64, 0, len(commitPhaseMerkleCaps))
+	for i := 0; i < len(commitPhaseMerkleCaps); i++ {
+		c.ObserveCap(commitPhaseMerkleCaps[i])
+		friBetas = append(friBetas, gl.QuadraticExtensionToUint64Array(c.GetExtensionChallenge()))
+	}
+
+	c.ObserveExtensionElements(finalPoly)
+	c.ObserveElement(powWitness)
+
+	friPowResponse := c.GetChallenge()
+	friQueryIndices := c.GetNChallenges(numFriQueries)
+
+	return types.FriChallengesRaw{
+		FriAlpha:        gl.QuadraticExtensionToUint64Array(friAlpha),
+		FriBetas:        friBetas,
+		FriPowResponse:  friPowResponse.Uint64(),
+		FriQueryIndices: gl.ElementArrayToUint64Array(friQueryIndices),
+	}
+}
+
+func (c *Challenger) duplexing() {
+	for i := 0; i < len(c.inputBuffer); i++ {
+		c.spongeState[i] = c.inputBuffer[i]
+	}
+	// Clear the input buffer
+	c.inputBuffer = make([]goldilocks.Element, 0)
+	c.spongeState = c.poseidonHasher.Poseidon(c.spongeState)
+
+	// Clear the output buffer
+	c.outputBuffer = make([]goldilocks.Element, 0, poseidon.SPONGE_RATE)
+	for i := 0; i < poseidon.SPONGE_RATE; i++ {
+		c.outputBuffer = append(c.outputBuffer, c.spongeState[i])
+	}
+}
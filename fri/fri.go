@@ -20,6 +20,8 @@ type Chip struct {
 	poseidonBN254Chip *poseidon.BN254Chip      `gnark:"-"`
 	commonData        *types.CommonCircuitData `gnark:"-"`
 	friParams         *types.FriParams         `gnark:"-"`
+	// When not nil, the proof is only checked when enabled is 1.
+	enabled frontend.Variable `gnark:"-"`
 }
 
 func NewChip(
@@ -37,6 +39,28 @@ func NewChip(
 	}
 }
 
+// Like NewChip, but the constraints that check the proof only hold when enabled is 1, so that a
+// proof doesn't have to be valid when enabled is 0.  enabled must be 0 or 1.  The proof's hashes
+// and index decompositions are still computed, so its values must still be canonical.
+func NewConditionalChip(
+	api frontend.API,
+	commonData *types.CommonCircuitData,
+	friParams *types.FriParams,
+	enabled frontend.Variable,
+) *Chip {
+	f := NewChip(api, commonData, friParams)
+	f.enabled = enabled
+	return f
+}
+
+func (f *Chip) assertIsEqual(a, b frontend.Variable) {
+	if f.enabled == nil {
+		f.api.AssertIsEqual(a, b)
+		return
+	}
+	f.api.AssertIsEqual(f.api.Mul(f.enabled, f.api.Sub(a, b)), 0)
+}
+
 func (f *Chip) GetInstance(zeta gl.QuadraticExtensionVariable) InstanceInfo {
 	zetaBatch := BatchInfo{
 		Point:       zeta,
@@ -51,7 +75,7 @@ func (f *Chip) GetInstance(zeta gl.QuadraticExtensionVariable) InstanceInfo {
 
 	zetaNextBatch := BatchInfo{
 		Point:       zetaNext,
-		Polynomials: friZSPolys(f.commonData),
+		Polynomials: friNextBatchPolys(f.commonData),
 	}
 
 	return InstanceInfo{
@@ -67,8 +91,11 @@ func (f *Chip) ToOpenings(c variables.OpeningSet) Openings {
 	values = append(values, c.PlonkZs...)         // num_challenges
 	values = append(values, c.PartialProducts...) // num_challenges * num_partial_products
 	values = append(values, c.QuotientPolys...)   // num_challenges * quotient_degree_factor
+	values = append(values, c.LookupZs...)        // num_challenges * num_lookup_polys
 	zetaBatch := OpeningBatch{Values: values}
-	zetaNextBatch := OpeningBatch{Values: c.PlonkZsNext}
+	nextValues := c.PlonkZsNext                        // num_challenges
+	nextValues = append(nextValues, c.LookupZsNext...) // num_challenges * num_lookup_polys
+	zetaNextBatch := OpeningBatch{Values: nextValues}
 	return Openings{Batches: []OpeningBatch{zetaBatch, zetaNextBatch}}
 }
 
@@ -76,6 +103,9 @@ func (f *Chip) assertLeadingZeros(powWitness gl.Variable, friConfig types.FriCon
 	// Asserts that powWitness'es big-endian bit representation has at least friConfig.ProofOfWorkBits leading zeros.
 	// Note that this is assuming that the Goldilocks field is being used.  Specfically that the
 	// field is 64 bits long.
+	if f.enabled != nil {
+		powWitness = gl.NewVariable(f.api.Mul(f.enabled, powWitness.Limb))
+	}
 	f.gl.RangeCheckWithMaxBits(powWitness, 64-friConfig.ProofOfWorkBits)
 }
 
@@ -84,7 +114,7 @@ func (f *Chip) fromOpeningsAndAlpha(
 	alpha gl.QuadraticExtensionVariable,
 ) []gl.QuadraticExtensionVariable {
 	// One reduced opening for all openings evaluated at point Zeta.
-	// Another one for all openings evaluated at point Zeta * Omega (which is only PlonkZsNext polynomial)
+	// Another one for all openings evaluated at point Zeta * Omega (which are the PlonkZsNext and LookupZsNext polynomials)
 
 	reducedOpenings := make([]gl.QuadraticExtensionVariable, 0, 2)
 	for _, batch := range openings.Batches {
@@ -115,32 +145,62 @@ func (f *Chip) verifyMerkleProofToCapWithCapIndex(
 		currentDigest = state[0]
 	}
 
-	// We assume that the cap_height is 4.  Create two levels of the Lookup2 circuit
-	if len(capIndexBits) != 4 || len(merkleCap) != 16 {
-		errorMsg, _ := fmt.Printf(
-			"capIndexBits length should be 4 and the merkleCap length should be 16.  Actual values (capIndexBits: %d, merkleCap: %d)\n",
-			len(capIndexBits),
-			len(merkleCap),
-		)
-		panic(errorMsg)
-	}
-
-	const NUM_LEAF_LOOKUPS = 4
-	// Each lookup gadget will connect to 4 merkleCap entries
-	const STRIDE_LENGTH = 4
-	var leafLookups [NUM_LEAF_LOOKUPS]poseidon.BN254HashOut
-	// First create the "leaf" lookup2 circuits
-	// This will use the least significant bits of the capIndexBits array
-	for i := 0; i < NUM_LEAF_LOOKUPS; i++ {
-		leafLookups[i] = f.api.Lookup2(
-			capIndexBits[0], capIndexBits[1],
-			merkleCap[i*STRIDE_LENGTH], merkleCap[i*STRIDE_LENGTH+1], merkleCap[i*STRIDE_LENGTH+2], merkleCap[i*STRIDE_LENGTH+3],
-		)
+	merkleCapEntry := f.selectCapEntry(capIndexBits, merkleCap)
+	f.assertIsEqual(currentDigest, merkleCapEntry)
+}
+
+// Returns merkleCap[capIndex], where capIndexBits are the little endian bits of capIndex.  The cap
+// must have 2^len(capIndexBits) entries, which allows any cap height including 0.
+func (f *Chip) selectCapEntry(capIndexBits []frontend.Variable, merkleCap variables.FriMerkleCap) poseidon.BN254HashOut {
+	return selectByBits(capIndexBits, merkleCap, f.api.Select, f.api.Lookup2)
+}
+
+// Returns evals[index], where indexBits are the little endian bits of index, for the evals of a FRI
+// step of any arity.
+func (f *Chip) selectEval(indexBits []frontend.Variable, evals []gl.QuadraticExtensionVariable) gl.QuadraticExtensionVariable {
+	selectFn := func(b frontend.Variable, i1, i0 gl.QuadraticExtensionVariable) gl.QuadraticExtensionVariable {
+		return f.gl.Lookup(b, i0, i1)
 	}
+	return selectByBits(indexBits, evals, selectFn, f.gl.Lookup2)
+}
 
-	// Use the most 2 significant bits of the capIndexBits array for the "root" lookup
-	merkleCapEntry := f.api.Lookup2(capIndexBits[2], capIndexBits[3], leafLookups[0], leafLookups[1], leafLookups[2], leafLookups[3])
-	f.api.AssertIsEqual(currentDigest, merkleCapEntry)
+// Returns values[index], where indexBits are the little endian bits of index, and values has
+// 2^len(indexBits) entries.  selectFn and lookup2 have the semantics of frontend.API's Select and
+// Lookup2 for the element type.
+func selectByBits[T any](
+	indexBits []frontend.Variable,
+	values []T,
+	selectFn func(b frontend.Variable, i1, i0 T) T,
+	lookup2 func(b0, b1 frontend.Variable, i0, i1, i2, i3 T) T,
+) T {
+	if len(values) != 1<<len(indexBits) {
+		panic(fmt.Sprintf(
+			"values length should be 2^len(indexBits).  Actual values (indexBits: %d, values: %d)",
+			len(indexBits),
+			len(values),
+		))
+	}
+
+	// Halve the candidate values with each bit, starting from the least significant.  Lookup2 is
+	// cheaper than two levels of Select, so consume two bits at a time where possible.
+	candidates := values
+	remainingBits := indexBits
+	for len(remainingBits) >= 2 {
+		nextCandidates := make([]T, len(candidates)/4)
+		for i := range nextCandidates {
+			nextCandidates[i] = lookup2(
+				remainingBits[0], remainingBits[1],
+				candidates[4*i], candidates[4*i+1], candidates[4*i+2], candidates[4*i+3],
+			)
+		}
+		candidates = nextCandidates
+		remainingBits = remainingBits[2:]
+	}
+	if len(remainingBits) == 1 {
+		return selectFn(remainingBits[0], candidates[1], candidates[0])
+	}
+
+	return candidates[0]
 }
 
 func (f *Chip) verifyInitialProof(xIndexBits []frontend.Variable, proof *variables.FriInitialTreeProof, initialMerkleCaps []variables.FriMerkleCap, capIndexBits []frontend.Variable) {
@@ -322,9 +382,6 @@ func (f *Chip) computeEvaluation(
 	if (len(evals)) != arity {
 		panic("len(evals) != arity")
 	}
-	if arityBits > 8 {
-		panic("currently assuming that arityBits is <= 8")
-	}
 
 	g := gl.PrimitiveRootOfUnity(arityBits)
 	gInv := goldilocks.NewElement(0)
@@ -335,8 +392,8 @@ func (f *Chip) computeEvaluation(
 	// OPTIMIZE - Since the size of the evals array should be constant (e.g. 2^arityBits),
 	//        we can just hard code the permutation.
 	permutedEvals := make([]gl.QuadraticExtensionVariable, len(evals))
-	for i := uint8(0); i <= uint8(len(evals)-1); i++ {
-		newIndex := bits.Reverse8(i) >> (8 - arityBits)
+	for i := 0; i < len(evals); i++ {
+		newIndex := bits.Reverse64(uint64(i)) >> (64 - arityBits)
 		permutedEvals[newIndex] = evals[i]
 	}
 
@@ -424,41 +481,10 @@ func (f *Chip) verifyQueryRound(
 		cosetIndexBits := xIndexBits[arityBits:]
 		xIndexWithinCosetBits := xIndexBits[:arityBits]
 
-		// Assumes that the arity bits will be 4.  That means that the range of
-		// xIndexWithCoset is [0,2^4-1].  This is based on plonky2's circuit recursive
-		// config:  https://github.com/mir-protocol/plonky2/blob/main/plonky2/src/plonk/circuit_data.rs#L63
-		// Will use a two levels tree of 4-selector gadgets.
-		if arityBits != 4 {
-			panic("assuming arity bits is 4")
-		}
-
-		const NUM_LEAF_LOOKUPS = 4
-		var leafLookups [NUM_LEAF_LOOKUPS]gl.QuadraticExtensionVariable
-		// First create the "leaf" lookup2 circuits
-		// The will use the least significant bits of the xIndexWithCosetBits array
-		for i := 0; i < NUM_LEAF_LOOKUPS; i++ {
-			leafLookups[i] = f.gl.Lookup2(
-				xIndexWithinCosetBits[0],
-				xIndexWithinCosetBits[1],
-				evals[i*NUM_LEAF_LOOKUPS],
-				evals[i*NUM_LEAF_LOOKUPS+1],
-				evals[i*NUM_LEAF_LOOKUPS+2],
-				evals[i*NUM_LEAF_LOOKUPS+3],
-			)
-		}
-
-		// Use the most 2 significant bits of the xIndexWithCosetBits array for the "root" lookup
-		newEval := f.gl.Lookup2(
-			xIndexWithinCosetBits[2],
-			xIndexWithinCosetBits[3],
-			leafLookups[0],
-			leafLookups[1],
-			leafLookups[2],
-			leafLookups[3],
-		)
+		newEval := f.selectEval(xIndexWithinCosetBits, evals)
 
-		f.gl.AssertIsEqual(newEval[0], oldEval[0])
-		f.gl.AssertIsEqual(newEval[1], oldEval[1])
+		f.assertIsEqual(newEval[0].Limb, oldEval[0].Limb)
+		f.assertIsEqual(newEval[1].Limb, oldEval[1].Limb)
 
 		oldEval = f.computeEvaluation(
 			subgroupX,
@@ -493,8 +519,8 @@ func (f *Chip) verifyQueryRound(
 	subgroupX_QE = subgroupX.ToQuadraticExtension()
 	finalPolyEval := f.finalPolyEval(proof.FinalPoly, subgroupX_QE)
 
-	f.gl.AssertIsEqual(oldEval[0], finalPolyEval[0])
-	f.gl.AssertIsEqual(oldEval[1], finalPolyEval[1])
+	f.assertIsEqual(oldEval[0].Limb, finalPolyEval[0].Limb)
+	f.assertIsEqual(oldEval[1].Limb, finalPolyEval[1].Limb)
 }
 
 func (f *Chip) VerifyFriProof(
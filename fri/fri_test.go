@@ -1,14 +1,18 @@
 package fri_test
 
 import (
+	"fmt"
+	"os"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/test"
 	"github.com/succinctlabs/gnark-plonky2-verifier/challenger"
 	"github.com/succinctlabs/gnark-plonky2-verifier/fri"
 	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/native"
 	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
 	"github.com/succinctlabs/gnark-plonky2-verifier/types"
 	"github.com/succinctlabs/gnark-plonky2-verifier/variables"
@@ -110,9 +114,11 @@ func TestDecodeBlockFriVerification(t *testing.T) {
 	commonCircuitDataFilename := "../testdata/decode_block/common_circuit_data.json"
 	verifierOnlyCircuitDataFilename := "../testdata/decode_block/verifier_only_circuit_data.json"
 
-	proofWithPis := variables.DeserializeProofWithPublicInputs(types.ReadProofWithPublicInputs(proofWithPIsFilename))
+	proofWithPis, err := variables.DeserializeProofWithPublicInputs(types.ReadProofWithPublicInputs(proofWithPIsFilename))
+	assert.NoError(err)
 	commonCircuitData := types.ReadCommonCircuitData(commonCircuitDataFilename)
-	verifierOnlyCircuitData := variables.DeserializeVerifierOnlyCircuitData(types.ReadVerifierOnlyCircuitData(verifierOnlyCircuitDataFilename))
+	verifierOnlyCircuitData, err := variables.DeserializeVerifierOnlyCircuitData(types.ReadVerifierOnlyCircuitData(verifierOnlyCircuitDataFilename))
+	assert.NoError(err)
 
 	testCase := func() {
 		circuit := TestFriCircuit{
@@ -131,3 +137,273 @@ func TestDecodeBlockFriVerification(t *testing.T) {
 
 	testCase()
 }
+
+type TestFriCapHeightCircuit struct {
+	ProofWithPis            variables.ProofWithPublicInputs
+	VerifierOnlyCircuitData variables.VerifierOnlyCircuitData
+	PlonkZeta               gl.QuadraticExtensionVariable
+	FriChallenges           variables.FriChallenges
+	CommonCircuitData       types.CommonCircuitData
+}
+
+func (circuit *TestFriCapHeightCircuit) Define(api frontend.API) error {
+	commonCircuitData := circuit.CommonCircuitData
+	proof := circuit.ProofWithPis.Proof
+	friChip := fri.NewChip(api, &commonCircuitData, &commonCircuitData.FriParams)
+
+	initialMerkleCaps := []variables.FriMerkleCap{
+		circuit.VerifierOnlyCircuitData.ConstantSigmasCap,
+		proof.WiresCap,
+		proof.PlonkZsPartialProductsCap,
+		proof.QuotientPolysCap,
+	}
+
+	friChip.VerifyFriProof(
+		friChip.GetInstance(circuit.PlonkZeta),
+		friChip.ToOpenings(proof.Openings),
+		&circuit.FriChallenges,
+		initialMerkleCaps,
+		&proof.OpeningProof,
+	)
+
+	return nil
+}
+
+// Lowers a merkle cap by one level, hashing pairs of its entries the way plonky2 hashes the nodes
+// below the cap, and appends the cap entry next to each of the proofs' paths to their siblings.
+// capIndices are the indices of the entries that the proofs lead to, and are updated to the
+// lowered cap.
+func lowerCapHeight(merkleCap []string, siblings [][]string, capIndices []uint64) []string {
+	hasher := poseidon.NewBN254Hasher()
+
+	entries := make([]fr.Element, len(merkleCap))
+	for i, entry := range merkleCap {
+		if _, err := entries[i].SetString(entry); err != nil {
+			panic(err)
+		}
+	}
+
+	for i := range siblings {
+		siblings[i] = append(siblings[i], merkleCap[capIndices[i]^1])
+		capIndices[i] >>= 1
+	}
+
+	loweredCap := make([]string, len(entries)/2)
+	for i := range loweredCap {
+		node := hasher.TwoToOne(entries[2*i], entries[2*i+1])
+		loweredCap[i] = node.String()
+	}
+	return loweredCap
+}
+
+// Reads a plonky2 fixture and moves its merkle caps down to capHeight: the trees are the ones that
+// plonky2 built, but their caps are taken lower down and the merkle proofs are longer.  plonky2
+// would observe the lower caps in the transcript and so derive different challenges, so the
+// challenges are those of the original proof, which only the FRI verification can be checked
+// against.
+func readFixtureAtCapHeight(plonky2Circuit string, capHeight uint64) (
+	types.CommonCircuitData,
+	types.ProofWithPublicInputsRaw,
+	types.VerifierOnlyCircuitDataRaw,
+	types.ProofChallengesRaw,
+) {
+	commonCircuitData := types.ReadCommonCircuitData("../testdata/" + plonky2Circuit + "/common_circuit_data.json")
+	proofWithPis := types.ReadProofWithPublicInputs("../testdata/" + plonky2Circuit + "/proof_with_public_inputs.json")
+	verifierOnlyCircuitData := types.ReadVerifierOnlyCircuitData("../testdata/" + plonky2Circuit + "/verifier_only_circuit_data.json")
+
+	challenges, err := native.NewVerifier(commonCircuitData).GetChallenges(proofWithPis, verifierOnlyCircuitData)
+	if err != nil {
+		panic(err)
+	}
+
+	ldeBits := commonCircuitData.FriParams.DegreeBits + commonCircuitData.FriParams.Config.RateBits
+	originalCapHeight := commonCircuitData.FriParams.Config.CapHeight
+	queryRounds := proofWithPis.Proof.OpeningProof.QueryRoundProofs
+
+	// Each cap is lowered along with the proofs that lead to it, one per query round.
+	lower := func(merkleCap []string, siblings func(round int) *[]string) []string {
+		paths := make([][]string, len(queryRounds))
+		capIndices := make([]uint64, len(queryRounds))
+		for round := range queryRounds {
+			paths[round] = *siblings(round)
+			// The cap index is the top bits of the leaf index, which are the same for the initial
+			// trees and for the trees of the folded codewords.
+			xIndex := challenges.FriChallenges.FriQueryIndices[round] & (1<<ldeBits - 1)
+			capIndices[round] = xIndex >> (ldeBits - originalCapHeight)
+		}
+		for height := originalCapHeight; height > capHeight; height-- {
+			merkleCap = lowerCapHeight(merkleCap, paths, capIndices)
+		}
+		for round := range queryRounds {
+			*siblings(round) = paths[round]
+		}
+		return merkleCap
+	}
+
+	proof := &proofWithPis.Proof
+	for i, initialCap := range []*[]string{
+		&verifierOnlyCircuitData.ConstantsSigmasCap,
+		&proof.WiresCap,
+		&proof.PlonkZsPartialProductsCap,
+		&proof.QuotientPolysCap,
+	} {
+		*initialCap = lower(*initialCap, func(round int) *[]string {
+			return &queryRounds[round].InitialTreesProof.EvalsProofs[i].MerkleProof.Hash
+		})
+	}
+	for i := range proof.OpeningProof.CommitPhaseMerkleCaps {
+		proof.OpeningProof.CommitPhaseMerkleCaps[i] = lower(proof.OpeningProof.CommitPhaseMerkleCaps[i], func(round int) *[]string {
+			return &queryRounds[round].Steps[i].MerkleProof.Siblings
+		})
+	}
+
+	commonCircuitData.Config.FriConfig.CapHeight = capHeight
+	commonCircuitData.FriParams.Config.CapHeight = capHeight
+
+	return commonCircuitData, proofWithPis, verifierOnlyCircuitData, challenges
+}
+
+func toQuadraticExtensionVariable(value []uint64) gl.QuadraticExtensionVariable {
+	return gl.NewQuadraticExtensionVariable(gl.NewVariable(value[0]), gl.NewVariable(value[1]))
+}
+
+func toFriChallenges(challenges types.ProofChallengesRaw) variables.FriChallenges {
+	friChallenges := variables.FriChallenges{
+		FriAlpha:       toQuadraticExtensionVariable(challenges.FriChallenges.FriAlpha),
+		FriPowResponse: gl.NewVariable(challenges.FriChallenges.FriPowResponse),
+	}
+	for _, beta := range challenges.FriChallenges.FriBetas {
+		friChallenges.FriBetas = append(friChallenges.FriBetas, toQuadraticExtensionVariable(beta))
+	}
+	for _, queryIndex := range challenges.FriChallenges.FriQueryIndices {
+		friChallenges.FriQueryIndices = append(friChallenges.FriQueryIndices, gl.NewVariable(queryIndex))
+	}
+	return friChallenges
+}
+
+func TestFriVerificationToCapHeights(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// plonky2 only generated these fixtures with cap height 4, see readFixtureAtCapHeight, so the
+	// other cap heights are re-capped here and checked against real proofs by
+	// TestFriVerificationCapHeightFixtures.
+	for _, capHeight := range []uint64{0, 1, 3} {
+		commonCircuitData, proofWithPisRaw, verifierOnlyCircuitDataRaw, challenges := readFixtureAtCapHeight("decode_block", capHeight)
+
+		proofWithPis, err := variables.DeserializeProofWithPublicInputs(proofWithPisRaw)
+		assert.NoError(err)
+		verifierOnlyCircuitData, err := variables.DeserializeVerifierOnlyCircuitData(verifierOnlyCircuitDataRaw)
+		assert.NoError(err)
+		if len(verifierOnlyCircuitData.ConstantSigmasCap) != 1<<capHeight {
+			t.Fatalf("expected a cap of %d entries, got %d", 1<<capHeight, len(verifierOnlyCircuitData.ConstantSigmasCap))
+		}
+
+		witness := TestFriCapHeightCircuit{
+			ProofWithPis:            proofWithPis,
+			VerifierOnlyCircuitData: verifierOnlyCircuitData,
+			PlonkZeta:               toQuadraticExtensionVariable(challenges.PlonkZeta),
+			FriChallenges:           toFriChallenges(challenges),
+			CommonCircuitData:       commonCircuitData,
+		}
+		circuit := witness
+
+		err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, "cap height %d", capHeight)
+
+		// The proofs must not verify against other entries of the lowered caps.
+		if capHeight > 0 {
+			wiresCap := witness.ProofWithPis.Proof.WiresCap
+			wiresCap[0], wiresCap[1] = wiresCap[1], wiresCap[0]
+			err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+			assert.Error(err, "cap height %d", capHeight)
+		}
+	}
+}
+
+func TestFriVerificationArities(t *testing.T) {
+	if _, err := os.Stat("../testdata/fri_arities"); os.IsNotExist(err) {
+		t.Fatal("no fri_arities fixture, see testdata/fri_arities.md")
+	}
+
+	assert := test.NewAssert(t)
+
+	plonky2Circuit := "fri_arities"
+	commonCircuitData := types.ReadCommonCircuitData("../testdata/" + plonky2Circuit + "/common_circuit_data.json")
+	proofWithPisRaw := types.ReadProofWithPublicInputs("../testdata/" + plonky2Circuit + "/proof_with_public_inputs.json")
+	verifierOnlyCircuitDataRaw := types.ReadVerifierOnlyCircuitData("../testdata/" + plonky2Circuit + "/verifier_only_circuit_data.json")
+
+	arities := map[uint64]bool{}
+	for _, arityBits := range commonCircuitData.FriParams.ReductionArityBits {
+		arities[arityBits] = true
+	}
+	if len(arities) < 2 {
+		t.Fatalf("the fri_arities fixture only folds with arity bits %v", commonCircuitData.FriParams.ReductionArityBits)
+	}
+
+	nativeVerifier := native.NewVerifier(commonCircuitData)
+	err := nativeVerifier.Verify(proofWithPisRaw, verifierOnlyCircuitDataRaw)
+	assert.NoError(err)
+	challenges, err := nativeVerifier.GetChallenges(proofWithPisRaw, verifierOnlyCircuitDataRaw)
+	assert.NoError(err)
+
+	proofWithPis, err := variables.DeserializeProofWithPublicInputs(proofWithPisRaw)
+	assert.NoError(err)
+	verifierOnlyCircuitData, err := variables.DeserializeVerifierOnlyCircuitData(verifierOnlyCircuitDataRaw)
+	assert.NoError(err)
+
+	witness := TestFriCapHeightCircuit{
+		ProofWithPis:            proofWithPis,
+		VerifierOnlyCircuitData: verifierOnlyCircuitData,
+		PlonkZeta:               toQuadraticExtensionVariable(challenges.PlonkZeta),
+		FriChallenges:           toFriChallenges(challenges),
+		CommonCircuitData:       commonCircuitData,
+	}
+	circuit := witness
+
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+// Unlike TestFriVerificationToCapHeights, verifies proofs that plonky2 generated at cap heights other
+// than 4, so that both the re-capping of readFixtureAtCapHeight and the transcript of the lower caps
+// are checked against plonky2.
+func TestFriVerificationCapHeightFixtures(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, capHeight := range []uint64{0, 2} {
+		plonky2Circuit := fmt.Sprintf("cap_height_%d", capHeight)
+		if _, err := os.Stat("../testdata/" + plonky2Circuit); os.IsNotExist(err) {
+			t.Fatalf("no %s fixture, see testdata/cap_heights.md", plonky2Circuit)
+		}
+
+		commonCircuitData := types.ReadCommonCircuitData("../testdata/" + plonky2Circuit + "/common_circuit_data.json")
+		proofWithPisRaw := types.ReadProofWithPublicInputs("../testdata/" + plonky2Circuit + "/proof_with_public_inputs.json")
+		verifierOnlyCircuitDataRaw := types.ReadVerifierOnlyCircuitData("../testdata/" + plonky2Circuit + "/verifier_only_circuit_data.json")
+		if commonCircuitData.FriParams.Config.CapHeight != capHeight {
+			t.Fatalf("the %s fixture has cap height %d", plonky2Circuit, commonCircuitData.FriParams.Config.CapHeight)
+		}
+
+		nativeVerifier := native.NewVerifier(commonCircuitData)
+		err := nativeVerifier.Verify(proofWithPisRaw, verifierOnlyCircuitDataRaw)
+		assert.NoError(err, "cap height %d", capHeight)
+		challenges, err := nativeVerifier.GetChallenges(proofWithPisRaw, verifierOnlyCircuitDataRaw)
+		assert.NoError(err)
+
+		proofWithPis, err := variables.DeserializeProofWithPublicInputs(proofWithPisRaw)
+		assert.NoError(err)
+		verifierOnlyCircuitData, err := variables.DeserializeVerifierOnlyCircuitData(verifierOnlyCircuitDataRaw)
+		assert.NoError(err)
+
+		witness := TestFriCapHeightCircuit{
+			ProofWithPis:            proofWithPis,
+			VerifierOnlyCircuitData: verifierOnlyCircuitData,
+			PlonkZeta:               toQuadraticExtensionVariable(challenges.PlonkZeta),
+			FriChallenges:           toFriChallenges(challenges),
+			CommonCircuitData:       commonCircuitData,
+		}
+		circuit := witness
+
+		err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, "cap height %d", capHeight)
+	}
+}
@@ -75,6 +75,12 @@ func numZSPartialProductsPolys(c *types.CommonCircuitData) uint64 {
 	return c.Config.NumChallenges * (1 + c.NumPartialProducts)
 }
 
+// The lookup polynomials are committed to within the zs/partial products oracle, after the zs and
+// partial products.
+func lookupRange(c *types.CommonCircuitData) (uint64, uint64) {
+	return numZSPartialProductsPolys(c), numZSPartialProductsPolys(c) + c.NumAllLookupPolys()
+}
+
 func numQuotientPolys(c *types.CommonCircuitData) uint64 {
 	return c.Config.NumChallenges * c.QuotientDegreeFactor
 }
@@ -111,6 +117,16 @@ func friQuotientPolys(c *types.CommonCircuitData) []PolynomialInfo {
 	)
 }
 
+func friLookupPolys(c *types.CommonCircuitData) []PolynomialInfo {
+	lookupStart, lookupEnd := lookupRange(c)
+	return polynomialInfoFromRange(
+		c,
+		ZS_PARTIAL_PRODUCTS.index,
+		lookupStart,
+		lookupEnd,
+	)
+}
+
 func friZSPolys(c *types.CommonCircuitData) []PolynomialInfo {
 	return polynomialInfoFromRange(
 		c,
@@ -131,7 +147,7 @@ func friOracles(c *types.CommonCircuitData) []OracleInfo {
 			Blinding: WIRES.blinding,
 		},
 		{
-			NumPolys: numZSPartialProductsPolys(c),
+			NumPolys: numZSPartialProductsPolys(c) + c.NumAllLookupPolys(),
 			Blinding: ZS_PARTIAL_PRODUCTS.blinding,
 		},
 		{
@@ -147,6 +163,16 @@ func friAllPolys(c *types.CommonCircuitData) []PolynomialInfo {
 	returnArr = append(returnArr, friWirePolys(c)...)
 	returnArr = append(returnArr, friZSPartialProductsPolys(c)...)
 	returnArr = append(returnArr, friQuotientPolys(c)...)
+	returnArr = append(returnArr, friLookupPolys(c)...)
+
+	return returnArr
+}
+
+// The polynomials opened at g * zeta.
+func friNextBatchPolys(c *types.CommonCircuitData) []PolynomialInfo {
+	returnArr := make([]PolynomialInfo, 0)
+	returnArr = append(returnArr, friZSPolys(c)...)
+	returnArr = append(returnArr, friLookupPolys(c)...)
 
 	return returnArr
 }
@@ -165,8 +191,6 @@ func assertNoncanonicalIndicesOK(friParams types.FriParams) {
 // This does not add any constraints, it is just a sanity check on the shapes of the proof variable
 // and given FriParams. It's a 1-1 port of validate_fri_proof_shape from fri::validate_shape in plonky2
 func validateFriProofShape(proof *variables.FriProof, instance InstanceInfo, params *types.FriParams) {
-	const SALT_SIZE = 4
-
 	commitPhaseMerkleCaps := proof.CommitPhaseMerkleCaps
 	queryRoundProofs := proof.QueryRoundProofs
 	finalPoly := proof.FinalPoly
@@ -188,11 +212,7 @@ func validateFriProofShape(proof *variables.FriProof, instance InstanceInfo, par
 			leaf := evalProof.Elements
 			merkleProof := evalProof.MerkleProof
 			oracle := instance.Oracles[i]
-			salt_size := 0
-			if oracle.Blinding && params.Hiding {
-				salt_size = SALT_SIZE
-			}
-			if len(leaf) != (int(oracle.NumPolys) + salt_size) {
+			if uint64(len(leaf)) != oracle.NumPolys+params.SaltSize(oracle.Blinding) {
 				panic("eval proof leaf length doesn't match oracle info")
 			}
 			if len(merkleProof.Siblings)+int(capHeight) != params.LdeBits() {
@@ -0,0 +1,113 @@
+package fri
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
+	"github.com/succinctlabs/gnark-plonky2-verifier/variables"
+)
+
+const testMerkleTreeHeight = 6
+
+type TestMerkleProofToCapCircuit struct {
+	LeafData  []gl.Variable
+	LeafIndex frontend.Variable
+	MerkleCap variables.FriMerkleCap
+	Proof     variables.FriMerkleProof
+}
+
+func (circuit *TestMerkleProofToCapCircuit) Define(api frontend.API) error {
+	friChip := NewChip(api, nil, nil)
+
+	leafIndexBits := api.ToBinary(circuit.LeafIndex, testMerkleTreeHeight)
+	capHeight := testMerkleTreeHeight - len(circuit.Proof.Siblings)
+	capIndexBits := leafIndexBits[testMerkleTreeHeight-capHeight:]
+
+	friChip.verifyMerkleProofToCapWithCapIndex(circuit.LeafData, leafIndexBits, capIndexBits, circuit.MerkleCap, &circuit.Proof)
+	return nil
+}
+
+// Builds a merkle tree of random leaves, and returns the leaves and its cap at capHeight.
+func buildMerkleTree(rng *rand.Rand, capHeight int) ([][]goldilocks.Element, [][]fr.Element) {
+	hasher := poseidon.NewBN254Hasher()
+
+	leaves := make([][]goldilocks.Element, 1<<testMerkleTreeHeight)
+	layer := make([]fr.Element, len(leaves))
+	for i := range leaves {
+		// Use more than 3 elements, so that the leaves are hashed.
+		leaves[i] = make([]goldilocks.Element, 5)
+		for j := range leaves[i] {
+			leaves[i][j] = goldilocks.NewElement(rng.Uint64())
+		}
+		layer[i] = hasher.HashOrNoop(leaves[i])
+	}
+
+	layers := [][]fr.Element{layer}
+	for len(layer) > 1<<capHeight {
+		nextLayer := make([]fr.Element, len(layer)/2)
+		for i := range nextLayer {
+			nextLayer[i] = hasher.TwoToOne(layer[2*i], layer[2*i+1])
+		}
+		layer = nextLayer
+		layers = append(layers, layer)
+	}
+
+	return leaves, layers
+}
+
+func TestMerkleProofToCapHeights(t *testing.T) {
+	// The commit based range checker can't pick its base width for circuits this small.
+	t.Setenv("USE_BIT_DECOMPOSITION_RANGE_CHECK", "true")
+
+	assert := test.NewAssert(t)
+	rng := rand.New(rand.NewSource(0))
+
+	for capHeight := 0; capHeight <= testMerkleTreeHeight; capHeight++ {
+		leaves, layers := buildMerkleTree(rng, capHeight)
+		merkleCap := layers[len(layers)-1]
+		numSiblings := testMerkleTreeHeight - capHeight
+
+		circuit := TestMerkleProofToCapCircuit{
+			LeafData:  make([]gl.Variable, len(leaves[0])),
+			MerkleCap: variables.NewFriMerkleCap(uint64(capHeight)),
+			Proof:     variables.NewFriMerkleProof(uint64(numSiblings)),
+		}
+
+		for _, leafIndex := range []int{0, 1, 37, len(leaves) - 1} {
+			witness := TestMerkleProofToCapCircuit{
+				LeafData:  make([]gl.Variable, len(leaves[leafIndex])),
+				LeafIndex: leafIndex,
+				MerkleCap: make(variables.FriMerkleCap, len(merkleCap)),
+				Proof:     variables.NewFriMerkleProof(uint64(numSiblings)),
+			}
+			for i, element := range leaves[leafIndex] {
+				witness.LeafData[i] = gl.NewVariable(element.Uint64())
+			}
+			for i, entry := range merkleCap {
+				witness.MerkleCap[i] = entry.String()
+			}
+			index := leafIndex
+			for i := 0; i < numSiblings; i++ {
+				witness.Proof.Siblings[i] = layers[i][index^1].String()
+				index >>= 1
+			}
+
+			err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+			assert.NoError(err, "cap height %d, leaf %d", capHeight, leafIndex)
+
+			// The same proof must not verify against a different cap entry.
+			if len(merkleCap) > 1 {
+				witness.MerkleCap[index], witness.MerkleCap[index^1] = witness.MerkleCap[index^1], witness.MerkleCap[index]
+				err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+				assert.Error(err, "cap height %d, leaf %d", capHeight, leafIndex)
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package fri
+
+import (
+	"math/big"
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+)
+
+// Checks a single FRI step of any arity.  The evals are those of a polynomial of degree less
+// than the arity, so the selected eval must be its value at X and the folded evaluation must be
+// its value at Beta.
+type TestQueryStepCircuit struct {
+	X                 gl.Variable
+	XIndexWithinCoset frontend.Variable
+	Evals             []gl.QuadraticExtensionVariable
+	Beta              gl.QuadraticExtensionVariable
+	ExpectedEval      gl.QuadraticExtensionVariable
+	ExpectedFolded    gl.QuadraticExtensionVariable
+}
+
+func (circuit *TestQueryStepCircuit) Define(api frontend.API) error {
+	friChip := NewChip(api, nil, nil)
+
+	arityBits := uint64(bits.Len(uint(len(circuit.Evals))) - 1)
+	xIndexWithinCosetBits := api.ToBinary(circuit.XIndexWithinCoset, int(arityBits))
+
+	eval := friChip.selectEval(xIndexWithinCosetBits, circuit.Evals)
+	friChip.gl.AssertIsEqualExtension(eval, circuit.ExpectedEval)
+
+	folded := friChip.computeEvaluation(circuit.X, xIndexWithinCosetBits, arityBits, circuit.Evals, circuit.Beta)
+	friChip.gl.AssertIsEqualExtension(folded, circuit.ExpectedFolded)
+
+	return nil
+}
+
+func randomQuadraticExtension(rng *rand.Rand) gl.QuadraticExtension {
+	return gl.NewQuadraticExtension(goldilocks.NewElement(rng.Uint64()), goldilocks.NewElement(rng.Uint64()))
+}
+
+func quadraticExtensionToVariable(e gl.QuadraticExtension) gl.QuadraticExtensionVariable {
+	return gl.NewQuadraticExtensionVariable(gl.NewVariable(e[0].Uint64()), gl.NewVariable(e[1].Uint64()))
+}
+
+func TestQueryStepArities(t *testing.T) {
+	// The commit based range checker can't pick its base width for circuits this small.
+	t.Setenv("USE_BIT_DECOMPOSITION_RANGE_CHECK", "true")
+
+	assert := test.NewAssert(t)
+	rng := rand.New(rand.NewSource(0))
+
+	for arityBits := uint64(1); arityBits <= 5; arityBits++ {
+		arity := uint64(1) << arityBits
+		g := gl.PrimitiveRootOfUnity(arityBits)
+
+		poly := make([]gl.QuadraticExtension, arity)
+		for i := range poly {
+			poly[i] = randomQuadraticExtension(rng)
+		}
+
+		x := goldilocks.NewElement(rng.Uint64())
+		xIndexWithinCoset := rng.Uint64() % arity
+		beta := randomQuadraticExtension(rng)
+
+		// x is the point of the coset at xIndexWithinCoset, and the points are in bit reversed order.
+		var cosetStart goldilocks.Element
+		cosetStart.Exp(g, new(big.Int).SetUint64(bits.Reverse64(xIndexWithinCoset)>>(64-arityBits)))
+		cosetStart.Inverse(&cosetStart)
+		cosetStart.Mul(&cosetStart, &x)
+
+		circuit := TestQueryStepCircuit{Evals: make([]gl.QuadraticExtensionVariable, arity)}
+		witness := TestQueryStepCircuit{
+			X:                 gl.NewVariable(x.Uint64()),
+			XIndexWithinCoset: xIndexWithinCoset,
+			Evals:             make([]gl.QuadraticExtensionVariable, arity),
+			Beta:              quadraticExtensionToVariable(beta),
+			ExpectedEval:      quadraticExtensionToVariable(gl.ReduceWithPowers(poly, gl.NewQuadraticExtensionFromBase(x))),
+			ExpectedFolded:    quadraticExtensionToVariable(gl.ReduceWithPowers(poly, beta)),
+		}
+		for i := uint64(0); i < arity; i++ {
+			var point goldilocks.Element
+			point.Exp(g, new(big.Int).SetUint64(bits.Reverse64(i)>>(64-arityBits)))
+			point.Mul(&point, &cosetStart)
+			witness.Evals[i] = quadraticExtensionToVariable(gl.ReduceWithPowers(poly, gl.NewQuadraticExtensionFromBase(point)))
+		}
+
+		err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, "arity bits %d", arityBits)
+	}
+}
@@ -0,0 +1,98 @@
+package goldilocks
+
+import "github.com/consensys/gnark-crypto/field/goldilocks"
+
+// An element of the extension algebra over the quadratic extension, computed natively (out of circuit).
+type QuadraticExtensionAlgebra [D]QuadraticExtension
+
+func (p QuadraticExtension) ToQuadraticExtensionAlgebra() QuadraticExtensionAlgebra {
+	return QuadraticExtensionAlgebra{p, ZeroQuadraticExtension()}
+}
+
+func ZeroQuadraticExtensionAlgebra() QuadraticExtensionAlgebra {
+	return ZeroQuadraticExtension().ToQuadraticExtensionAlgebra()
+}
+
+func OneQuadraticExtensionAlgebra() QuadraticExtensionAlgebra {
+	return OneQuadraticExtension().ToQuadraticExtensionAlgebra()
+}
+
+func (a QuadraticExtensionAlgebra) Add(b QuadraticExtensionAlgebra) QuadraticExtensionAlgebra {
+	var sum QuadraticExtensionAlgebra
+	for i := 0; i < D; i++ {
+		sum[i] = a[i].Add(b[i])
+	}
+	return sum
+}
+
+func (a QuadraticExtensionAlgebra) Sub(b QuadraticExtensionAlgebra) QuadraticExtensionAlgebra {
+	var diff QuadraticExtensionAlgebra
+	for i := 0; i < D; i++ {
+		diff[i] = a[i].Sub(b[i])
+	}
+	return diff
+}
+
+func (a QuadraticExtensionAlgebra) Mul(b QuadraticExtensionAlgebra) QuadraticExtensionAlgebra {
+	w := goldilocks.NewElement(W)
+
+	var product QuadraticExtensionAlgebra
+	for i := 0; i < D; i++ {
+		product[i] = ZeroQuadraticExtension()
+	}
+
+	for i := 0; i < D; i++ {
+		for j := 0; j < D; j++ {
+			term := a[i].Mul(b[j])
+			if i+j >= D {
+				term = term.ScalarMul(w)
+			}
+			idx := (i + j) % D
+			product[idx] = product[idx].Add(term)
+		}
+	}
+
+	return product
+}
+
+func (a QuadraticExtensionAlgebra) ScalarMul(b QuadraticExtension) QuadraticExtensionAlgebra {
+	var product QuadraticExtensionAlgebra
+	for i := 0; i < D; i++ {
+		product[i] = b.Mul(a[i])
+	}
+	return product
+}
+
+func PartialInterpolateExtAlgebra(
+	domain []goldilocks.Element,
+	values []QuadraticExtensionAlgebra,
+	barycentricWeights []goldilocks.Element,
+	point QuadraticExtensionAlgebra,
+	initialEval QuadraticExtensionAlgebra,
+	initialPartialProd QuadraticExtensionAlgebra,
+) (QuadraticExtensionAlgebra, QuadraticExtensionAlgebra) {
+	n := len(values)
+	if n == 0 {
+		panic("Cannot interpolate with no values")
+	}
+	if n != len(domain) {
+		panic("Domain and values must have the same length")
+	}
+	if n != len(barycentricWeights) {
+		panic("Domain and barycentric weights must have the same length")
+	}
+
+	newEval := initialEval
+	newPartialProd := initialPartialProd
+	for i := 0; i < n; i++ {
+		val := values[i]
+		xAlgebra := NewQuadraticExtensionFromBase(domain[i]).ToQuadraticExtensionAlgebra()
+		weight := NewQuadraticExtensionFromBase(barycentricWeights[i])
+		term := point.Sub(xAlgebra)
+		weightedVal := val.ScalarMul(weight)
+		newEval = newEval.Mul(term).Add(weightedVal.Mul(newPartialProd))
+		newPartialProd = newPartialProd.Mul(term)
+	}
+
+	return newEval, newPartialProd
+}
@@ -0,0 +1,149 @@
+package goldilocks
+
+// This file implements the quadratic extension of the Goldilocks field outside of the circuit. It
+// mirrors the in-circuit operations in quadratic_extension.go, and is used to verify plonky2 proofs
+// natively (e.g. before paying for the generation of a gnark proof).
+
+import (
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+// A quadratic extension element of the Goldilocks field, a[0] + a[1] * u where u^2 = W.
+type QuadraticExtension [2]goldilocks.Element
+
+func NewQuadraticExtension(a goldilocks.Element, b goldilocks.Element) QuadraticExtension {
+	return QuadraticExtension{a, b}
+}
+
+// Embeds a Goldilocks field element into the quadratic extension.
+func NewQuadraticExtensionFromBase(a goldilocks.Element) QuadraticExtension {
+	return QuadraticExtension{a, goldilocks.NewElement(0)}
+}
+
+// Embeds a uint64 into the quadratic extension.  The value is reduced modulo the Goldilocks modulus.
+func NewQuadraticExtensionFromUint64(a uint64) QuadraticExtension {
+	return NewQuadraticExtensionFromBase(goldilocks.NewElement(a))
+}
+
+func ZeroQuadraticExtension() QuadraticExtension {
+	return NewQuadraticExtensionFromUint64(0)
+}
+
+func OneQuadraticExtension() QuadraticExtension {
+	return NewQuadraticExtensionFromUint64(1)
+}
+
+// Adds two quadratic extension elements.
+func (a QuadraticExtension) Add(b QuadraticExtension) QuadraticExtension {
+	var c QuadraticExtension
+	c[0].Add(&a[0], &b[0])
+	c[1].Add(&a[1], &b[1])
+	return c
+}
+
+// Subtracts two quadratic extension elements.
+func (a QuadraticExtension) Sub(b QuadraticExtension) QuadraticExtension {
+	var c QuadraticExtension
+	c[0].Sub(&a[0], &b[0])
+	c[1].Sub(&a[1], &b[1])
+	return c
+}
+
+// Multiplies two quadratic extension elements.
+func (a QuadraticExtension) Mul(b QuadraticExtension) QuadraticExtension {
+	w := goldilocks.NewElement(W)
+
+	var c0o0, c0o1, c1o0, c1o1 goldilocks.Element
+	c0o0.Mul(&a[0], &b[0])
+	c0o1.Mul(&a[1], &b[1])
+	c0o1.Mul(&c0o1, &w)
+	c1o0.Mul(&a[0], &b[1])
+	c1o1.Mul(&a[1], &b[0])
+
+	var c QuadraticExtension
+	c[0].Add(&c0o0, &c0o1)
+	c[1].Add(&c1o0, &c1o1)
+	return c
+}
+
+// Multiplies a quadratic extension element by a Goldilocks field element.
+func (a QuadraticExtension) ScalarMul(b goldilocks.Element) QuadraticExtension {
+	var c QuadraticExtension
+	c[0].Mul(&a[0], &b)
+	c[1].Mul(&a[1], &b)
+	return c
+}
+
+// Computes the inverse of a quadratic extension element.  The inverse of zero is zero.
+func (a QuadraticExtension) Inverse() QuadraticExtension {
+	// a^r, where r = 1 + p, is in the base field and a^(r - 1) is the Frobenius automorphism of a.
+	dthRoot := goldilocks.NewElement(DTH_ROOT)
+	aPowRMinus1 := QuadraticExtension{a[0]}
+	aPowRMinus1[1].Mul(&a[1], &dthRoot)
+	aPowR := aPowRMinus1.Mul(a)
+
+	var aPowRInv goldilocks.Element
+	aPowRInv.Inverse(&aPowR[0])
+	return aPowRMinus1.ScalarMul(aPowRInv)
+}
+
+// Divides two quadratic extension elements.  The second return value is false if b is zero.
+func (a QuadraticExtension) Div(b QuadraticExtension) (QuadraticExtension, bool) {
+	if b.IsZero() {
+		return ZeroQuadraticExtension(), false
+	}
+	return a.Mul(b.Inverse()), true
+}
+
+// Exponentiates a quadratic extension element to some exponent.
+func (a QuadraticExtension) Exp(exponent uint64) QuadraticExtension {
+	current := a
+	product := OneQuadraticExtension()
+
+	for i := 0; i < bits.Len64(exponent); i++ {
+		if i != 0 {
+			current = current.Mul(current)
+		}
+		if (exponent >> i & 1) != 0 {
+			product = product.Mul(current)
+		}
+	}
+
+	return product
+}
+
+func (a QuadraticExtension) IsZero() bool {
+	return a[0].IsZero() && a[1].IsZero()
+}
+
+func (a QuadraticExtension) Equal(b QuadraticExtension) bool {
+	return a[0].Equal(&b[0]) && a[1].Equal(&b[1])
+}
+
+func (a QuadraticExtension) String() string {
+	return "[" + a[0].String() + ", " + a[1].String() + "]"
+}
+
+// Reduces a list of extension field terms with a scalar power (e.g. computes
+// terms[0] + terms[1] * scalar + terms[2] * scalar^2 + ...).
+func ReduceWithPowers(terms []QuadraticExtension, scalar QuadraticExtension) QuadraticExtension {
+	sum := ZeroQuadraticExtension()
+	for i := len(terms) - 1; i >= 0; i-- {
+		sum = sum.Mul(scalar).Add(terms[i])
+	}
+	return sum
+}
+
+func ElementArrayToUint64Array(input []goldilocks.Element) []uint64 {
+	output := make([]uint64, len(input))
+	for i := 0; i < len(input); i++ {
+		output[i] = input[i].Uint64()
+	}
+	return output
+}
+
+func QuadraticExtensionToUint64Array(input QuadraticExtension) []uint64 {
+	return ElementArrayToUint64Array(input[:])
+}
@@ -0,0 +1,32 @@
+package goldilocks
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+func TestQuadraticExtensionMulNative(t *testing.T) {
+	operand1 := NewQuadraticExtension(goldilocks.NewElement(4994088319481652598), goldilocks.NewElement(16489566008211790727))
+	operand2 := NewQuadraticExtension(goldilocks.NewElement(3797605683985595697), goldilocks.NewElement(13424401189265534004))
+	expectedResult := NewQuadraticExtension(goldilocks.NewElement(15052319864161058789), goldilocks.NewElement(16841416332519902625))
+
+	if actualRes := operand1.Mul(operand2); !actualRes.Equal(expectedResult) {
+		t.Errorf("expected %s, got %s", expectedResult, actualRes)
+	}
+}
+
+func TestQuadraticExtensionDivNative(t *testing.T) {
+	operand1 := NewQuadraticExtension(goldilocks.NewElement(4994088319481652598), goldilocks.NewElement(16489566008211790727))
+	operand2 := NewQuadraticExtension(goldilocks.NewElement(7166004739148609569), goldilocks.NewElement(14655965871663555016))
+	expectedResult := NewQuadraticExtension(goldilocks.NewElement(15052319864161058789), goldilocks.NewElement(16841416332519902625))
+
+	actualRes, hasQuotient := operand1.Div(operand2)
+	if !hasQuotient || !actualRes.Equal(expectedResult) {
+		t.Errorf("expected %s, got %s", expectedResult, actualRes)
+	}
+
+	if _, hasQuotient := operand1.Div(ZeroQuadraticExtension()); hasQuotient {
+		t.Errorf("expected division by zero to fail")
+	}
+}
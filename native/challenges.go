@@ -0,0 +1,107 @@
+package native
+
+import (
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/challenger"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/plonk/gates"
+	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+)
+
+type friChallenges struct {
+	friAlpha        gl.QuadraticExtension
+	friBetas        []gl.QuadraticExtension
+	friPowResponse  goldilocks.Element
+	friQueryIndices []goldilocks.Element
+}
+
+type proofChallenges struct {
+	plonkBetas    []goldilocks.Element
+	plonkGammas   []goldilocks.Element
+	plonkAlphas   []goldilocks.Element
+	plonkDeltas   []goldilocks.Element
+	plonkZeta     gl.QuadraticExtension
+	friChallenges friChallenges
+}
+
+func uint64ArrayToElements(values []uint64) []goldilocks.Element {
+	elements := make([]goldilocks.Element, len(values))
+	for i, value := range values {
+		elements[i] = goldilocks.NewElement(value)
+	}
+	return elements
+}
+
+func uint64ArrayToQuadraticExtension(values []uint64) gl.QuadraticExtension {
+	return gl.NewQuadraticExtension(goldilocks.NewElement(values[0]), goldilocks.NewElement(values[1]))
+}
+
+// The challenges produced by challenger.Challenger are all canonical, so the conversion is lossless.
+func proofChallengesFromRaw(raw types.ProofChallengesRaw) proofChallenges {
+	friBetas := make([]gl.QuadraticExtension, len(raw.FriChallenges.FriBetas))
+	for i, friBeta := range raw.FriChallenges.FriBetas {
+		friBetas[i] = uint64ArrayToQuadraticExtension(friBeta)
+	}
+
+	return proofChallenges{
+		plonkBetas:  uint64ArrayToElements(raw.PlonkBetas),
+		plonkGammas: uint64ArrayToElements(raw.PlonkGammas),
+		plonkAlphas: uint64ArrayToElements(raw.PlonkAlphas),
+		plonkDeltas: uint64ArrayToElements(raw.PlonkDeltas),
+		plonkZeta:   uint64ArrayToQuadraticExtension(raw.PlonkZeta),
+		friChallenges: friChallenges{
+			friAlpha:        uint64ArrayToQuadraticExtension(raw.FriChallenges.FriAlpha),
+			friBetas:        friBetas,
+			friPowResponse:  goldilocks.NewElement(raw.FriChallenges.FriPowResponse),
+			friQueryIndices: uint64ArrayToElements(raw.FriChallenges.FriQueryIndices),
+		},
+	}
+}
+
+// The out of circuit counterpart of verifier.VerifierChip.GetChallenges.
+func (v *Verifier) getChallenges(
+	proof *proof,
+	publicInputsHash poseidon.GoldilocksNativeHashOut,
+	verifierData *verifierOnlyCircuitData,
+) types.ProofChallengesRaw {
+	numChallenges := v.commonData.Config.NumChallenges
+	challenger := challenger.NewChallenger()
+
+	challenger.ObserveBN254Hash(verifierData.circuitDigest)
+	challenger.ObserveHash(publicInputsHash)
+	challenger.ObserveCap(proof.wiresCap)
+	plonkBetas := challenger.GetNChallenges(numChallenges)
+	plonkGammas := challenger.GetNChallenges(numChallenges)
+
+	// The lookup argument reuses the betas and gammas as its first challenges.
+	var plonkDeltas []goldilocks.Element
+	if v.commonData.NumLookupPolys != 0 {
+		plonkDeltas = append(plonkDeltas, plonkBetas...)
+		plonkDeltas = append(plonkDeltas, plonkGammas...)
+		plonkDeltas = append(plonkDeltas, challenger.GetNChallenges((gates.NUM_COINS_LOOKUP-2)*numChallenges)...)
+	}
+
+	// The zs/partial products cap also commits to the lookup polynomials.
+	challenger.ObserveCap(proof.plonkZsPartialProductsCap)
+	plonkAlphas := challenger.GetNChallenges(numChallenges)
+
+	challenger.ObserveCap(proof.quotientPolysCap)
+	plonkZeta := challenger.GetExtensionChallenge()
+
+	challenger.ObserveOpenings(toOpenings(&proof.openings))
+
+	return types.ProofChallengesRaw{
+		PlonkBetas:  gl.ElementArrayToUint64Array(plonkBetas),
+		PlonkGammas: gl.ElementArrayToUint64Array(plonkGammas),
+		PlonkAlphas: gl.ElementArrayToUint64Array(plonkAlphas),
+		PlonkDeltas: gl.ElementArrayToUint64Array(plonkDeltas),
+		PlonkZeta:   gl.QuadraticExtensionToUint64Array(plonkZeta),
+		FriChallenges: challenger.GetFriChallenges(
+			proof.openingProof.commitPhaseMerkleCaps,
+			proof.openingProof.finalPoly,
+			proof.openingProof.powWitness,
+			v.commonData.Config.FriConfig,
+		),
+	}
+}
@@ -0,0 +1,321 @@
+package native
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+)
+
+type polynomialInfo struct {
+	oracleIndex     uint64
+	polynomialIndex uint64
+}
+
+type batchInfo struct {
+	point       gl.QuadraticExtension
+	polynomials []polynomialInfo
+}
+
+func polynomialInfoFromRange(oracleIdx uint64, startPolyIdx uint64, endPolyIdx uint64) []polynomialInfo {
+	polynomials := make([]polynomialInfo, 0, endPolyIdx-startPolyIdx)
+	for i := startPolyIdx; i < endPolyIdx; i++ {
+		polynomials = append(polynomials, polynomialInfo{oracleIndex: oracleIdx, polynomialIndex: i})
+	}
+	return polynomials
+}
+
+// The out of circuit counterpart of fri.Chip.GetInstance.  All of the polynomials are opened at
+// zeta, and the Z polynomials are also opened at g * zeta.
+func (v *Verifier) friInstance(zeta gl.QuadraticExtension) []batchInfo {
+	zetaPolys := []polynomialInfo{}
+	for oracleIdx, numPolys := range v.commonData.OracleNumPolys() {
+		zetaPolys = append(zetaPolys, polynomialInfoFromRange(uint64(oracleIdx), 0, numPolys)...)
+	}
+
+	g := gl.PrimitiveRootOfUnity(v.commonData.DegreeBits)
+	zetaNext := zeta.ScalarMul(g)
+	zetaNextPolys := polynomialInfoFromRange(2, 0, v.commonData.Config.NumChallenges)
+
+	// The lookup polynomials are opened at both points.  They are committed to after the zs and
+	// partial products, so they come last within the polynomials of the zs/partial products oracle.
+	lookupStart := v.commonData.OracleNumPolys()[2] - v.commonData.NumAllLookupPolys()
+	lookupPolys := polynomialInfoFromRange(2, lookupStart, lookupStart+v.commonData.NumAllLookupPolys())
+	zetaPolys = append(zetaPolys, lookupPolys...)
+	zetaNextPolys = append(zetaNextPolys, lookupPolys...)
+
+	return []batchInfo{
+		{point: zeta, polynomials: zetaPolys},
+		{point: zetaNext, polynomials: zetaNextPolys},
+	}
+}
+
+// The out of circuit counterpart of fri.Chip.ToOpenings.
+func toOpenings(c *openingSet) [][]gl.QuadraticExtension {
+	values := []gl.QuadraticExtension{}
+	values = append(values, c.constants...)
+	values = append(values, c.plonkSigmas...)
+	values = append(values, c.wires...)
+	values = append(values, c.plonkZs...)
+	values = append(values, c.partialProducts...)
+	values = append(values, c.quotientPolys...)
+	values = append(values, c.lookupZs...)
+	nextValues := append([]gl.QuadraticExtension{}, c.plonkZsNext...)
+	nextValues = append(nextValues, c.lookupZsNext...)
+	return [][]gl.QuadraticExtension{values, nextValues}
+}
+
+func reverseBits(n uint64, numBits uint64) uint64 {
+	if numBits == 0 {
+		return 0
+	}
+	return bits.Reverse64(n) >> (64 - numBits)
+}
+
+func (v *Verifier) verifyMerkleProofToCapWithCapIndex(
+	leafData []goldilocks.Element,
+	leafIndex uint64,
+	capIndex uint64,
+	cap merkleCap,
+	siblings []fr.Element,
+) bool {
+	currentDigest := v.poseidonBN254.HashOrNoop(leafData)
+	for i, sibling := range siblings {
+		if (leafIndex>>i)&1 == 1 {
+			currentDigest = v.poseidonBN254.TwoToOne(sibling, currentDigest)
+		} else {
+			currentDigest = v.poseidonBN254.TwoToOne(currentDigest, sibling)
+		}
+	}
+
+	return currentDigest.Equal(&cap[capIndex])
+}
+
+func (v *Verifier) friCombineInitial(
+	instance []batchInfo,
+	initialTreesProof []friEvalProof,
+	friAlpha gl.QuadraticExtension,
+	subgroupX gl.QuadraticExtension,
+	precomputedReducedEvals []gl.QuadraticExtension,
+) (gl.QuadraticExtension, error) {
+	sum := gl.ZeroQuadraticExtension()
+
+	for i, batch := range instance {
+		evals := make([]gl.QuadraticExtension, 0, len(batch.polynomials))
+		for _, polynomial := range batch.polynomials {
+			eval := initialTreesProof[polynomial.oracleIndex].elements[polynomial.polynomialIndex]
+			evals = append(evals, gl.NewQuadraticExtensionFromBase(eval))
+		}
+
+		reducedEvals := gl.ReduceWithPowers(evals, friAlpha)
+		numerator := reducedEvals.Sub(precomputedReducedEvals[i])
+		denominator := subgroupX.Sub(batch.point)
+		quotient, hasQuotient := numerator.Div(denominator)
+		if !hasQuotient {
+			return sum, fmt.Errorf("query point coincides with the opening point of batch %d", i)
+		}
+
+		sum = friAlpha.Exp(uint64(len(evals))).Mul(sum).Add(quotient)
+	}
+
+	return sum, nil
+}
+
+func interpolate(
+	x gl.QuadraticExtension,
+	xPoints []gl.QuadraticExtension,
+	yPoints []gl.QuadraticExtension,
+	barycentricWeights []gl.QuadraticExtension,
+) gl.QuadraticExtension {
+	// If x is already within the xPoints, then its evaluation is given.
+	for i, xPoint := range xPoints {
+		if x.Equal(xPoint) {
+			return yPoints[i]
+		}
+	}
+
+	lX := gl.OneQuadraticExtension()
+	for _, xPoint := range xPoints {
+		lX = lX.Mul(x.Sub(xPoint))
+	}
+
+	sum := gl.ZeroQuadraticExtension()
+	for i, xPoint := range xPoints {
+		quotient, _ := barycentricWeights[i].Div(x.Sub(xPoint))
+		sum = sum.Add(yPoints[i].Mul(quotient))
+	}
+
+	return lX.Mul(sum)
+}
+
+func computeEvaluation(
+	x goldilocks.Element,
+	xIndexWithinCoset uint64,
+	arityBits uint64,
+	evals []gl.QuadraticExtension,
+	beta gl.QuadraticExtension,
+) gl.QuadraticExtension {
+	arity := uint64(1) << arityBits
+
+	g := gl.PrimitiveRootOfUnity(arityBits)
+	var gInv goldilocks.Element
+	gInv.Exp(g, new(big.Int).SetUint64(arity-1))
+
+	// The evaluation vector needs to be reordered first.  Permute the evals array such that each
+	// element's new index is the bit reverse of it's original index.
+	permutedEvals := make([]gl.QuadraticExtension, len(evals))
+	for i := range evals {
+		permutedEvals[reverseBits(uint64(i), arityBits)] = evals[i]
+	}
+
+	// Want `g^(arity - rev_x_index_within_coset)` as in the out-of-circuit version. Compute it
+	// as `(g^-1)^rev_x_index_within_coset`.
+	var cosetStart goldilocks.Element
+	cosetStart.Exp(gInv, new(big.Int).SetUint64(reverseBits(xIndexWithinCoset, arityBits)))
+	cosetStart.Mul(&cosetStart, &x)
+
+	xPoints := make([]gl.QuadraticExtension, len(evals))
+	xPoints[0] = gl.NewQuadraticExtensionFromBase(cosetStart)
+	for i := 1; i < len(evals); i++ {
+		xPoints[i] = xPoints[i-1].ScalarMul(g)
+	}
+
+	// The points are distinct powers of g times cosetStart, so every weight is invertible.
+	barycentricWeights := make([]gl.QuadraticExtension, len(xPoints))
+	for i := range xPoints {
+		barycentricWeights[i] = gl.OneQuadraticExtension()
+		for j := range xPoints {
+			if i != j {
+				barycentricWeights[i] = barycentricWeights[i].Mul(xPoints[i].Sub(xPoints[j]))
+			}
+		}
+		barycentricWeights[i] = barycentricWeights[i].Inverse()
+	}
+
+	return interpolate(beta, xPoints, permutedEvals, barycentricWeights)
+}
+
+func finalPolyEval(finalPoly []gl.QuadraticExtension, point gl.QuadraticExtension) gl.QuadraticExtension {
+	return gl.ReduceWithPowers(finalPoly, point)
+}
+
+func (v *Verifier) verifyQueryRound(
+	instance []batchInfo,
+	challenges *friChallenges,
+	precomputedReducedEvals []gl.QuadraticExtension,
+	initialMerkleCaps []merkleCap,
+	proof *friProof,
+	xIndex uint64,
+	roundProof *friQueryRound,
+) error {
+	friParams := v.commonData.FriParams
+	nLog := uint64(friParams.LdeBits())
+	capHeight := friParams.Config.CapHeight
+
+	xIndex &= (uint64(1) << nLog) - 1
+	capIndex := xIndex >> (nLog - capHeight)
+
+	for i, cap := range initialMerkleCaps {
+		evalsProof := roundProof.initialTreesProof[i]
+		if !v.verifyMerkleProofToCapWithCapIndex(evalsProof.elements, xIndex, capIndex, cap, evalsProof.siblings) {
+			return fmt.Errorf("invalid merkle proof for initial tree %d", i)
+		}
+	}
+
+	// `subgroup_x` is `subgroup[x_index]`, i.e., the actual field element in the domain.
+	var subgroupX goldilocks.Element
+	subgroupX.Exp(gl.PrimitiveRootOfUnity(nLog), new(big.Int).SetUint64(reverseBits(xIndex, nLog)))
+	subgroupX.Mul(&subgroupX, &gl.MULTIPLICATIVE_GROUP_GENERATOR)
+
+	oldEval, err := v.friCombineInitial(
+		instance,
+		roundProof.initialTreesProof,
+		challenges.friAlpha,
+		gl.NewQuadraticExtensionFromBase(subgroupX),
+		precomputedReducedEvals,
+	)
+	if err != nil {
+		return err
+	}
+
+	for i, arityBits := range friParams.ReductionArityBits {
+		evals := roundProof.steps[i].evals
+
+		cosetIndex := xIndex >> arityBits
+		xIndexWithinCoset := xIndex & ((uint64(1) << arityBits) - 1)
+
+		if !evals[xIndexWithinCoset].Equal(oldEval) {
+			return fmt.Errorf("fri step %d is inconsistent with the previous evaluation", i)
+		}
+
+		oldEval = computeEvaluation(subgroupX, xIndexWithinCoset, arityBits, evals, challenges.friBetas[i])
+
+		// Convert evals (array of QE) to fields by taking their 0th degree coefficients
+		fieldEvals := make([]goldilocks.Element, 0, 2*len(evals))
+		for _, eval := range evals {
+			fieldEvals = append(fieldEvals, eval[0], eval[1])
+		}
+		if !v.verifyMerkleProofToCapWithCapIndex(
+			fieldEvals,
+			cosetIndex,
+			capIndex,
+			proof.commitPhaseMerkleCaps[i],
+			roundProof.steps[i].siblings,
+		) {
+			return fmt.Errorf("invalid merkle proof for fri step %d", i)
+		}
+
+		// Update the point x to x^arity.
+		for j := uint64(0); j < arityBits; j++ {
+			subgroupX.Square(&subgroupX)
+		}
+
+		xIndex = cosetIndex
+	}
+
+	finalEval := finalPolyEval(proof.finalPoly, gl.NewQuadraticExtensionFromBase(subgroupX))
+	if !oldEval.Equal(finalEval) {
+		return fmt.Errorf("final polynomial evaluation %s does not match %s", finalEval, oldEval)
+	}
+
+	return nil
+}
+
+// The out of circuit counterpart of fri.Chip.VerifyFriProof.
+func (v *Verifier) verifyFriProof(
+	instance []batchInfo,
+	openings [][]gl.QuadraticExtension,
+	challenges *friChallenges,
+	initialMerkleCaps []merkleCap,
+	proof *friProof,
+) error {
+	// Check POW.  The response must have at least ProofOfWorkBits leading zeros, assuming a 64 bit field.
+	powBits := v.commonData.FriParams.Config.ProofOfWorkBits
+	if bits.LeadingZeros64(challenges.friPowResponse.Uint64()) < int(powBits) {
+		return fmt.Errorf("proof of work response %d does not have %d leading zeros", challenges.friPowResponse.Uint64(), powBits)
+	}
+
+	precomputedReducedEvals := make([]gl.QuadraticExtension, 0, len(openings))
+	for _, batch := range openings {
+		precomputedReducedEvals = append(precomputedReducedEvals, gl.ReduceWithPowers(batch, challenges.friAlpha))
+	}
+
+	for i, xIndex := range challenges.friQueryIndices {
+		if err := v.verifyQueryRound(
+			instance,
+			challenges,
+			precomputedReducedEvals,
+			initialMerkleCaps,
+			proof,
+			xIndex.Uint64(),
+			&proof.queryRoundProofs[i],
+		); err != nil {
+			return fmt.Errorf("fri query round %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
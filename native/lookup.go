@@ -0,0 +1,166 @@
+package native
+
+import (
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/plonk/gates"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+)
+
+// The out of circuit counterpart of plonk.PlonkChip.evalLutPoly.
+func evalLutPoly(lut types.LookupTable, deltas []goldilocks.Element, degree uint64) goldilocks.Element {
+	b := deltas[gates.LOOKUP_CHALLENGE_B]
+	delta := deltas[gates.LOOKUP_CHALLENGE_DELTA]
+
+	var eval, combo goldilocks.Element
+	for _, entry := range lut {
+		combo.SetUint64(uint64(entry[1]))
+		combo.Mul(&combo, &b)
+		combo.Add(&combo, new(goldilocks.Element).SetUint64(uint64(entry[0])))
+		eval.Mul(&eval, &delta)
+		eval.Add(&eval, &combo)
+	}
+
+	for i := uint64(len(lut)); i < degree; i++ {
+		eval.Mul(&eval, &delta)
+	}
+
+	return eval
+}
+
+// Computes prod_{j in [start, end), j != skip} (alpha - combos[j]).
+func lookupProduct(alpha gl.QuadraticExtension, combos []gl.QuadraticExtension, start uint64, end uint64, skip uint64) gl.QuadraticExtension {
+	product := gl.OneQuadraticExtension()
+	for j := start; j < end; j++ {
+		if j != skip {
+			product = product.Mul(alpha.Sub(combos[j]))
+		}
+	}
+	return product
+}
+
+// The out of circuit counterpart of plonk.PlonkChip.checkLookupConstraints.
+func (v *Verifier) checkLookupConstraints(
+	wires []gl.QuadraticExtension,
+	localLookupZs []gl.QuadraticExtension,
+	nextLookupZs []gl.QuadraticExtension,
+	lookupSelectors []gl.QuadraticExtension,
+	deltas []goldilocks.Element,
+) []gl.QuadraticExtension {
+	numRoutedWires := v.commonData.Config.NumRoutedWires
+	numLuSlots := gates.LookupGateNumSlots(numRoutedWires)
+	numLutSlots := gates.LookupTableGateNumSlots(numRoutedWires)
+	luDegree := v.commonData.QuotientDegreeFactor - 1
+	numSldcPolys := uint64(len(localLookupZs) - 1)
+	lutDegree := (numLutSlots + numSldcPolys - 1) / numSldcPolys
+
+	constraints := make([]gl.QuadraticExtension, 0, 4+len(v.commonData.Luts)+2*int(numSldcPolys))
+
+	// RE is the first polynomial stored, the partial Sums and LDCs are stored in the rest.
+	zRe := localLookupZs[0]
+	nextZRe := nextLookupZs[0]
+	zXLookupSldcs := localLookupZs[1 : numSldcPolys+1]
+	zGxLookupSldcs := nextLookupZs[1 : numSldcPolys+1]
+
+	challengeA := deltas[gates.LOOKUP_CHALLENGE_A]
+	challengeB := deltas[gates.LOOKUP_CHALLENGE_B]
+	challengeAlpha := gl.NewQuadraticExtensionFromBase(deltas[gates.LOOKUP_CHALLENGE_ALPHA])
+	challengeDelta := deltas[gates.LOOKUP_CHALLENGE_DELTA]
+
+	currentLookedCombos := make([]gl.QuadraticExtension, numLutSlots)
+	currentLookupCombos := make([]gl.QuadraticExtension, numLutSlots)
+	for s := uint64(0); s < numLutSlots; s++ {
+		inputWire := wires[gates.LookupTableGateWireIthLookedInp(s)]
+		outputWire := wires[gates.LookupTableGateWireIthLookedOut(s)]
+		currentLookedCombos[s] = inputWire.Add(outputWire.ScalarMul(challengeA))
+		currentLookupCombos[s] = inputWire.Add(outputWire.ScalarMul(challengeB))
+	}
+
+	currentLookingCombos := make([]gl.QuadraticExtension, numLuSlots)
+	for s := uint64(0); s < numLuSlots; s++ {
+		inputWire := wires[gates.LookupGateWireIthLookingInp(s)]
+		outputWire := wires[gates.LookupGateWireIthLookingOut(s)]
+		currentLookingCombos[s] = inputWire.Add(outputWire.ScalarMul(challengeA))
+	}
+
+	// Check the last LDC, and the initial Sum and RE constraints.
+	constraints = append(constraints, lookupSelectors[gates.LOOKUP_SELECTOR_LAST_LDC].Mul(zXLookupSldcs[numSldcPolys-1]))
+	constraints = append(constraints, lookupSelectors[gates.LOOKUP_SELECTOR_INIT_SRE].Mul(zXLookupSldcs[0]))
+	constraints = append(constraints, lookupSelectors[gates.LOOKUP_SELECTOR_INIT_SRE].Mul(zRe))
+
+	// Check final RE constraints for each different LUT.
+	for r := uint64(gates.LOOKUP_SELECTOR_START_END); r < v.commonData.NumLookupSelectors; r++ {
+		lut := v.commonData.Luts[r-gates.LOOKUP_SELECTOR_START_END]
+		lutRowNumber := (uint64(len(lut)) + numLutSlots - 1) / numLutSlots
+		curFunctionEval := evalLutPoly(lut, deltas, numLutSlots*lutRowNumber)
+		constraints = append(constraints, lookupSelectors[r].Mul(zRe.Sub(gl.NewQuadraticExtensionFromBase(curFunctionEval))))
+	}
+
+	// Check RE row transition constraint.
+	curSum := nextZRe
+	for _, combo := range currentLookupCombos {
+		curSum = curSum.ScalarMul(challengeDelta).Add(combo)
+	}
+	constraints = append(constraints, lookupSelectors[gates.LOOKUP_SELECTOR_TRANS_SRE].Mul(zRe.Sub(curSum)))
+
+	for poly := uint64(0); poly < numSldcPolys; poly++ {
+		lutStart, lutEnd := poly*lutDegree, (poly+1)*lutDegree
+		if lutEnd > numLutSlots {
+			lutEnd = numLutSlots
+		}
+		luStart, luEnd := poly*luDegree, (poly+1)*luDegree
+		if luEnd > numLuSlots {
+			luEnd = numLuSlots
+		}
+
+		lutProd := lookupProduct(challengeAlpha, currentLookedCombos, lutStart, lutEnd, lutEnd)
+		luProd := lookupProduct(challengeAlpha, currentLookingCombos, luStart, luEnd, luEnd)
+
+		lutSumProds := gl.ZeroQuadraticExtension()
+		for i := lutStart; i < lutEnd; i++ {
+			multiplicity := wires[gates.LookupTableGateWireIthMultiplicity(i)]
+			lutSumProds = lutSumProds.Add(multiplicity.Mul(lookupProduct(challengeAlpha, currentLookedCombos, lutStart, lutEnd, i)))
+		}
+
+		luSumProds := gl.ZeroQuadraticExtension()
+		for i := luStart; i < luEnd; i++ {
+			luSumProds = luSumProds.Add(lookupProduct(challengeAlpha, currentLookingCombos, luStart, luEnd, i))
+		}
+
+		// The previous element is the previous poly of the current row or the last poly of the next row.
+		prev := zGxLookupSldcs[numSldcPolys-1]
+		if poly != 0 {
+			prev = zXLookupSldcs[poly-1]
+		}
+		diff := zXLookupSldcs[poly].Sub(prev)
+
+		// Check Sum and LDC row and col transitions.
+		constraints = append(constraints, lookupSelectors[gates.LOOKUP_SELECTOR_TRANS_SRE].Mul(lutProd.Mul(diff).Sub(lutSumProds)))
+		constraints = append(constraints, lookupSelectors[gates.LOOKUP_SELECTOR_TRANS_LDC].Mul(luProd.Mul(diff).Add(luSumProds)))
+	}
+
+	return constraints
+}
+
+// The out of circuit counterpart of plonk.PlonkChip.evalLookupTerms.
+func (v *Verifier) evalLookupTerms(challenges *proofChallenges, openings *openingSet) []gl.QuadraticExtension {
+	numLookupPolys := v.commonData.NumLookupPolys
+	if numLookupPolys == 0 {
+		return nil
+	}
+
+	numSelectors := v.commonData.SelectorsInfo.NumSelectors()
+	lookupSelectors := openings.constants[numSelectors : numSelectors+v.commonData.NumLookupSelectors]
+
+	lookupTerms := []gl.QuadraticExtension{}
+	for i := uint64(0); i < v.commonData.Config.NumChallenges; i++ {
+		lookupTerms = append(lookupTerms, v.checkLookupConstraints(
+			openings.wires,
+			openings.lookupZs[i*numLookupPolys:(i+1)*numLookupPolys],
+			openings.lookupZsNext[i*numLookupPolys:(i+1)*numLookupPolys],
+			lookupSelectors,
+			challenges.plonkDeltas[i*gates.NUM_COINS_LOOKUP:(i+1)*gates.NUM_COINS_LOOKUP],
+		)...)
+	}
+	return lookupTerms
+}
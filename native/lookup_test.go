@@ -0,0 +1,161 @@
+package native
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/plonk/gates"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+)
+
+func randomQuadraticExtension(rng *rand.Rand) gl.QuadraticExtension {
+	return gl.NewQuadraticExtension(goldilocks.NewElement(rng.Uint64()), goldilocks.NewElement(rng.Uint64()))
+}
+
+func randomQuadraticExtensions(rng *rand.Rand, n uint64) []gl.QuadraticExtension {
+	elements := make([]gl.QuadraticExtension, n)
+	for i := range elements {
+		elements[i] = randomQuadraticExtension(rng)
+	}
+	return elements
+}
+
+// The lookup parameters of plonky2's standard recursion config, with a single lookup table.
+func lookupCommonData() types.CommonCircuitData {
+	var commonData types.CommonCircuitData
+	commonData.Config.NumWires = 135
+	commonData.Config.NumRoutedWires = 80
+	commonData.Config.NumChallenges = 2
+	commonData.Config.MaxQuotientDegreeFactor = 8
+	commonData.QuotientDegreeFactor = 8
+	commonData.NumLookupPolys = 7
+	commonData.NumLookupSelectors = gates.LOOKUP_SELECTOR_START_END + 1
+
+	lut := make(types.LookupTable, 30)
+	for i := range lut {
+		lut[i] = [2]uint16{uint16(i), uint16(i * i)}
+	}
+	commonData.Luts = []types.LookupTable{lut}
+
+	return commonData
+}
+
+// Builds the lookup polynomials of a row on which only the given lookup selector is set, the way
+// an honest prover would: the sums of logarithmic derivatives are computed with inverses rather
+// than with the products that the constraints use.
+func honestLookupRow(
+	rng *rand.Rand,
+	commonData *types.CommonCircuitData,
+	selector uint64,
+	deltas []goldilocks.Element,
+) ([]gl.QuadraticExtension, []gl.QuadraticExtension, []gl.QuadraticExtension) {
+	wires := randomQuadraticExtensions(rng, commonData.Config.NumWires)
+	localLookupZs := randomQuadraticExtensions(rng, commonData.NumLookupPolys)
+	nextLookupZs := randomQuadraticExtensions(rng, commonData.NumLookupPolys)
+
+	numSldcPolys := commonData.NumLookupPolys - 1
+	numLuSlots := gates.LookupGateNumSlots(commonData.Config.NumRoutedWires)
+	numLutSlots := gates.LookupTableGateNumSlots(commonData.Config.NumRoutedWires)
+	luDegree := commonData.QuotientDegreeFactor - 1
+	lutDegree := (numLutSlots + numSldcPolys - 1) / numSldcPolys
+
+	challengeA := deltas[gates.LOOKUP_CHALLENGE_A]
+	challengeB := deltas[gates.LOOKUP_CHALLENGE_B]
+	alpha := gl.NewQuadraticExtensionFromBase(deltas[gates.LOOKUP_CHALLENGE_ALPHA])
+	delta := gl.NewQuadraticExtensionFromBase(deltas[gates.LOOKUP_CHALLENGE_DELTA])
+
+	switch selector {
+	case gates.LOOKUP_SELECTOR_TRANS_SRE:
+		localLookupZs[0] = nextLookupZs[0]
+		for s := uint64(0); s < numLutSlots; s++ {
+			input := wires[gates.LookupTableGateWireIthLookedInp(s)]
+			output := wires[gates.LookupTableGateWireIthLookedOut(s)]
+			localLookupZs[0] = localLookupZs[0].Mul(delta).Add(input.Add(output.ScalarMul(challengeB)))
+		}
+
+		sum := nextLookupZs[numSldcPolys]
+		for s := uint64(0); s < numLutSlots; s++ {
+			input := wires[gates.LookupTableGateWireIthLookedInp(s)]
+			output := wires[gates.LookupTableGateWireIthLookedOut(s)]
+			multiplicity := wires[gates.LookupTableGateWireIthMultiplicity(s)]
+			sum = sum.Add(multiplicity.Mul(alpha.Sub(input.Add(output.ScalarMul(challengeA))).Inverse()))
+			if s%lutDegree == lutDegree-1 || s == numLutSlots-1 {
+				localLookupZs[1+s/lutDegree] = sum
+			}
+		}
+		for poly := (numLutSlots-1)/lutDegree + 1; poly < numSldcPolys; poly++ {
+			localLookupZs[1+poly] = sum
+		}
+	case gates.LOOKUP_SELECTOR_TRANS_LDC:
+		ldc := nextLookupZs[numSldcPolys]
+		for s := uint64(0); s < numLuSlots; s++ {
+			input := wires[gates.LookupGateWireIthLookingInp(s)]
+			output := wires[gates.LookupGateWireIthLookingOut(s)]
+			ldc = ldc.Sub(alpha.Sub(input.Add(output.ScalarMul(challengeA))).Inverse())
+			if s%luDegree == luDegree-1 || s == numLuSlots-1 {
+				localLookupZs[1+s/luDegree] = ldc
+			}
+		}
+	case gates.LOOKUP_SELECTOR_INIT_SRE:
+		localLookupZs[0] = gl.ZeroQuadraticExtension()
+		localLookupZs[1] = gl.ZeroQuadraticExtension()
+	case gates.LOOKUP_SELECTOR_LAST_LDC:
+		localLookupZs[numSldcPolys] = gl.ZeroQuadraticExtension()
+	default:
+		// The table is padded with zeros to fill its last row, and its first entry has the
+		// highest power of delta.
+		lut := commonData.Luts[selector-gates.LOOKUP_SELECTOR_START_END]
+		degree := (uint64(len(lut)) + numLutSlots - 1) / numLutSlots * numLutSlots
+		localLookupZs[0] = gl.ZeroQuadraticExtension()
+		for i, entry := range lut {
+			combo := gl.NewQuadraticExtensionFromUint64(uint64(entry[0])).Add(
+				gl.NewQuadraticExtensionFromUint64(uint64(entry[1])).ScalarMul(challengeB),
+			)
+			localLookupZs[0] = localLookupZs[0].Add(combo.Mul(delta.Exp(degree - 1 - uint64(i))))
+		}
+	}
+
+	return wires, localLookupZs, nextLookupZs
+}
+
+func TestCheckLookupConstraints(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	commonData := lookupCommonData()
+	v := &Verifier{commonData: commonData}
+
+	deltas := make([]goldilocks.Element, gates.NUM_COINS_LOOKUP)
+	for i := range deltas {
+		deltas[i] = goldilocks.NewElement(rng.Uint64())
+	}
+
+	for selector := uint64(0); selector < commonData.NumLookupSelectors; selector++ {
+		lookupSelectors := make([]gl.QuadraticExtension, commonData.NumLookupSelectors)
+		lookupSelectors[selector] = gl.OneQuadraticExtension()
+
+		wires, localLookupZs, nextLookupZs := honestLookupRow(rng, &commonData, selector, deltas)
+		constraints := v.checkLookupConstraints(wires, localLookupZs, nextLookupZs, lookupSelectors, deltas)
+		if expectedLen := 4 + len(commonData.Luts) + 2*int(commonData.NumLookupPolys-1); len(constraints) != expectedLen {
+			t.Fatalf("expected %d constraints, got %d", expectedLen, len(constraints))
+		}
+		for i, constraint := range constraints {
+			if !constraint.IsZero() {
+				t.Errorf("selector %d: constraint %d is %s", selector, i, constraint)
+			}
+		}
+
+		// Every lookup selector constrains the local lookup polynomials.
+		for i := range localLookupZs {
+			localLookupZs[i] = localLookupZs[i].Add(gl.OneQuadraticExtension())
+		}
+		constraints = v.checkLookupConstraints(wires, localLookupZs, nextLookupZs, lookupSelectors, deltas)
+		allZero := true
+		for _, constraint := range constraints {
+			allZero = allZero && constraint.IsZero()
+		}
+		if allZero {
+			t.Errorf("selector %d: the constraints hold for a dishonest row", selector)
+		}
+	}
+}
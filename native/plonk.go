@@ -0,0 +1,172 @@
+package native
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/plonk/gates"
+	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
+)
+
+func (v *Verifier) expPowerOf2Extension(x gl.QuadraticExtension) gl.QuadraticExtension {
+	for i := uint64(0); i < v.commonData.DegreeBits; i++ {
+		x = x.Mul(x)
+	}
+	return x
+}
+
+func (v *Verifier) evalL0(x gl.QuadraticExtension, xPowN gl.QuadraticExtension) (gl.QuadraticExtension, error) {
+	// L_0(x) = (x^n - 1) / (n * (x - 1))
+	n := goldilocks.NewElement(1 << v.commonData.DegreeBits)
+	evalZeroPoly := xPowN.Sub(gl.OneQuadraticExtension())
+	denominator := x.Sub(gl.OneQuadraticExtension()).ScalarMul(n)
+
+	quotient, hasQuotient := evalZeroPoly.Div(denominator)
+	if !hasQuotient {
+		return quotient, fmt.Errorf("plonk zeta is 1, so L_0(zeta) is undefined")
+	}
+	return quotient, nil
+}
+
+func (v *Verifier) checkPartialProducts(
+	numerators []gl.QuadraticExtension,
+	denominators []gl.QuadraticExtension,
+	challengeNum uint64,
+	openings *openingSet,
+) []gl.QuadraticExtension {
+	numPartProds := v.commonData.NumPartialProducts
+	quotDegreeFactor := v.commonData.QuotientDegreeFactor
+
+	productAccs := make([]gl.QuadraticExtension, 0, numPartProds+2)
+	productAccs = append(productAccs, openings.plonkZs[challengeNum])
+	productAccs = append(productAccs, openings.partialProducts[challengeNum*numPartProds:(challengeNum+1)*numPartProds]...)
+	productAccs = append(productAccs, openings.plonkZsNext[challengeNum])
+
+	partialProductChecks := make([]gl.QuadraticExtension, 0, numPartProds+1)
+
+	for i := uint64(0); i <= numPartProds; i++ {
+		ppStartIdx := i * quotDegreeFactor
+		ppEndIdx := ppStartIdx + quotDegreeFactor
+		if ppEndIdx > uint64(len(numerators)) {
+			ppEndIdx = uint64(len(numerators))
+		}
+
+		numeProduct := gl.OneQuadraticExtension()
+		denoProduct := gl.OneQuadraticExtension()
+		for j := ppStartIdx; j < ppEndIdx; j++ {
+			numeProduct = numeProduct.Mul(numerators[j])
+			denoProduct = denoProduct.Mul(denominators[j])
+		}
+
+		partialProductCheck := productAccs[i].Mul(numeProduct).Sub(productAccs[i+1].Mul(denoProduct))
+		partialProductChecks = append(partialProductChecks, partialProductCheck)
+	}
+	return partialProductChecks
+}
+
+func (v *Verifier) evalVanishingPoly(
+	vars gates.NativeEvaluationVars,
+	challenges *proofChallenges,
+	openings *openingSet,
+	zetaPowN gl.QuadraticExtension,
+) ([]gl.QuadraticExtension, error) {
+	config := v.commonData.Config
+	constraintTerms := v.evaluateGates.EvaluateGateConstraints(vars)
+
+	// Calculate the k[i] * x
+	sIDs := make([]gl.QuadraticExtension, config.NumRoutedWires)
+	for i := range sIDs {
+		sIDs[i] = challenges.plonkZeta.ScalarMul(goldilocks.NewElement(v.commonData.KIs[i]))
+	}
+
+	// Calculate L_0(zeta)
+	l0Zeta, err := v.evalL0(challenges.plonkZeta, zetaPowN)
+	if err != nil {
+		return nil, err
+	}
+
+	vanishingZ1Terms := make([]gl.QuadraticExtension, 0, config.NumChallenges)
+	vanishingPartialProductsTerms := make([]gl.QuadraticExtension, 0, config.NumChallenges*(v.commonData.NumPartialProducts+1))
+	for i := uint64(0); i < config.NumChallenges; i++ {
+		// L_0(zeta) (Z(zeta) - 1) = 0
+		z1Term := l0Zeta.Mul(openings.plonkZs[i].Sub(gl.OneQuadraticExtension()))
+		vanishingZ1Terms = append(vanishingZ1Terms, z1Term)
+
+		beta := gl.NewQuadraticExtensionFromBase(challenges.plonkBetas[i])
+		gamma := gl.NewQuadraticExtensionFromBase(challenges.plonkGammas[i])
+
+		numeratorValues := make([]gl.QuadraticExtension, 0, config.NumRoutedWires)
+		denominatorValues := make([]gl.QuadraticExtension, 0, config.NumRoutedWires)
+		for j := uint64(0); j < config.NumRoutedWires; j++ {
+			// The numerator is `beta * s_id + wire_value + gamma`, and the denominator is
+			// `beta * s_sigma + wire_value + gamma`.
+			wireValuePlusGamma := openings.wires[j].Add(gamma)
+			numeratorValues = append(numeratorValues, beta.Mul(sIDs[j]).Add(wireValuePlusGamma))
+			denominatorValues = append(denominatorValues, beta.Mul(openings.plonkSigmas[j]).Add(wireValuePlusGamma))
+		}
+
+		vanishingPartialProductsTerms = append(
+			vanishingPartialProductsTerms,
+			v.checkPartialProducts(numeratorValues, denominatorValues, i, openings)...,
+		)
+	}
+
+	vanishingTerms := append(vanishingZ1Terms, vanishingPartialProductsTerms...)
+	vanishingTerms = append(vanishingTerms, v.evalLookupTerms(challenges, openings)...)
+	vanishingTerms = append(vanishingTerms, constraintTerms...)
+
+	reducedValues := make([]gl.QuadraticExtension, config.NumChallenges)
+	for j := range reducedValues {
+		reducedValues[j] = gl.ZeroQuadraticExtension()
+		for i := len(vanishingTerms) - 1; i >= 0; i-- {
+			reducedValues[j] = reducedValues[j].ScalarMul(challenges.plonkAlphas[j]).Add(vanishingTerms[i])
+		}
+	}
+
+	return reducedValues, nil
+}
+
+// The out of circuit counterpart of plonk.PlonkChip.Verify.  Checks that the vanishing polynomial
+// evaluated at zeta matches Z_H(zeta) times the quotient polynomial evaluated at zeta.
+func (v *Verifier) verifyPlonk(
+	challenges *proofChallenges,
+	openings *openingSet,
+	publicInputsHash poseidon.GoldilocksNativeHashOut,
+) error {
+	// Calculate zeta^n
+	zetaPowN := v.expPowerOf2Extension(challenges.plonkZeta)
+
+	vars := gates.NewNativeEvaluationVars(openings.constants, openings.wires, publicInputsHash)
+
+	vanishingPolysZeta, err := v.evalVanishingPoly(*vars, challenges, openings, zetaPowN)
+	if err != nil {
+		return err
+	}
+
+	// Calculate Z(H)
+	zHZeta := zetaPowN.Sub(gl.OneQuadraticExtension())
+
+	// `quotient_polys_zeta` holds `num_challenges * quotient_degree_factor` evaluations.
+	// Each chunk of `quotient_degree_factor` holds the evaluations of `t_0(zeta),...,t_{quotient_degree_factor-1}(zeta)`
+	// where the "real" quotient polynomial is `t(X) = t_0(X) + t_1(X)*X^n + t_2(X)*X^{2n} + ...`.
+	// So to reconstruct `t(zeta)` we can compute `reduce_with_powers(chunk, zeta^n)` for each
+	// `quotient_degree_factor`-sized chunk of the original evaluations.
+	quotDegreeFactor := int(v.commonData.QuotientDegreeFactor)
+	for i, vanishingPolyZeta := range vanishingPolysZeta {
+		quotientPolysStartIdx := i * quotDegreeFactor
+		quotientPolysEndIdx := quotientPolysStartIdx + quotDegreeFactor
+		prod := zHZeta.Mul(gl.ReduceWithPowers(openings.quotientPolys[quotientPolysStartIdx:quotientPolysEndIdx], zetaPowN))
+
+		if !vanishingPolyZeta.Equal(prod) {
+			return fmt.Errorf(
+				"vanishing polynomial for challenge %d does not match the quotient polynomial at zeta: %s != %s",
+				i,
+				vanishingPolyZeta,
+				prod,
+			)
+		}
+	}
+
+	return nil
+}
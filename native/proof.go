@@ -0,0 +1,304 @@
+package native
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+)
+
+// The native counterparts of the proof structs within the variables package.  The raw proof is
+// parsed into these once, so that the rest of the verifier can work on field elements directly.
+
+type merkleCap = []fr.Element
+
+type openingSet struct {
+	constants       []gl.QuadraticExtension
+	plonkSigmas     []gl.QuadraticExtension
+	wires           []gl.QuadraticExtension
+	plonkZs         []gl.QuadraticExtension
+	plonkZsNext     []gl.QuadraticExtension
+	partialProducts []gl.QuadraticExtension
+	quotientPolys   []gl.QuadraticExtension
+	lookupZs        []gl.QuadraticExtension
+	lookupZsNext    []gl.QuadraticExtension
+}
+
+type friEvalProof struct {
+	elements []goldilocks.Element
+	siblings []fr.Element
+}
+
+type friQueryStep struct {
+	evals    []gl.QuadraticExtension
+	siblings []fr.Element
+}
+
+type friQueryRound struct {
+	initialTreesProof []friEvalProof
+	steps             []friQueryStep
+}
+
+type friProof struct {
+	commitPhaseMerkleCaps []merkleCap
+	queryRoundProofs      []friQueryRound
+	finalPoly             []gl.QuadraticExtension
+	powWitness            goldilocks.Element
+}
+
+type proof struct {
+	wiresCap                  merkleCap
+	plonkZsPartialProductsCap merkleCap
+	quotientPolysCap          merkleCap
+	openings                  openingSet
+	openingProof              friProof
+}
+
+type verifierOnlyCircuitData struct {
+	constantSigmasCap merkleCap
+	circuitDigest     fr.Element
+}
+
+func parseGoldilocks(value uint64, name string) (goldilocks.Element, error) {
+	if value >= gl.MODULUS.Uint64() {
+		return goldilocks.Element{}, fmt.Errorf("%s: %d is not a canonical goldilocks element", name, value)
+	}
+	return goldilocks.NewElement(value), nil
+}
+
+func parseGoldilocksArray(values []uint64, expectedLen uint64, name string) ([]goldilocks.Element, error) {
+	if uint64(len(values)) != expectedLen {
+		return nil, fmt.Errorf("%s: expected %d elements, got %d", name, expectedLen, len(values))
+	}
+
+	elements := make([]goldilocks.Element, len(values))
+	for i, value := range values {
+		element, err := parseGoldilocks(value, fmt.Sprintf("%s[%d]", name, i))
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = element
+	}
+	return elements, nil
+}
+
+func parseQuadraticExtensionArray(values [][]uint64, expectedLen uint64, name string) ([]gl.QuadraticExtension, error) {
+	if uint64(len(values)) != expectedLen {
+		return nil, fmt.Errorf("%s: expected %d extension elements, got %d", name, expectedLen, len(values))
+	}
+
+	elements := make([]gl.QuadraticExtension, len(values))
+	for i, value := range values {
+		coeffs, err := parseGoldilocksArray(value, gl.D, fmt.Sprintf("%s[%d]", name, i))
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = gl.NewQuadraticExtension(coeffs[0], coeffs[1])
+	}
+	return elements, nil
+}
+
+func parseBN254(value string, name string) (fr.Element, error) {
+	bigValue, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return fr.Element{}, fmt.Errorf("%s: %q is not a decimal integer", name, value)
+	}
+	if bigValue.Sign() < 0 || bigValue.Cmp(fr.Modulus()) >= 0 {
+		return fr.Element{}, fmt.Errorf("%s: %s is not a canonical bn254 element", name, value)
+	}
+
+	var element fr.Element
+	element.SetBigInt(bigValue)
+	return element, nil
+}
+
+func parseBN254Array(values []string, expectedLen uint64, name string) ([]fr.Element, error) {
+	if uint64(len(values)) != expectedLen {
+		return nil, fmt.Errorf("%s: expected %d hashes, got %d", name, expectedLen, len(values))
+	}
+
+	elements := make([]fr.Element, len(values))
+	for i, value := range values {
+		element, err := parseBN254(value, fmt.Sprintf("%s[%d]", name, i))
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = element
+	}
+	return elements, nil
+}
+
+func parseVerifierOnlyCircuitData(
+	raw types.VerifierOnlyCircuitDataRaw,
+	commonData *types.CommonCircuitData,
+) (verifierOnlyCircuitData, error) {
+	var data verifierOnlyCircuitData
+	var err error
+
+	capLen := uint64(1) << commonData.FriParams.Config.CapHeight
+	if data.constantSigmasCap, err = parseBN254Array(raw.ConstantsSigmasCap, capLen, "constants_sigmas_cap"); err != nil {
+		return data, err
+	}
+	if data.circuitDigest, err = parseBN254(raw.CircuitDigest, "circuit_digest"); err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
+
+func parseOpeningSet(proofRaw *types.ProofWithPublicInputsRaw, commonData *types.CommonCircuitData) (openingSet, error) {
+	raw := proofRaw.Proof.Openings
+	numChallenges := commonData.Config.NumChallenges
+
+	var openings openingSet
+	var err error
+	if openings.constants, err = parseQuadraticExtensionArray(raw.Constants, commonData.NumConstants, "openings.constants"); err != nil {
+		return openings, err
+	}
+	if openings.plonkSigmas, err = parseQuadraticExtensionArray(raw.PlonkSigmas, commonData.Config.NumRoutedWires, "openings.plonk_sigmas"); err != nil {
+		return openings, err
+	}
+	if openings.wires, err = parseQuadraticExtensionArray(raw.Wires, commonData.Config.NumWires, "openings.wires"); err != nil {
+		return openings, err
+	}
+	if openings.plonkZs, err = parseQuadraticExtensionArray(raw.PlonkZs, numChallenges, "openings.plonk_zs"); err != nil {
+		return openings, err
+	}
+	if openings.plonkZsNext, err = parseQuadraticExtensionArray(raw.PlonkZsNext, numChallenges, "openings.plonk_zs_next"); err != nil {
+		return openings, err
+	}
+	if openings.partialProducts, err = parseQuadraticExtensionArray(
+		raw.PartialProducts,
+		numChallenges*commonData.NumPartialProducts,
+		"openings.partial_products",
+	); err != nil {
+		return openings, err
+	}
+	if openings.quotientPolys, err = parseQuadraticExtensionArray(
+		raw.QuotientPolys,
+		numChallenges*commonData.QuotientDegreeFactor,
+		"openings.quotient_polys",
+	); err != nil {
+		return openings, err
+	}
+	if openings.lookupZs, err = parseQuadraticExtensionArray(types.LookupOpeningValues(raw.LookupZs), commonData.NumAllLookupPolys(), "openings.lookup_zs"); err != nil {
+		return openings, err
+	}
+	if openings.lookupZsNext, err = parseQuadraticExtensionArray(types.LookupOpeningValues(raw.LookupZsNext), commonData.NumAllLookupPolys(), "openings.lookup_zs_next"); err != nil {
+		return openings, err
+	}
+
+	return openings, nil
+}
+
+func parseFriProof(proofRaw *types.ProofWithPublicInputsRaw, commonData *types.CommonCircuitData) (friProof, error) {
+	raw := proofRaw.Proof.OpeningProof
+	friParams := commonData.FriParams
+	capHeight := friParams.Config.CapHeight
+	capLen := uint64(1) << capHeight
+	ldeBits := uint64(friParams.LdeBits())
+
+	var fp friProof
+	var err error
+
+	numSteps := len(friParams.ReductionArityBits)
+	if len(raw.CommitPhaseMerkleCaps) != numSteps {
+		return fp, fmt.Errorf("opening_proof.commit_phase_merkle_caps: expected %d caps, got %d", numSteps, len(raw.CommitPhaseMerkleCaps))
+	}
+	fp.commitPhaseMerkleCaps = make([]merkleCap, numSteps)
+	for i, capRaw := range raw.CommitPhaseMerkleCaps {
+		name := fmt.Sprintf("opening_proof.commit_phase_merkle_caps[%d]", i)
+		if fp.commitPhaseMerkleCaps[i], err = parseBN254Array(capRaw, capLen, name); err != nil {
+			return fp, err
+		}
+	}
+
+	if uint64(len(raw.QueryRoundProofs)) != friParams.Config.NumQueryRounds {
+		return fp, fmt.Errorf(
+			"opening_proof.query_round_proofs: expected %d query rounds, got %d",
+			friParams.Config.NumQueryRounds,
+			len(raw.QueryRoundProofs),
+		)
+	}
+
+	leafLens := commonData.OracleLeafLens()
+	fp.queryRoundProofs = make([]friQueryRound, len(raw.QueryRoundProofs))
+	for i, roundRaw := range raw.QueryRoundProofs {
+		roundName := fmt.Sprintf("opening_proof.query_round_proofs[%d]", i)
+		round := &fp.queryRoundProofs[i]
+
+		evalsProofs := roundRaw.InitialTreesProof.EvalsProofs
+		if len(evalsProofs) != len(leafLens) {
+			return fp, fmt.Errorf("%s.initial_trees_proof: expected %d evals proofs, got %d", roundName, len(leafLens), len(evalsProofs))
+		}
+		round.initialTreesProof = make([]friEvalProof, len(evalsProofs))
+		for j, evalsProof := range evalsProofs {
+			name := fmt.Sprintf("%s.initial_trees_proof.evals_proofs[%d]", roundName, j)
+			if round.initialTreesProof[j].elements, err = parseGoldilocksArray(evalsProof.LeafElements, leafLens[j], name+".elements"); err != nil {
+				return fp, err
+			}
+			if round.initialTreesProof[j].siblings, err = parseBN254Array(evalsProof.MerkleProof.Hash, ldeBits-capHeight, name+".siblings"); err != nil {
+				return fp, err
+			}
+		}
+
+		if len(roundRaw.Steps) != numSteps {
+			return fp, fmt.Errorf("%s.steps: expected %d steps, got %d", roundName, numSteps, len(roundRaw.Steps))
+		}
+		round.steps = make([]friQueryStep, numSteps)
+		codewordLenBits := ldeBits
+		for j, stepRaw := range roundRaw.Steps {
+			name := fmt.Sprintf("%s.steps[%d]", roundName, j)
+			arityBits := friParams.ReductionArityBits[j]
+			codewordLenBits -= arityBits
+			if round.steps[j].evals, err = parseQuadraticExtensionArray(stepRaw.Evals, 1<<arityBits, name+".evals"); err != nil {
+				return fp, err
+			}
+			if round.steps[j].siblings, err = parseBN254Array(stepRaw.MerkleProof.Siblings, codewordLenBits-capHeight, name+".siblings"); err != nil {
+				return fp, err
+			}
+		}
+	}
+
+	if fp.finalPoly, err = parseQuadraticExtensionArray(
+		raw.FinalPoly.Coeffs,
+		uint64(friParams.FinalPolyLen()),
+		"opening_proof.final_poly.coeffs",
+	); err != nil {
+		return fp, err
+	}
+	if fp.powWitness, err = parseGoldilocks(raw.PowWitness, "opening_proof.pow_witness"); err != nil {
+		return fp, err
+	}
+
+	return fp, nil
+}
+
+// Parses the raw proof, checking that every value is canonical and that every array has the
+// length implied by commonData.
+func parseProof(raw *types.ProofWithPublicInputsRaw, commonData *types.CommonCircuitData) (proof, error) {
+	var p proof
+	var err error
+
+	capLen := uint64(1) << commonData.FriParams.Config.CapHeight
+	if p.wiresCap, err = parseBN254Array(raw.Proof.WiresCap, capLen, "wires_cap"); err != nil {
+		return p, err
+	}
+	if p.plonkZsPartialProductsCap, err = parseBN254Array(raw.Proof.PlonkZsPartialProductsCap, capLen, "plonk_zs_partial_products_cap"); err != nil {
+		return p, err
+	}
+	if p.quotientPolysCap, err = parseBN254Array(raw.Proof.QuotientPolysCap, capLen, "quotient_polys_cap"); err != nil {
+		return p, err
+	}
+	if p.openings, err = parseOpeningSet(raw, commonData); err != nil {
+		return p, err
+	}
+	if p.openingProof, err = parseFriProof(raw, commonData); err != nil {
+		return p, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,155 @@
+// Package native verifies plonky2 proofs out of circuit.  It runs the same checks as
+// verifier.VerifierChip.Verify, so that a bad proof can be rejected in milliseconds instead of
+// after compiling and proving the gnark circuit.
+package native
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/plonk/gates"
+	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+)
+
+type Verifier struct {
+	commonData    types.CommonCircuitData
+	evaluateGates *gates.NativeEvaluateGates
+	poseidonGl    *poseidon.GoldilocksHasher
+	poseidonBN254 *poseidon.BN254Hasher
+
+	// Set if one of the circuit's gate IDs isn't in the registry, or its gate doesn't implement
+	// gates.NativeGate, in which case every proof is rejected with it.
+	gateErr error
+}
+
+func NewVerifier(commonCircuitData types.CommonCircuitData) *Verifier {
+	return NewVerifierWithRegistry(commonCircuitData, gates.DefaultRegistry)
+}
+
+// Like NewVerifier, but looks up the circuit's gates in the given registry, which may contain
+// custom gates.  A gate ID that's unknown or ambiguous doesn't fail here, but Verify rejects every
+// proof with the registry's error.
+func NewVerifierWithRegistry(commonCircuitData types.CommonCircuitData, registry *gates.Registry) *Verifier {
+	// Create the gates based on commonData GateIds
+	var gateErr error
+	createdGates := []gates.NativeGate{}
+	for _, gateId := range commonCircuitData.GateIds {
+		gate, err := registry.TryGateInstanceFromId(gateId)
+		if err != nil {
+			if gateErr == nil {
+				gateErr = err
+			}
+			continue
+		}
+		nativeGate, ok := gate.(gates.NativeGate)
+		if !ok {
+			if gateErr == nil {
+				gateErr = fmt.Errorf("gate %s can't be evaluated out of circuit", gateId)
+			}
+			continue
+		}
+		createdGates = append(createdGates, nativeGate)
+	}
+
+	evaluateGates := gates.NewNativeEvaluateGates(
+		createdGates,
+		commonCircuitData.NumGateConstraints,
+		commonCircuitData.SelectorsInfo,
+		commonCircuitData.NumLookupSelectors,
+	)
+
+	return &Verifier{
+		commonData:    commonCircuitData,
+		evaluateGates: evaluateGates,
+		poseidonGl:    poseidon.NewGoldilocksHasher(),
+		poseidonBN254: poseidon.NewBN254Hasher(),
+		gateErr:       gateErr,
+	}
+}
+
+// Parses the proof and verifier only data, and hashes the public inputs.
+func (v *Verifier) parse(
+	proofWithPis types.ProofWithPublicInputsRaw,
+	verifierOnlyCircuitData types.VerifierOnlyCircuitDataRaw,
+) (proof, poseidon.GoldilocksNativeHashOut, verifierOnlyCircuitData, error) {
+	var publicInputsHash poseidon.GoldilocksNativeHashOut
+
+	verifierData, err := parseVerifierOnlyCircuitData(verifierOnlyCircuitData, &v.commonData)
+	if err != nil {
+		return proof{}, publicInputsHash, verifierData, fmt.Errorf("invalid verifier only circuit data: %w", err)
+	}
+
+	p, err := parseProof(&proofWithPis, &v.commonData)
+	if err != nil {
+		return p, publicInputsHash, verifierData, fmt.Errorf("invalid proof: %w", err)
+	}
+
+	if uint64(len(proofWithPis.PublicInputs)) != v.commonData.NumPublicInputs {
+		return p, publicInputsHash, verifierData, fmt.Errorf(
+			"invalid proof: expected %d public inputs, got %d",
+			v.commonData.NumPublicInputs,
+			len(proofWithPis.PublicInputs),
+		)
+	}
+
+	// The circuit reduces the public inputs before hashing them, so they don't need to be canonical.
+	publicInputs := make([]goldilocks.Element, len(proofWithPis.PublicInputs))
+	for i, publicInput := range proofWithPis.PublicInputs {
+		publicInputs[i] = goldilocks.NewElement(publicInput)
+	}
+	publicInputsHash = v.poseidonGl.HashNoPad(publicInputs)
+
+	return p, publicInputsHash, verifierData, nil
+}
+
+// Computes the Fiat-Shamir challenges of the proof, in the same format that plonky2 serializes
+// them.  This is useful to find transcript mismatches when a proof fails to verify.
+func (v *Verifier) GetChallenges(
+	proofWithPis types.ProofWithPublicInputsRaw,
+	verifierOnlyCircuitData types.VerifierOnlyCircuitDataRaw,
+) (types.ProofChallengesRaw, error) {
+	proof, publicInputsHash, verifierData, err := v.parse(proofWithPis, verifierOnlyCircuitData)
+	if err != nil {
+		return types.ProofChallengesRaw{}, err
+	}
+
+	return v.getChallenges(&proof, publicInputsHash, &verifierData), nil
+}
+
+// Verifies the proof against the verifier only data.  A nil error means that the verifier circuit
+// will be satisfied by the same inputs.
+func (v *Verifier) Verify(
+	proofWithPis types.ProofWithPublicInputsRaw,
+	verifierOnlyCircuitData types.VerifierOnlyCircuitDataRaw,
+) error {
+	if v.gateErr != nil {
+		return v.gateErr
+	}
+
+	proof, publicInputsHash, verifierData, err := v.parse(proofWithPis, verifierOnlyCircuitData)
+	if err != nil {
+		return err
+	}
+
+	challenges := proofChallengesFromRaw(v.getChallenges(&proof, publicInputsHash, &verifierData))
+
+	if err := v.verifyPlonk(&challenges, &proof.openings, publicInputsHash); err != nil {
+		return err
+	}
+
+	initialMerkleCaps := []merkleCap{
+		verifierData.constantSigmasCap,
+		proof.wiresCap,
+		proof.plonkZsPartialProductsCap,
+		proof.quotientPolysCap,
+	}
+
+	return v.verifyFriProof(
+		v.friInstance(challenges.plonkZeta),
+		toOpenings(&proof.openings),
+		&challenges.friChallenges,
+		initialMerkleCaps,
+		&proof.openingProof,
+	)
+}
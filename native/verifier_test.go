@@ -0,0 +1,200 @@
+package native_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/native"
+	"github.com/succinctlabs/gnark-plonky2-verifier/plonk/gates"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+)
+
+func readTestData(plonky2Circuit string) (types.CommonCircuitData, types.ProofWithPublicInputsRaw, types.VerifierOnlyCircuitDataRaw) {
+	commonCircuitData := types.ReadCommonCircuitData("../testdata/" + plonky2Circuit + "/common_circuit_data.json")
+	proofWithPis := types.ReadProofWithPublicInputs("../testdata/" + plonky2Circuit + "/proof_with_public_inputs.json")
+	verifierOnlyCircuitData := types.ReadVerifierOnlyCircuitData("../testdata/" + plonky2Circuit + "/verifier_only_circuit_data.json")
+	return commonCircuitData, proofWithPis, verifierOnlyCircuitData
+}
+
+func TestVerifier(t *testing.T) {
+	for _, plonky2Circuit := range []string{"step", "decode_block"} {
+		commonCircuitData, proofWithPis, verifierOnlyCircuitData := readTestData(plonky2Circuit)
+
+		err := native.NewVerifier(commonCircuitData).Verify(proofWithPis, verifierOnlyCircuitData)
+		if err != nil {
+			t.Errorf("%s: %v", plonky2Circuit, err)
+		}
+	}
+}
+
+func TestLookupVerifier(t *testing.T) {
+	if _, err := os.Stat("../testdata/lookup"); os.IsNotExist(err) {
+		t.Fatal("no lookup fixture, see testdata/lookup.md")
+	}
+
+	commonCircuitData, proofWithPis, verifierOnlyCircuitData := readTestData("lookup")
+	if len(commonCircuitData.Luts) == 0 {
+		t.Fatal("the lookup fixture doesn't have any lookup tables")
+	}
+
+	err := native.NewVerifier(commonCircuitData).Verify(proofWithPis, verifierOnlyCircuitData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lookupZ := (*proofWithPis.Proof.Openings.LookupZs)[0]
+	lookupZ[0] = (lookupZ[0] + 1) % goldilocks.Modulus().Uint64()
+	err = native.NewVerifier(commonCircuitData).Verify(proofWithPis, verifierOnlyCircuitData)
+	if err == nil {
+		t.Error("a proof with a mutated lookup_zs opening verified")
+	}
+}
+
+func TestZkVerifier(t *testing.T) {
+	if _, err := os.Stat("../testdata/zk"); os.IsNotExist(err) {
+		t.Fatal("no zk fixture, see testdata/zk.md")
+	}
+
+	commonCircuitData, proofWithPis, verifierOnlyCircuitData := readTestData("zk")
+	if !commonCircuitData.Config.ZeroKnowledge || !commonCircuitData.FriParams.Hiding {
+		t.Fatal("the zk fixture isn't hiding")
+	}
+
+	err := native.NewVerifier(commonCircuitData).Verify(proofWithPis, verifierOnlyCircuitData)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetChallenges(t *testing.T) {
+	commonCircuitData, proofWithPis, verifierOnlyCircuitData := readTestData("decode_block")
+
+	challenges, err := native.NewVerifier(commonCircuitData).GetChallenges(proofWithPis, verifierOnlyCircuitData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// These are the same values that fri_test.go checks the challenger chip against.
+	expected := []struct {
+		name     string
+		actual   uint64
+		expected uint64
+	}{
+		{"plonk_betas[0]", challenges.PlonkBetas[0], 17615363392879944733},
+		{"plonk_gammas[0]", challenges.PlonkGammas[0], 15174493176564484303},
+		{"plonk_alphas[0]", challenges.PlonkAlphas[0], 9276470834414745550},
+		{"plonk_zeta[0]", challenges.PlonkZeta[0], 3892795992421241388},
+		{"fri_alpha[0]", challenges.FriChallenges.FriAlpha[0], 885535811531859621},
+		{"fri_betas[0][0]", challenges.FriChallenges.FriBetas[0][0], 5231781384587895507},
+		{"fri_pow_response", challenges.FriChallenges.FriPowResponse, 70715523064019},
+		{"fri_query_indices[0]", challenges.FriChallenges.FriQueryIndices[0], 11890500485816111017},
+	}
+	for _, e := range expected {
+		if e.actual != e.expected {
+			t.Errorf("%s: expected %d, got %d", e.name, e.expected, e.actual)
+		}
+	}
+
+	if uint64(len(challenges.FriChallenges.FriQueryIndices)) != commonCircuitData.Config.FriConfig.NumQueryRounds {
+		t.Errorf("expected %d query indices, got %d", commonCircuitData.Config.FriConfig.NumQueryRounds, len(challenges.FriChallenges.FriQueryIndices))
+	}
+}
+
+func TestVerifierRejectsBadProofs(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mutate func(proofWithPis *types.ProofWithPublicInputsRaw, verifierData *types.VerifierOnlyCircuitDataRaw)
+	}{
+		{
+			name: "public input",
+			mutate: func(proofWithPis *types.ProofWithPublicInputsRaw, verifierData *types.VerifierOnlyCircuitDataRaw) {
+				proofWithPis.PublicInputs[0] += 1
+			},
+		},
+		{
+			name: "circuit digest",
+			mutate: func(proofWithPis *types.ProofWithPublicInputsRaw, verifierData *types.VerifierOnlyCircuitDataRaw) {
+				verifierData.CircuitDigest = "1"
+			},
+		},
+		{
+			name: "wire opening",
+			mutate: func(proofWithPis *types.ProofWithPublicInputsRaw, verifierData *types.VerifierOnlyCircuitDataRaw) {
+				proofWithPis.Proof.Openings.Wires[0][0] += 1
+			},
+		},
+		{
+			name: "initial tree leaf",
+			mutate: func(proofWithPis *types.ProofWithPublicInputsRaw, verifierData *types.VerifierOnlyCircuitDataRaw) {
+				proofWithPis.Proof.OpeningProof.QueryRoundProofs[0].InitialTreesProof.EvalsProofs[1].LeafElements[0] += 1
+			},
+		},
+		{
+			name: "pow witness",
+			mutate: func(proofWithPis *types.ProofWithPublicInputsRaw, verifierData *types.VerifierOnlyCircuitDataRaw) {
+				proofWithPis.Proof.OpeningProof.PowWitness += 1
+			},
+		},
+		{
+			name: "non-canonical final poly coefficient",
+			mutate: func(proofWithPis *types.ProofWithPublicInputsRaw, verifierData *types.VerifierOnlyCircuitDataRaw) {
+				proofWithPis.Proof.OpeningProof.FinalPoly.Coeffs[0][0] = 18446744069414584321
+			},
+		},
+		{
+			name: "missing quotient opening",
+			mutate: func(proofWithPis *types.ProofWithPublicInputsRaw, verifierData *types.VerifierOnlyCircuitDataRaw) {
+				openings := &proofWithPis.Proof.Openings
+				openings.QuotientPolys = openings.QuotientPolys[1:]
+			},
+		},
+	}
+
+	commonCircuitData, _, _ := readTestData("step")
+	verifier := native.NewVerifier(commonCircuitData)
+
+	for _, testCase := range testCases {
+		// Re-read the fixtures, since the mutations modify the proof's slices in place.
+		_, proofWithPis, verifierOnlyCircuitData := readTestData("step")
+		testCase.mutate(&proofWithPis, &verifierOnlyCircuitData)
+
+		if err := verifier.Verify(proofWithPis, verifierOnlyCircuitData); err == nil {
+			t.Errorf("%s: expected the proof to be rejected", testCase.name)
+		}
+	}
+}
+
+// A custom gate that only implements gates.Gate, and so can't be evaluated out of circuit.
+type circuitOnlyGate struct {
+	gates.Gate
+}
+
+func TestVerifierRejectsCircuitOnlyGates(t *testing.T) {
+	commonCircuitData, proofWithPis, verifierOnlyCircuitData := readTestData("decode_block")
+
+	registry := gates.NewRegistry()
+	err := registry.Register("CircuitOnlyGate", func(map[string]string) gates.Gate {
+		return circuitOnlyGate{gates.NewNoopGate()}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commonCircuitData.GateIds = append(append([]string{}, commonCircuitData.GateIds...), "CircuitOnlyGate")
+
+	err = native.NewVerifierWithRegistry(commonCircuitData, registry).Verify(proofWithPis, verifierOnlyCircuitData)
+	if err == nil || !strings.Contains(err.Error(), "CircuitOnlyGate") {
+		t.Errorf("expected an error for a gate that can't be evaluated out of circuit, got %v", err)
+	}
+}
+
+func TestVerifierRejectsUnknownGates(t *testing.T) {
+	commonCircuitData, proofWithPis, verifierOnlyCircuitData := readTestData("decode_block")
+	commonCircuitData.GateIds = append(append([]string{}, commonCircuitData.GateIds...), "UnknownGate")
+
+	err := native.NewVerifier(commonCircuitData).Verify(proofWithPis, verifierOnlyCircuitData)
+	if err == nil || !strings.Contains(err.Error(), "unknown gate ID UnknownGate") {
+		t.Errorf("expected an error for an unknown gate ID, got %v", err)
+	}
+}
@@ -84,3 +84,26 @@ func (g *ArithmeticExtensionGate) EvalUnfiltered(
 
 	return constraints
 }
+
+func (g *ArithmeticExtensionGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	const0 := vars.localConstants[0]
+	const1 := vars.localConstants[1]
+
+	constraints := []gl.QuadraticExtension{}
+	for i := uint64(0); i < g.numOps; i++ {
+		multiplicand0 := vars.GetLocalExtAlgebra(g.wiresIthMultiplicand0(i))
+		multiplicand1 := vars.GetLocalExtAlgebra(g.wiresIthMultiplicand1(i))
+		addend := vars.GetLocalExtAlgebra(g.wiresIthAddend(i))
+		output := vars.GetLocalExtAlgebra(g.wiresIthOutput(i))
+
+		scaledMul := multiplicand0.Mul(multiplicand1).ScalarMul(const0)
+		computedOutput := addend.ScalarMul(const1).Add(scaledMul)
+
+		diff := output.Sub(computedOutput)
+		for j := 0; j < gl.D; j++ {
+			constraints = append(constraints, diff[j])
+		}
+	}
+
+	return constraints
+}
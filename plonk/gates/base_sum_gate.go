@@ -94,3 +94,26 @@ func (g *BaseSumGate) EvalUnfiltered(
 
 	return constraints
 }
+
+func (g *BaseSumGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	sum := vars.localWires[BASESUM_GATE_WIRE_SUM]
+	limbs := make([]gl.QuadraticExtension, g.numLimbs)
+	limbIndices := g.limbs()
+	for i, limbIdx := range limbIndices {
+		limbs[i] = vars.localWires[limbIdx]
+	}
+
+	computedSum := gl.ReduceWithPowers(limbs, gl.NewQuadraticExtensionFromUint64(g.base))
+
+	var constraints []gl.QuadraticExtension
+	constraints = append(constraints, computedSum.Sub(sum))
+	for _, limb := range limbs {
+		acc := gl.OneQuadraticExtension()
+		for i := uint64(0); i < g.base; i++ {
+			acc = acc.Mul(limb.Sub(gl.NewQuadraticExtensionFromUint64(i)))
+		}
+		constraints = append(constraints, acc)
+	}
+
+	return constraints
+}
@@ -0,0 +1,244 @@
+package gates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/consensys/gnark/frontend"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+)
+
+var comparisonGateRegex = regexp.MustCompile("ComparisonGate { num_bits: (?P<numBits>[0-9]+), num_chunks: (?P<numChunks>[0-9]+), _phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField> }<D=(?P<base>[0-9]+)>")
+
+func deserializeComparisonGate(parameters map[string]string) Gate {
+	// Has the format "ComparisonGate { num_bits: 32, num_chunks: 16, _phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField> }<D=2>"
+	numBits, hasNumBits := parameters["numBits"]
+	numChunks, hasNumChunks := parameters["numChunks"]
+	if !hasNumBits || !hasNumChunks {
+		panic("Missing field num_bits or num_chunks in ComparisonGate")
+	}
+
+	numBitsInt, err := strconv.Atoi(numBits)
+	if err != nil {
+		panic("Invalid num_bits field in ComparisonGate")
+	}
+
+	numChunksInt, err := strconv.Atoi(numChunks)
+	if err != nil || numChunksInt == 0 {
+		panic("Invalid num_chunks field in ComparisonGate")
+	}
+
+	base, hasBase := parameters["base"]
+	if !hasBase {
+		panic("Missing field base in ComparisonGate")
+	}
+
+	baseInt, err := strconv.Atoi(base)
+	if err != nil {
+		panic("Invalid base field in ComparisonGate")
+	}
+
+	if baseInt != gl.D {
+		panic("Expected base field in ComparisonGate to equal gl.D")
+	}
+
+	return NewComparisonGate(uint64(numBitsInt), uint64(numChunksInt))
+}
+
+// Computes whether first_input <= second_input, for inputs of num_bits bits that are split into
+// num_chunks chunks.  From the plonky2-u32 crate.
+type ComparisonGate struct {
+	numBits   uint64
+	numChunks uint64
+}
+
+func NewComparisonGate(numBits uint64, numChunks uint64) *ComparisonGate {
+	return &ComparisonGate{
+		numBits:   numBits,
+		numChunks: numChunks,
+	}
+}
+
+func (g *ComparisonGate) Id() string {
+	return fmt.Sprintf(
+		"ComparisonGate { num_bits: %d, num_chunks: %d, _phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField> }<D=%d>",
+		g.numBits,
+		g.numChunks,
+		gl.D,
+	)
+}
+
+func (g *ComparisonGate) chunkBits() uint64 {
+	return (g.numBits + g.numChunks - 1) / g.numChunks
+}
+
+func (g *ComparisonGate) WireFirstInput() uint64 {
+	return 0
+}
+
+func (g *ComparisonGate) WireSecondInput() uint64 {
+	return 1
+}
+
+func (g *ComparisonGate) WireResultBool() uint64 {
+	return 2
+}
+
+func (g *ComparisonGate) WireMostSignificantDiff() uint64 {
+	return 3
+}
+
+func (g *ComparisonGate) WireFirstChunkVal(chunk uint64) uint64 {
+	return 4 + chunk
+}
+
+func (g *ComparisonGate) WireSecondChunkVal(chunk uint64) uint64 {
+	return 4 + g.numChunks + chunk
+}
+
+func (g *ComparisonGate) WireEqualityDummy(chunk uint64) uint64 {
+	return 4 + 2*g.numChunks + chunk
+}
+
+func (g *ComparisonGate) WireChunksEqual(chunk uint64) uint64 {
+	return 4 + 3*g.numChunks + chunk
+}
+
+func (g *ComparisonGate) WireIntermediateValue(chunk uint64) uint64 {
+	return 4 + 4*g.numChunks + chunk
+}
+
+// The bitIndex-th bit of 2^chunk_bits + most_significant_diff.
+func (g *ComparisonGate) WireMostSignificantDiffBit(bitIndex uint64) uint64 {
+	return 4 + 5*g.numChunks + bitIndex
+}
+
+func (g *ComparisonGate) EvalUnfiltered(
+	api frontend.API,
+	glApi *gl.Chip,
+	vars EvaluationVars,
+) []gl.QuadraticExtensionVariable {
+	one := gl.OneExtension()
+	chunkBits := g.chunkBits()
+	chunkSize := uint64(1) << chunkBits
+	chunkBase := gl.NewQuadraticExtensionVariable(gl.NewVariable(chunkSize), gl.Zero())
+
+	firstInput := vars.localWires[g.WireFirstInput()]
+	secondInput := vars.localWires[g.WireSecondInput()]
+
+	// Get the chunks and check that they add up to the inputs.
+	firstChunks := make([]gl.QuadraticExtensionVariable, g.numChunks)
+	secondChunks := make([]gl.QuadraticExtensionVariable, g.numChunks)
+	for i := uint64(0); i < g.numChunks; i++ {
+		firstChunks[i] = vars.localWires[g.WireFirstChunkVal(i)]
+		secondChunks[i] = vars.localWires[g.WireSecondChunkVal(i)]
+	}
+
+	constraints := []gl.QuadraticExtensionVariable{}
+	constraints = append(constraints, glApi.SubExtension(glApi.ReduceWithPowers(firstChunks, chunkBase), firstInput))
+	constraints = append(constraints, glApi.SubExtension(glApi.ReduceWithPowers(secondChunks, chunkBase), secondInput))
+
+	mostSignificantDiffSoFar := gl.ZeroExtension()
+	for i := uint64(0); i < g.numChunks; i++ {
+		// Range check the chunks to be less than chunkSize.
+		constraints = append(constraints, evalRangeCheck(glApi, firstChunks[i], chunkSize))
+		constraints = append(constraints, evalRangeCheck(glApi, secondChunks[i], chunkSize))
+
+		difference := glApi.SubExtension(secondChunks[i], firstChunks[i])
+		equalityDummy := vars.localWires[g.WireEqualityDummy(i)]
+		chunksEqual := vars.localWires[g.WireChunksEqual(i)]
+
+		// Two constraints to check that chunksEqual is valid.
+		constraints = append(constraints, glApi.SubExtension(
+			glApi.MulExtension(difference, equalityDummy),
+			glApi.SubExtension(one, chunksEqual),
+		))
+		constraints = append(constraints, glApi.MulExtension(chunksEqual, difference))
+
+		// Update mostSignificantDiffSoFar.
+		intermediateValue := vars.localWires[g.WireIntermediateValue(i)]
+		constraints = append(constraints, glApi.SubExtension(
+			intermediateValue,
+			glApi.MulExtension(chunksEqual, mostSignificantDiffSoFar),
+		))
+		mostSignificantDiffSoFar = glApi.MulAddExtension(glApi.SubExtension(one, chunksEqual), difference, intermediateValue)
+	}
+
+	mostSignificantDiff := vars.localWires[g.WireMostSignificantDiff()]
+	constraints = append(constraints, glApi.SubExtension(mostSignificantDiff, mostSignificantDiffSoFar))
+
+	// Range check the bits.
+	mostSignificantDiffBits := make([]gl.QuadraticExtensionVariable, chunkBits+1)
+	for i := range mostSignificantDiffBits {
+		bit := vars.localWires[g.WireMostSignificantDiffBit(uint64(i))]
+		mostSignificantDiffBits[i] = bit
+		constraints = append(constraints, glApi.MulExtension(bit, glApi.SubExtension(one, bit)))
+	}
+
+	two := gl.NewQuadraticExtensionVariable(gl.NewVariable(2), gl.Zero())
+	bitsCombined := glApi.ReduceWithPowers(mostSignificantDiffBits, two)
+	constraints = append(constraints, glApi.SubExtension(glApi.AddExtension(chunkBase, mostSignificantDiff), bitsCombined))
+
+	// Iff first <= second, the top (chunk_bits + 1st) bit of 2^chunk_bits + most_significant_diff is 1.
+	resultBool := vars.localWires[g.WireResultBool()]
+	constraints = append(constraints, glApi.SubExtension(resultBool, mostSignificantDiffBits[chunkBits]))
+
+	return constraints
+}
+
+func (g *ComparisonGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	one := gl.OneQuadraticExtension()
+	chunkBits := g.chunkBits()
+	chunkSize := uint64(1) << chunkBits
+	chunkBase := gl.NewQuadraticExtensionFromUint64(chunkSize)
+
+	firstInput := vars.localWires[g.WireFirstInput()]
+	secondInput := vars.localWires[g.WireSecondInput()]
+
+	firstChunks := make([]gl.QuadraticExtension, g.numChunks)
+	secondChunks := make([]gl.QuadraticExtension, g.numChunks)
+	for i := uint64(0); i < g.numChunks; i++ {
+		firstChunks[i] = vars.localWires[g.WireFirstChunkVal(i)]
+		secondChunks[i] = vars.localWires[g.WireSecondChunkVal(i)]
+	}
+
+	constraints := []gl.QuadraticExtension{}
+	constraints = append(constraints, gl.ReduceWithPowers(firstChunks, chunkBase).Sub(firstInput))
+	constraints = append(constraints, gl.ReduceWithPowers(secondChunks, chunkBase).Sub(secondInput))
+
+	mostSignificantDiffSoFar := gl.ZeroQuadraticExtension()
+	for i := uint64(0); i < g.numChunks; i++ {
+		constraints = append(constraints, evalRangeCheckNative(firstChunks[i], chunkSize))
+		constraints = append(constraints, evalRangeCheckNative(secondChunks[i], chunkSize))
+
+		difference := secondChunks[i].Sub(firstChunks[i])
+		equalityDummy := vars.localWires[g.WireEqualityDummy(i)]
+		chunksEqual := vars.localWires[g.WireChunksEqual(i)]
+
+		constraints = append(constraints, difference.Mul(equalityDummy).Sub(one.Sub(chunksEqual)))
+		constraints = append(constraints, chunksEqual.Mul(difference))
+
+		intermediateValue := vars.localWires[g.WireIntermediateValue(i)]
+		constraints = append(constraints, intermediateValue.Sub(chunksEqual.Mul(mostSignificantDiffSoFar)))
+		mostSignificantDiffSoFar = intermediateValue.Add(one.Sub(chunksEqual).Mul(difference))
+	}
+
+	mostSignificantDiff := vars.localWires[g.WireMostSignificantDiff()]
+	constraints = append(constraints, mostSignificantDiff.Sub(mostSignificantDiffSoFar))
+
+	mostSignificantDiffBits := make([]gl.QuadraticExtension, chunkBits+1)
+	for i := range mostSignificantDiffBits {
+		bit := vars.localWires[g.WireMostSignificantDiffBit(uint64(i))]
+		mostSignificantDiffBits[i] = bit
+		constraints = append(constraints, bit.Mul(one.Sub(bit)))
+	}
+
+	bitsCombined := gl.ReduceWithPowers(mostSignificantDiffBits, gl.NewQuadraticExtensionFromUint64(2))
+	constraints = append(constraints, chunkBase.Add(mostSignificantDiff).Sub(bitsCombined))
+
+	resultBool := vars.localWires[g.WireResultBool()]
+	constraints = append(constraints, resultBool.Sub(mostSignificantDiffBits[chunkBits]))
+
+	return constraints
+}
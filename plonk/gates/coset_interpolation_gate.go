@@ -224,3 +224,72 @@ func (g *CosetInterpolationGate) EvalUnfiltered(
 
 	return constraints
 }
+
+func (g *CosetInterpolationGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	constraints := []gl.QuadraticExtension{}
+
+	shift := vars.localWires[g.wireShift()]
+	evaluationPoint := vars.GetLocalExtAlgebra(g.wiresEvaluationPoint())
+	shiftedEvaluationPoint := vars.GetLocalExtAlgebra(g.wiresShiftedEvaluationPoint())
+
+	negShift := gl.ZeroQuadraticExtension().Sub(shift)
+
+	tmp := shiftedEvaluationPoint.ScalarMul(negShift).Add(evaluationPoint)
+	for i := 0; i < gl.D; i++ {
+		constraints = append(constraints, tmp[i])
+	}
+
+	domain := gl.TwoAdicSubgroup(g.subgroupBits)
+	values := []gl.QuadraticExtensionAlgebra{}
+	for i := uint64(0); i < g.numPoints(); i++ {
+		values = append(values, vars.GetLocalExtAlgebra(g.wiresValue(i)))
+	}
+	weights := g.barycentricWeights
+
+	computedEval, computedProd := gl.PartialInterpolateExtAlgebra(
+		domain[:g.degree],
+		values[:g.degree],
+		weights[:g.degree],
+		shiftedEvaluationPoint,
+		gl.ZeroQuadraticExtensionAlgebra(),
+		gl.OneQuadraticExtensionAlgebra(),
+	)
+
+	for i := uint64(0); i < g.numIntermediates(); i++ {
+		intermediateEval := vars.GetLocalExtAlgebra(g.wiresIntermediateEval(i))
+		intermediateProd := vars.GetLocalExtAlgebra(g.wiresIntermediateProd(i))
+
+		evalDiff := intermediateEval.Sub(computedEval)
+		for j := 0; j < gl.D; j++ {
+			constraints = append(constraints, evalDiff[j])
+		}
+
+		prodDiff := intermediateProd.Sub(computedProd)
+		for j := 0; j < gl.D; j++ {
+			constraints = append(constraints, prodDiff[j])
+		}
+
+		startIndex := 1 + (g.degree-1)*(i+1)
+		endIndex := startIndex + g.degree - 1
+		if endIndex > g.numPoints() {
+			endIndex = g.numPoints()
+		}
+
+		computedEval, computedProd = gl.PartialInterpolateExtAlgebra(
+			domain[startIndex:endIndex],
+			values[startIndex:endIndex],
+			weights[startIndex:endIndex],
+			shiftedEvaluationPoint,
+			intermediateEval,
+			intermediateProd,
+		)
+	}
+
+	evaluationValue := vars.GetLocalExtAlgebra(g.wiresEvaluationValue())
+	evalDiff := evaluationValue.Sub(computedEval)
+	for j := 0; j < gl.D; j++ {
+		constraints = append(constraints, evalDiff[j])
+	}
+
+	return constraints
+}
@@ -11,7 +11,8 @@ type EvaluateGatesChip struct {
 	gates              []Gate
 	numGateConstraints uint64
 
-	selectorsInfo SelectorsInfo
+	selectorsInfo      SelectorsInfo
+	numLookupSelectors uint64
 }
 
 func NewEvaluateGatesChip(
@@ -19,6 +20,7 @@ func NewEvaluateGatesChip(
 	gates []Gate,
 	numGateConstraints uint64,
 	selectorsInfo SelectorsInfo,
+	numLookupSelectors uint64,
 ) *EvaluateGatesChip {
 	return &EvaluateGatesChip{
 		api: api,
@@ -26,7 +28,8 @@ func NewEvaluateGatesChip(
 		gates:              gates,
 		numGateConstraints: numGateConstraints,
 
-		selectorsInfo: selectorsInfo,
+		selectorsInfo:      selectorsInfo,
+		numLookupSelectors: numLookupSelectors,
 	}
 }
 
@@ -61,11 +64,13 @@ func (g *EvaluateGatesChip) evalFiltered(
 	selectorIndex uint64,
 	groupRange Range,
 	numSelectors uint64,
+	numLookupSelectors uint64,
 ) []gl.QuadraticExtensionVariable {
 	glApi := gl.New(g.api)
 	filter := g.computeFilter(row, groupRange, vars.localConstants[selectorIndex], numSelectors > 1)
 
-	vars.RemovePrefix(numSelectors)
+	// The gate's constants come after the selectors and the lookup selectors.
+	vars.RemovePrefix(numSelectors + numLookupSelectors)
 
 	unfiltered := gate.EvalUnfiltered(g.api, glApi, vars)
 	for i := range unfiltered {
@@ -91,6 +96,7 @@ func (g *EvaluateGatesChip) EvaluateGateConstraints(vars EvaluationVars) []gl.Qu
 			selectorIndex,
 			g.selectorsInfo.groups[selectorIndex],
 			g.selectorsInfo.NumSelectors(),
+			g.numLookupSelectors,
 		)
 
 		for i, constraint := range gateConstraints {
@@ -103,3 +109,99 @@ func (g *EvaluateGatesChip) EvaluateGateConstraints(vars EvaluationVars) []gl.Qu
 
 	return constraints
 }
+
+// The out of circuit counterpart of EvaluateGatesChip.
+type NativeEvaluateGates struct {
+	gates              []NativeGate
+	numGateConstraints uint64
+
+	selectorsInfo      SelectorsInfo
+	numLookupSelectors uint64
+}
+
+func NewNativeEvaluateGates(
+	gates []NativeGate,
+	numGateConstraints uint64,
+	selectorsInfo SelectorsInfo,
+	numLookupSelectors uint64,
+) *NativeEvaluateGates {
+	return &NativeEvaluateGates{
+		gates:              gates,
+		numGateConstraints: numGateConstraints,
+
+		selectorsInfo:      selectorsInfo,
+		numLookupSelectors: numLookupSelectors,
+	}
+}
+
+func (g *NativeEvaluateGates) computeFilter(
+	row uint64,
+	groupRange Range,
+	s gl.QuadraticExtension,
+	manySelector bool,
+) gl.QuadraticExtension {
+	product := gl.OneQuadraticExtension()
+	for i := groupRange.start; i < groupRange.end; i++ {
+		if i == uint64(row) {
+			continue
+		}
+		product = product.Mul(gl.NewQuadraticExtensionFromUint64(i).Sub(s))
+	}
+
+	if manySelector {
+		product = product.Mul(gl.NewQuadraticExtensionFromUint64(UNUSED_SELECTOR).Sub(s))
+	}
+
+	return product
+}
+
+func (g *NativeEvaluateGates) evalFiltered(
+	gate NativeGate,
+	vars NativeEvaluationVars,
+	row uint64,
+	selectorIndex uint64,
+	groupRange Range,
+	numSelectors uint64,
+	numLookupSelectors uint64,
+) []gl.QuadraticExtension {
+	filter := g.computeFilter(row, groupRange, vars.localConstants[selectorIndex], numSelectors > 1)
+
+	// The gate's constants come after the selectors and the lookup selectors.
+	vars.RemovePrefix(numSelectors + numLookupSelectors)
+
+	unfiltered := gate.EvalUnfilteredNative(vars)
+	for i := range unfiltered {
+		unfiltered[i] = unfiltered[i].Mul(filter)
+	}
+	return unfiltered
+}
+
+func (g *NativeEvaluateGates) EvaluateGateConstraints(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	constraints := make([]gl.QuadraticExtension, g.numGateConstraints)
+	for i := range constraints {
+		constraints[i] = gl.ZeroQuadraticExtension()
+	}
+
+	for i, gate := range g.gates {
+		selectorIndex := g.selectorsInfo.selectorIndices[i]
+
+		gateConstraints := g.evalFiltered(
+			gate,
+			vars,
+			uint64(i),
+			selectorIndex,
+			g.selectorsInfo.groups[selectorIndex],
+			g.selectorsInfo.NumSelectors(),
+			g.numLookupSelectors,
+		)
+
+		for i, constraint := range gateConstraints {
+			if uint64(i) >= g.numGateConstraints {
+				panic("num_constraints() gave too low of a number")
+			}
+			constraints[i] = constraints[i].Add(constraint)
+		}
+	}
+
+	return constraints
+}
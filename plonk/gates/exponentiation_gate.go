@@ -126,3 +126,46 @@ func (g *ExponentiationGate) EvalUnfiltered(
 
 	return constraints
 }
+
+func (g *ExponentiationGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	base := vars.localWires[g.wireBase()]
+
+	var powerBits []gl.QuadraticExtension
+	for i := uint64(0); i < g.numPowerBits; i++ {
+		powerBits = append(powerBits, vars.localWires[g.wirePowerBit(i)])
+	}
+
+	var intermediateValues []gl.QuadraticExtension
+	for i := uint64(0); i < g.numPowerBits; i++ {
+		intermediateValues = append(intermediateValues, vars.localWires[g.wireIntermediateValue(i)])
+	}
+
+	output := vars.localWires[g.wireOutput()]
+
+	var constraints []gl.QuadraticExtension
+
+	for i := uint64(0); i < g.numPowerBits; i++ {
+		var prevIntermediateValue gl.QuadraticExtension
+		if i == 0 {
+			prevIntermediateValue = gl.OneQuadraticExtension()
+		} else {
+			prevIntermediateValue = intermediateValues[i-1].Mul(intermediateValues[i-1])
+		}
+
+		// powerBits is in LE order, but we accumulate in BE order.
+		curBit := powerBits[g.numPowerBits-i-1]
+
+		// Do a polynomial representation of generaized select (where the selector variable doesn't have to be binary)
+		// if b { x } else { y }
+		// i.e. `bx - (by-y)`.
+		tmp := curBit.Sub(gl.OneQuadraticExtension())
+		mulBy := curBit.Mul(base).Sub(tmp)
+		intermediateValueDiff := prevIntermediateValue.Mul(mulBy).Sub(intermediateValues[i])
+		constraints = append(constraints, intermediateValueDiff)
+	}
+
+	outputDiff := output.Sub(intermediateValues[g.numPowerBits-1])
+	constraints = append(constraints, outputDiff)
+
+	return constraints
+}
@@ -3,6 +3,7 @@ package gates
 import (
 	"fmt"
 	"regexp"
+	"sync"
 
 	"github.com/consensys/gnark/frontend"
 	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
@@ -17,38 +18,161 @@ type Gate interface {
 	) []gl.QuadraticExtensionVariable
 }
 
-var gateRegexHandlers = map[*regexp.Regexp]func(parameters map[string]string) Gate{
-	arithmeticGateRegex:          deserializeArithmeticGate,
-	arithmeticExtensionGateRegex: deserializeExtensionArithmeticGate,
-	baseSumGateRegex:             deserializeBaseSumGate,
-	constantGateRegex:            deserializeConstantGate,
-	cosetInterpolationGateRegex:  deserializeCosetInterpolationGate,
-	exponentiationGateRegex:      deserializeExponentiationGate,
-	mulExtensionGateRegex:        deserializeMulExtensionGate,
-	noopGateRegex:                deserializeNoopGate,
-	poseidonGateRegex:            deserializePoseidonGate,
-	poseidonMdsGateRegex:         deserializePoseidonMdsGate,
-	publicInputGateRegex:         deserializePublicInputGate,
-	randomAccessGateRegex:        deserializeRandomAccessGate,
-	reducingExtensionGateRegex:   deserializeReducingExtensionGate,
-	reducingGateRegex:            deserializeReducingGate,
+// A gate that can also be evaluated out of circuit, which the native verifier requires.  It's
+// separate from Gate so that custom gates only have to implement it to be used with the native
+// verifier.  All of the built-in gates implement it.
+type NativeGate interface {
+	Gate
+	EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension
 }
 
-func GateInstanceFromId(gateId string) Gate {
-	for regex, handler := range gateRegexHandlers {
-		matches := regex.FindStringSubmatch(gateId)
-		if matches != nil {
-			parameters := make(map[string]string)
-			for i, name := range regex.SubexpNames() {
-				if i != 0 && name != "" {
-					parameters[name] = matches[i]
-				}
-			}
-
-			if matches != nil {
-				return handler(parameters)
-			}
+// Builds a gate from the named capture groups of the pattern that matched its ID.
+type GateConstructor func(parameters map[string]string) Gate
+
+type gateHandler struct {
+	pattern     string
+	regex       *regexp.Regexp
+	constructor GateConstructor
+}
+
+// Maps the gate IDs of plonky2's common circuit data to Gate implementations.  A pattern must
+// match the whole gate ID, and a gate ID that is matched by more than one pattern is rejected
+// rather than resolved arbitrarily.
+type Registry struct {
+	mu       sync.RWMutex
+	handlers []gateHandler
+}
+
+// Returns a registry with the gates of plonky2's standard library and of the plonky2-u32 crate.
+func NewRegistry() *Registry {
+	r := &Registry{}
+	for _, builtin := range []struct {
+		regex       *regexp.Regexp
+		constructor GateConstructor
+	}{
+		{arithmeticGateRegex, deserializeArithmeticGate},
+		{arithmeticExtensionGateRegex, deserializeExtensionArithmeticGate},
+		{baseSumGateRegex, deserializeBaseSumGate},
+		{comparisonGateRegex, deserializeComparisonGate},
+		{constantGateRegex, deserializeConstantGate},
+		{cosetInterpolationGateRegex, deserializeCosetInterpolationGate},
+		{exponentiationGateRegex, deserializeExponentiationGate},
+		{lookupGateRegex, deserializeLookupGate},
+		{lookupTableGateRegex, deserializeLookupTableGate},
+		{mulExtensionGateRegex, deserializeMulExtensionGate},
+		{noopGateRegex, deserializeNoopGate},
+		{poseidonGateRegex, deserializePoseidonGate},
+		{poseidonMdsGateRegex, deserializePoseidonMdsGate},
+		{publicInputGateRegex, deserializePublicInputGate},
+		{randomAccessGateRegex, deserializeRandomAccessGate},
+		{reducingExtensionGateRegex, deserializeReducingExtensionGate},
+		{reducingGateRegex, deserializeReducingGate},
+		{u32AddManyGateRegex, deserializeU32AddManyGate},
+		{u32ArithmeticGateRegex, deserializeU32ArithmeticGate},
+		{u32RangeCheckGateRegex, deserializeU32RangeCheckGate},
+		{u32SubtractionGateRegex, deserializeU32SubtractionGate},
+	} {
+		if err := r.Register(builtin.regex.String(), builtin.constructor); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Adds a gate to the registry.  The pattern is a regular expression that must match the whole
+// gate ID, and its named capture groups are passed to the constructor.
+//
+// Only invalid patterns and patterns that are already registered are rejected here: whether two
+// patterns overlap can't be told in general, so a gate ID that more than one pattern matches is
+// reported as an error when it's looked up instead.
+func (r *Registry) Register(pattern string, constructor GateConstructor) error {
+	regex, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return fmt.Errorf("invalid gate pattern %s: %w", pattern, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, handler := range r.handlers {
+		if handler.pattern == pattern {
+			return fmt.Errorf("gate pattern %s is already registered", pattern)
+		}
+	}
+	r.handlers = append(r.handlers, gateHandler{pattern: pattern, regex: regex, constructor: constructor})
+	return nil
+}
+
+// Returns the handler of the only pattern that matches gateId, and the values of its named capture
+// groups.
+func (r *Registry) findHandler(gateId string) (*gateHandler, map[string]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var match *gateHandler
+	var matches []string
+	for i := range r.handlers {
+		handlerMatches := r.handlers[i].regex.FindStringSubmatch(gateId)
+		if handlerMatches == nil {
+			continue
 		}
+		if match != nil {
+			return nil, nil, fmt.Errorf("gate ID %s matches both %s and %s", gateId, match.pattern, r.handlers[i].pattern)
+		}
+		match, matches = &r.handlers[i], handlerMatches
+	}
+	if match == nil {
+		return nil, nil, fmt.Errorf("unknown gate ID %s", gateId)
 	}
-	panic(fmt.Sprintf("Unknown gate ID %s", gateId))
+
+	parameters := make(map[string]string)
+	for i, name := range match.regex.SubexpNames() {
+		if i != 0 && name != "" {
+			parameters[name] = matches[i]
+		}
+	}
+	return match, parameters, nil
+}
+
+// Panics if the gate ID is unknown, ambiguous or has invalid parameters.  See TryGateInstanceFromId
+// for a version that returns an error.
+func (r *Registry) GateInstanceFromId(gateId string) Gate {
+	handler, parameters, err := r.findHandler(gateId)
+	if err != nil {
+		panic(err)
+	}
+	return handler.constructor(parameters)
+}
+
+// Like GateInstanceFromId, but returns an error when the gate ID is unknown, ambiguous or has
+// invalid parameters.  Constructors panic on invalid parameters, so that panic is returned as an
+// error too.
+func (r *Registry) TryGateInstanceFromId(gateId string) (gate Gate, err error) {
+	handler, parameters, err := r.findHandler(gateId)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("invalid gate ID %s: %v", gateId, rec)
+		}
+	}()
+	return handler.constructor(parameters), nil
+}
+
+// The registry used by GateInstanceFromId, and by the verifiers unless they're given another one.
+var DefaultRegistry = NewRegistry()
+
+// Adds a gate to DefaultRegistry.  See Registry.Register.
+func RegisterGate(pattern string, constructor GateConstructor) error {
+	return DefaultRegistry.Register(pattern, constructor)
+}
+
+func GateInstanceFromId(gateId string) Gate {
+	return DefaultRegistry.GateInstanceFromId(gateId)
+}
+
+func TryGateInstanceFromId(gateId string) (Gate, error) {
+	return DefaultRegistry.TryGateInstanceFromId(gateId)
 }
@@ -1,7 +1,11 @@
 package gates_test
 
 import (
+	"encoding/json"
 	"errors"
+	"math/rand"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -766,3 +770,632 @@ func TestGates(t *testing.T) {
 		)
 	}
 }
+
+func toNativeQuadraticExtensions(variables []gl.QuadraticExtensionVariable) []gl.QuadraticExtension {
+	elements := make([]gl.QuadraticExtension, len(variables))
+	for i, variable := range variables {
+		for j := 0; j < 2; j++ {
+			if _, err := elements[i][j].SetInterface(variable[j].Limb); err != nil {
+				panic(err)
+			}
+		}
+	}
+	return elements
+}
+
+func TestGatesNative(t *testing.T) {
+	commonCircuitData := types.ReadCommonCircuitData("../../testdata/decode_block/common_circuit_data.json")
+	numSelectors := commonCircuitData.SelectorsInfo.NumSelectors()
+
+	var nativePublicInputsHash poseidon.GoldilocksNativeHashOut
+	for i := range publicInputsHash {
+		nativePublicInputsHash[i].SetInterface(publicInputsHash[i].Limb)
+	}
+
+	nativeLocalConstants := toNativeQuadraticExtensions(localConstants)
+	nativeLocalWires := toNativeQuadraticExtensions(localWires)
+
+	gateTests := []struct {
+		testGate            gates.NativeGate
+		expectedConstraints []gl.QuadraticExtensionVariable
+	}{
+		{gates.NewPublicInputGate(), publicInputGateExpectedConstraints},
+		{gates.NewBaseSumGate(63, 2), baseSumGateExpectedConstraints},
+		{gates.NewArithmeticGate(20), arithmeticGateExpectedConstraints},
+		{gates.NewRandomAccessGate(4, 4, 2), randomAccessGateExpectedConstraints},
+		{gates.NewPoseidonGate(), poseidonGateExpectedConstraints},
+		{gates.NewArithmeticExtensionGate(10), arithmeticExtensionGateExpectedConstraints},
+		{gates.NewMultiplicationExtensionGate(13), mulExtensionGateExpectedConstraints},
+		{gates.NewReducingExtensionGate(33), reducingExtensionGateExpectedConstraints},
+		{gates.NewReducingGate(44), reducingGateExpectedConstraints},
+		{gates.NewCosetInterpolationGate(
+			4,
+			6,
+			[]goldilocks.Element{
+				goldilocks.NewElement(17293822565076172801),
+				goldilocks.NewElement(18374686475376656385),
+				goldilocks.NewElement(18446744069413535745),
+				goldilocks.NewElement(281474976645120),
+				goldilocks.NewElement(17592186044416),
+				goldilocks.NewElement(18446744069414584577),
+				goldilocks.NewElement(18446744000695107601),
+				goldilocks.NewElement(18446744065119617025),
+				goldilocks.NewElement(1152921504338411520),
+				goldilocks.NewElement(72057594037927936),
+				goldilocks.NewElement(18446744069415632897),
+				goldilocks.NewElement(18446462594437939201),
+				goldilocks.NewElement(18446726477228539905),
+				goldilocks.NewElement(18446744069414584065),
+				goldilocks.NewElement(68719476720),
+				goldilocks.NewElement(4294967296),
+			},
+		), cosetInterpolationGateExpectedConstraints},
+		{&gates.PoseidonMdsGate{}, poseidonMdsGateExpectedConstraints},
+	}
+
+	for _, test := range gateTests {
+		vars := gates.NewNativeEvaluationVars(nativeLocalConstants[numSelectors:], nativeLocalWires, nativePublicInputsHash)
+
+		constraints := test.testGate.EvalUnfilteredNative(*vars)
+		expectedConstraints := toNativeQuadraticExtensions(test.expectedConstraints)
+
+		if len(constraints) != len(expectedConstraints) {
+			t.Fatalf("%s: gate constraints length mismatch", test.testGate.Id())
+		}
+		for i := range constraints {
+			if !constraints[i].Equal(expectedConstraints[i]) {
+				t.Errorf("%s: constraint %d is %s, expected %s", test.testGate.Id(), i, constraints[i], expectedConstraints[i])
+			}
+		}
+	}
+}
+
+func TestLookupGateIds(t *testing.T) {
+	lutHash := "[210, 32, 9, 0, 255, 117, 61, 14, 82, 240, 3, 77, 189, 45, 90, 201, 16, 38, 149, 7, 222, 108, 55, 173, 64, 11, 250, 128, 93, 36, 180, 5]"
+	for _, gateId := range []string{
+		"LookupGate {num_slots: 40, lut_hash: " + lutHash + "}",
+		"LookupTableGate {num_slots: 26, lut_hash: " + lutHash + ", last_lut_row: 1034}",
+	} {
+		gate, err := gates.TryGateInstanceFromId(gateId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gate.Id() != gateId {
+			t.Errorf("expected id %s, got %s", gateId, gate.Id())
+		}
+
+		// Lookups are checked by the lookup argument rather than by the gates.
+		nativeGate, ok := gate.(gates.NativeGate)
+		if !ok {
+			t.Fatalf("%s can't be evaluated out of circuit", gateId)
+		}
+		if constraints := nativeGate.EvalUnfilteredNative(gates.NativeEvaluationVars{}); len(constraints) != 0 {
+			t.Errorf("%s: expected no constraints, got %d", gateId, len(constraints))
+		}
+	}
+
+	if _, err := gates.TryGateInstanceFromId("LookupGate {num_slots: 40, lut_hash: [256]}"); err == nil {
+		t.Error("expected an error for a lut hash byte out of range")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	registry := gates.NewRegistry()
+
+	// Patterns must match the whole gate ID, so a custom gate whose ID contains the ID of a
+	// standard gate isn't mistaken for it.
+	customGateId := "CustomArithmeticGate { num_ops: 3 }"
+	if _, err := registry.TryGateInstanceFromId(customGateId); err == nil {
+		t.Fatalf("expected %s to be unknown", customGateId)
+	}
+
+	err := registry.Register(`CustomArithmeticGate { num_ops: (?P<numOps>[0-9]+) }`, func(parameters map[string]string) gates.Gate {
+		if parameters["numOps"] != "3" {
+			t.Errorf("expected num_ops 3, got %s", parameters["numOps"])
+		}
+		return gates.NewNoopGate()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.TryGateInstanceFromId(customGateId); err != nil {
+		t.Fatal(err)
+	}
+
+	// The custom gate isn't visible from the default registry.
+	if _, err := gates.TryGateInstanceFromId(customGateId); err == nil {
+		t.Errorf("expected %s to be unknown to the default registry", customGateId)
+	}
+
+	noopConstructor := func(map[string]string) gates.Gate { return gates.NewNoopGate() }
+	if err := registry.Register(`CustomArithmeticGate { num_ops: (?P<numOps>[0-9]+) }`, noopConstructor); err == nil {
+		t.Error("expected an error for a pattern that is already registered")
+	}
+	if err := registry.Register("NoopGate(", noopConstructor); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+
+	// Overlapping patterns can be registered, but a gate ID that matches more than one of them is
+	// an error rather than resolved arbitrarily.
+	if err := registry.Register(`Custom(?P<name>A|B)Gate`, noopConstructor); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register(`Custom(?P<name>B|C)Gate`, noopConstructor); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.TryGateInstanceFromId("CustomAGate"); err != nil {
+		t.Error(err)
+	}
+	if _, err := registry.TryGateInstanceFromId("CustomBGate"); err == nil || !strings.Contains(err.Error(), "matches both") {
+		t.Errorf("expected an error for a gate ID that matches two patterns, got %v", err)
+	}
+	if err := registry.Register("Noop.*", noopConstructor); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.TryGateInstanceFromId("NoopGate"); err == nil || !strings.Contains(err.Error(), "matches both") {
+		t.Errorf("expected an error for a gate ID that matches a built-in and a custom pattern, got %v", err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected GateInstanceFromId to panic for an ambiguous gate ID")
+			}
+		}()
+		registry.GateInstanceFromId("NoopGate")
+	}()
+}
+
+func TestRegistryBuiltinIds(t *testing.T) {
+	lutHash := "[" + strings.Repeat("0, ", 31) + "0]"
+	phantomData := "_phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField>"
+
+	// IDs that plonky2 and plonky2-u32 serialize the built-in gates with, each of which must be
+	// matched by exactly one of the built-in patterns.
+	for _, gateId := range []string{
+		"ArithmeticGate { num_ops: 20 }",
+		"ArithmeticExtensionGate { num_ops: 10 }",
+		"BaseSumGate { num_limbs: 63 } + Base: 2",
+		"ComparisonGate { num_bits: 32, num_chunks: 16, " + phantomData + " }<D=2>",
+		"ConstantGate { num_consts: 2 }",
+		"CosetInterpolationGate { subgroup_bits: 1, degree: 2, barycentric_weights: [9223372034707292161, 9223372034707292160], " + phantomData + " }<D=2>",
+		"ExponentiationGate { num_power_bits: 67, " + phantomData + " }<D=2>",
+		"LookupGate {num_slots: 40, lut_hash: " + lutHash + "}",
+		"LookupTableGate {num_slots: 26, lut_hash: " + lutHash + ", last_lut_row: 1034}",
+		"MulExtensionGate { num_ops: 13 }",
+		"NoopGate",
+		"PoseidonGate(PhantomData<plonky2_field::goldilocks_field::GoldilocksField>)<WIDTH=12>",
+		"PoseidonMdsGate(PhantomData<plonky2_field::goldilocks_field::GoldilocksField>)<WIDTH=12>",
+		"PublicInputGate",
+		"RandomAccessGate { bits: 4, num_copies: 4, num_extra_constants: 2, " + phantomData + " }<D=2>",
+		"ReducingExtensionGate { num_coeffs: 33 }",
+		"ReducingGate { num_coeffs: 44 }",
+		"U32AddManyGate { num_addends: 4, num_ops: 5, " + phantomData + " }",
+		"U32ArithmeticGate { num_ops: 3, " + phantomData + " }",
+		"U32RangeCheckGate { num_input_limbs: 8, " + phantomData + " }",
+		"U32SubtractionGate { num_ops: 6, " + phantomData + " }",
+	} {
+		if _, err := gates.NewRegistry().TryGateInstanceFromId(gateId); err != nil {
+			t.Errorf("%s: %v", gateId, err)
+		}
+	}
+}
+
+// Fill in the wires of one operation of a gate the way the plonky2-u32 generators do.
+func setU32ArithmeticOp(g *gates.U32ArithmeticGate, wires []uint64, i uint64, multiplicand0, multiplicand1, addend uint64) {
+	output := multiplicand0*multiplicand1 + addend
+	wires[g.WireIthMultiplicand0(i)] = multiplicand0
+	wires[g.WireIthMultiplicand1(i)] = multiplicand1
+	wires[g.WireIthAddend(i)] = addend
+	wires[g.WireIthOutputLowHalf(i)] = output & 0xffffffff
+	wires[g.WireIthOutputHighHalf(i)] = output >> 32
+	diff := goldilocks.NewElement(0xffffffff - output>>32)
+	wires[g.WireIthInverse(i)] = new(goldilocks.Element).Inverse(&diff).Uint64()
+	for j := uint64(0); j < gates.U32_ARITHMETIC_GATE_NUM_LIMBS; j++ {
+		wires[g.WireIthOutputJthLimb(i, j)] = (output >> (2 * j)) & 3
+	}
+}
+
+func setU32AddManyOp(g *gates.U32AddManyGate, wires []uint64, i uint64, carry uint64, addends []uint64) {
+	sum := carry
+	wires[g.WireIthCarry(i)] = carry
+	for j, addend := range addends {
+		wires[g.WireIthOpJthAddend(i, uint64(j))] = addend
+		sum += addend
+	}
+	result, outputCarry := sum&0xffffffff, sum>>32
+	wires[g.WireIthOutputResult(i)] = result
+	wires[g.WireIthOutputCarry(i)] = outputCarry
+	for j := uint64(0); j < gates.U32_ADD_MANY_GATE_NUM_RESULT_LIMBS; j++ {
+		wires[g.WireIthOutputJthLimb(i, j)] = (result >> (2 * j)) & 3
+	}
+	for j := uint64(0); j < gates.U32_ADD_MANY_GATE_NUM_CARRY_LIMBS; j++ {
+		wires[g.WireIthOutputJthLimb(i, gates.U32_ADD_MANY_GATE_NUM_RESULT_LIMBS+j)] = (outputCarry >> (2 * j)) & 3
+	}
+}
+
+func setU32SubtractionOp(g *gates.U32SubtractionGate, wires []uint64, i uint64, x, y, borrow uint64) {
+	result, outputBorrow := int64(x)-int64(y)-int64(borrow), uint64(0)
+	if result < 0 {
+		result, outputBorrow = result+(1<<32), 1
+	}
+	wires[g.WireIthInputX(i)] = x
+	wires[g.WireIthInputY(i)] = y
+	wires[g.WireIthInputBorrow(i)] = borrow
+	wires[g.WireIthOutputResult(i)] = uint64(result)
+	wires[g.WireIthOutputBorrow(i)] = outputBorrow
+	for j := uint64(0); j < gates.U32_SUBTRACTION_GATE_NUM_LIMBS; j++ {
+		wires[g.WireIthOutputJthLimb(i, j)] = (uint64(result) >> (2 * j)) & 3
+	}
+}
+
+func setU32RangeCheckInput(g *gates.U32RangeCheckGate, wires []uint64, i uint64, input uint64) {
+	wires[g.WireIthInputLimb(i)] = input
+	for j := uint64(0); j < gates.U32_RANGE_CHECK_GATE_AUX_LIMBS_PER_INPUT_LIMB; j++ {
+		wires[g.WireIthInputLimbJthAuxLimb(i, j)] = (input >> (2 * j)) & 3
+	}
+}
+
+// Only for NewComparisonGate(32, 16), i.e. 2 bit chunks.
+func comparisonRow(g *gates.ComparisonGate, first, second uint64) []uint64 {
+	wires := make([]uint64, 136)
+	wires[g.WireFirstInput()] = first
+	wires[g.WireSecondInput()] = second
+
+	mostSignificantDiff := int64(0)
+	for i := uint64(0); i < 16; i++ {
+		firstChunk, secondChunk := (first>>(2*i))&3, (second>>(2*i))&3
+		wires[g.WireFirstChunkVal(i)] = firstChunk
+		wires[g.WireSecondChunkVal(i)] = secondChunk
+
+		wires[g.WireIntermediateValue(i)] = 0
+		if firstChunk == secondChunk {
+			wires[g.WireChunksEqual(i)] = 1
+			wires[g.WireIntermediateValue(i)] = new(goldilocks.Element).SetInt64(mostSignificantDiff).Uint64()
+		} else {
+			diff := new(goldilocks.Element).SetInt64(int64(secondChunk) - int64(firstChunk))
+			wires[g.WireEqualityDummy(i)] = new(goldilocks.Element).Inverse(diff).Uint64()
+			mostSignificantDiff = int64(secondChunk) - int64(firstChunk)
+		}
+	}
+	wires[g.WireMostSignificantDiff()] = new(goldilocks.Element).SetInt64(mostSignificantDiff).Uint64()
+
+	bits := uint64(4 + mostSignificantDiff)
+	for i := uint64(0); i < 3; i++ {
+		wires[g.WireMostSignificantDiffBit(i)] = (bits >> i) & 1
+	}
+	if first <= second {
+		wires[g.WireResultBool()] = 1
+	}
+	return wires
+}
+
+// Honest rows of the plonky2-u32 gates, built the way their generators fill in the wires.
+var u32GateTests = []struct {
+	testGate gates.NativeGate
+	wires    func(rng *rand.Rand) []uint64
+}{
+	{gates.NewU32ArithmeticGate(3), func(rng *rand.Rand) []uint64 {
+		g := gates.NewU32ArithmeticGate(3)
+		wires := make([]uint64, 136)
+		for i := uint64(0); i < 3; i++ {
+			setU32ArithmeticOp(g, wires, i, uint64(rng.Uint32()), uint64(rng.Uint32()), uint64(rng.Uint32()))
+		}
+		return wires
+	}},
+	{gates.NewU32AddManyGate(4, 5), func(rng *rand.Rand) []uint64 {
+		g := gates.NewU32AddManyGate(4, 5)
+		wires := make([]uint64, 136)
+		for i := uint64(0); i < 5; i++ {
+			carry := uint64(rng.Uint32())
+			addends := make([]uint64, 4)
+			for j := range addends {
+				addends[j] = uint64(rng.Uint32())
+			}
+			setU32AddManyOp(g, wires, i, carry, addends)
+		}
+		return wires
+	}},
+	{gates.NewU32SubtractionGate(6), func(rng *rand.Rand) []uint64 {
+		g := gates.NewU32SubtractionGate(6)
+		wires := make([]uint64, 136)
+		for i := uint64(0); i < 6; i++ {
+			x, y, borrow := uint64(rng.Uint32()), uint64(rng.Uint32()), uint64(rng.Intn(2))
+			setU32SubtractionOp(g, wires, i, x, y, borrow)
+		}
+		return wires
+	}},
+	{gates.NewU32RangeCheckGate(8), func(rng *rand.Rand) []uint64 {
+		g := gates.NewU32RangeCheckGate(8)
+		wires := make([]uint64, 136)
+		for i := uint64(0); i < 8; i++ {
+			setU32RangeCheckInput(g, wires, i, uint64(rng.Uint32()))
+		}
+		return wires
+	}},
+	{gates.NewComparisonGate(32, 16), func(rng *rand.Rand) []uint64 {
+		first := uint64(rng.Uint32())
+		// Share the top chunks, so that the most significant difference isn't in the top chunk.
+		second := first&0xfff00000 | uint64(rng.Uint32())&0xfffff
+		return comparisonRow(gates.NewComparisonGate(32, 16), first, second)
+	}},
+}
+
+// Fixed rows that a dishonest prover could fill in, each of which gets past every constraint of
+// the gate but one.  The nonzero constraints are worked out by hand from the constraints of the
+// plonky2-u32 gates (a 2 bit limb x is range checked by x(x-1)(x-2)(x-3)), since there's no
+// plonky2-u32 build to generate them with.  The other operations of each gate are left all zero,
+// which is an honest row.
+var u32GateInvalidRowTests = []struct {
+	name     string
+	testGate gates.NativeGate
+	wires    func() []uint64
+	// The constraints that don't vanish, by index.  All of the others have to.
+	nonzeroConstraints map[int]uint64
+}{
+	{"u32 arithmetic output low half of 2^32", gates.NewU32ArithmeticGate(3), func() []uint64 {
+		g := gates.NewU32ArithmeticGate(3)
+		wires := make([]uint64, 136)
+		// 2^16 * 2^16 = 2^32, moved out of the high half into the low half.
+		setU32ArithmeticOp(g, wires, 0, 1<<16, 1<<16, 0)
+		wires[g.WireIthOutputLowHalf(0)] = 1 << 32
+		wires[g.WireIthOutputHighHalf(0)] = 0
+		diff := goldilocks.NewElement(0xffffffff)
+		wires[g.WireIthInverse(0)] = new(goldilocks.Element).Inverse(&diff).Uint64()
+		wires[g.WireIthOutputJthLimb(0, 15)] = 4
+		wires[g.WireIthOutputJthLimb(0, 16)] = 0
+		return wires
+	}, map[int]uint64{
+		// The range check of limb 15 of the first operation, 4*3*2*1.
+		2 + (31 - 15): 24,
+	}},
+	{"u32 add many result of 2^32 + 0xfffffffc", gates.NewU32AddManyGate(4, 5), func() []uint64 {
+		g := gates.NewU32AddManyGate(4, 5)
+		wires := make([]uint64, 136)
+		// 4 * 0xffffffff = 3 * 2^32 + 0xfffffffc, with one of the carries moved into the result.
+		setU32AddManyOp(g, wires, 0, 0, []uint64{0xffffffff, 0xffffffff, 0xffffffff, 0xffffffff})
+		wires[g.WireIthOutputResult(0)] = 1<<32 + 0xfffffffc
+		wires[g.WireIthOutputCarry(0)] = 2
+		wires[g.WireIthOutputJthLimb(0, 15)] = 7
+		wires[g.WireIthOutputJthLimb(0, 16)] = 2
+		return wires
+	}, map[int]uint64{
+		// The range check of limb 15 of the first operation, 7*6*5*4.
+		1 + (17 - 15): 840,
+	}},
+	{"u32 subtraction result of -1 without a borrow", gates.NewU32SubtractionGate(6), func() []uint64 {
+		g := gates.NewU32SubtractionGate(6)
+		wires := make([]uint64, 136)
+		// 0 - 1 = -1, without borrowing 2^32.
+		setU32SubtractionOp(g, wires, 0, 0, 1, 0)
+		minusOne := goldilocks.Modulus().Uint64() - 1
+		wires[g.WireIthOutputResult(0)] = minusOne
+		wires[g.WireIthOutputBorrow(0)] = 0
+		wires[g.WireIthOutputJthLimb(0, 15)] = minusOne
+		return wires
+	}, map[int]uint64{
+		// The range check of limb 15 of the first operation, (-1)(-2)(-3)(-4).
+		1 + (15 - 15): 24,
+	}},
+	{"u32 range check of 2^32", gates.NewU32RangeCheckGate(8), func() []uint64 {
+		g := gates.NewU32RangeCheckGate(8)
+		wires := make([]uint64, 136)
+		wires[g.WireIthInputLimb(0)] = 1 << 32
+		wires[g.WireIthInputLimbJthAuxLimb(0, 15)] = 4
+		return wires
+	}, map[int]uint64{
+		// The range check of aux limb 15 of the first input, 4*3*2*1.
+		1 + 15: 24,
+	}},
+	{"comparison claiming 5 <= 3", gates.NewComparisonGate(32, 16), func() []uint64 {
+		g := gates.NewComparisonGate(32, 16)
+		wires := comparisonRow(g, 5, 3)
+		wires[g.WireResultBool()] = 1
+		return wires
+	}, map[int]uint64{
+		// The last constraint, resultBool - mostSignificantDiffBits[2], which is 1 - 0.
+		2 + 16*5 + 1 + 3 + 1: 1,
+	}},
+}
+
+type TestU32GateCircuit struct {
+	testGate            gates.Gate
+	Wires               []gl.QuadraticExtensionVariable
+	ExpectedConstraints []gl.QuadraticExtensionVariable
+}
+
+func (circuit *TestU32GateCircuit) Define(api frontend.API) error {
+	glApi := gl.New(api)
+
+	vars := gates.NewEvaluationVars(nil, circuit.Wires, publicInputsHash)
+	constraints := circuit.testGate.EvalUnfiltered(api, glApi, *vars)
+	if len(constraints) != len(circuit.ExpectedConstraints) {
+		return errors.New("gate constraints length mismatch")
+	}
+	for i := range constraints {
+		glApi.AssertIsEqualExtension(constraints[i], circuit.ExpectedConstraints[i])
+	}
+
+	return nil
+}
+
+func TestU32GatesNative(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	var nativePublicInputsHash poseidon.GoldilocksNativeHashOut
+
+	for _, test := range u32GateTests {
+		gate, err := gates.TryGateInstanceFromId(test.testGate.Id())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gate.Id() != test.testGate.Id() {
+			t.Errorf("expected id %s, got %s", test.testGate.Id(), gate.Id())
+		}
+		if _, ok := gate.(gates.NativeGate); !ok {
+			t.Fatalf("%s can't be evaluated out of circuit", gate.Id())
+		}
+
+		wires := make([]gl.QuadraticExtension, 0, 136)
+		for _, wire := range test.wires(rng) {
+			wires = append(wires, gl.NewQuadraticExtensionFromUint64(wire))
+		}
+		vars := gates.NewNativeEvaluationVars(nil, wires, nativePublicInputsHash)
+		for i, constraint := range test.testGate.EvalUnfilteredNative(*vars) {
+			if !constraint.IsZero() {
+				t.Errorf("%s: constraint %d is %s", gate.Id(), i, constraint)
+			}
+		}
+
+		// Wire 0 is the first input of every one of these gates.
+		wires[0] = wires[0].Add(gl.OneQuadraticExtension())
+		allZero := true
+		for _, constraint := range test.testGate.EvalUnfilteredNative(*vars) {
+			allZero = allZero && constraint.IsZero()
+		}
+		if allZero {
+			t.Errorf("%s: the constraints hold for a dishonest row", gate.Id())
+		}
+	}
+
+	for _, test := range u32GateInvalidRowTests {
+		wires := []gl.QuadraticExtension{}
+		for _, wire := range test.wires() {
+			wires = append(wires, gl.NewQuadraticExtensionFromUint64(wire))
+		}
+		vars := gates.NewNativeEvaluationVars(nil, wires, nativePublicInputsHash)
+		for i, constraint := range test.testGate.EvalUnfilteredNative(*vars) {
+			expected := gl.NewQuadraticExtensionFromUint64(test.nonzeroConstraints[i])
+			if constraint != expected {
+				t.Errorf("%s: expected constraint %d to be %s, got %s", test.name, i, expected, constraint)
+			}
+		}
+	}
+}
+
+func TestU32Gates(t *testing.T) {
+	// The commit based range checker can't pick its base width for circuits this small.
+	t.Setenv("USE_BIT_DECOMPOSITION_RANGE_CHECK", "true")
+
+	assert := test.NewAssert(t)
+	rng := rand.New(rand.NewSource(0))
+
+	nativeLocalWires := toNativeQuadraticExtensions(localWires)
+	var nativePublicInputsHash poseidon.GoldilocksNativeHashOut
+	for i := range publicInputsHash {
+		nativePublicInputsHash[i].SetInterface(publicInputsHash[i].Limb)
+	}
+
+	testCase := func(testGate gates.Gate, wires []gl.QuadraticExtensionVariable, expectedConstraints []gl.QuadraticExtensionVariable) {
+		circuit := &TestU32GateCircuit{
+			testGate:            testGate,
+			Wires:               make([]gl.QuadraticExtensionVariable, len(wires)),
+			ExpectedConstraints: make([]gl.QuadraticExtensionVariable, len(expectedConstraints)),
+		}
+		witness := &TestU32GateCircuit{testGate: testGate, Wires: wires, ExpectedConstraints: expectedConstraints}
+		err := test.IsSolved(circuit, witness, ecc.BN254.ScalarField())
+		assert.NoError(err)
+	}
+
+	for _, test := range u32GateTests {
+		// The constraints vanish on an honest row.
+		wires := []gl.QuadraticExtensionVariable{}
+		for _, wire := range test.wires(rng) {
+			wires = append(wires, gl.NewVariable(wire).ToQuadraticExtension())
+		}
+		numConstraints := len(test.testGate.EvalUnfilteredNative(*gates.NewNativeEvaluationVars(nil, nativeLocalWires, nativePublicInputsHash)))
+		zeros := make([]gl.QuadraticExtensionVariable, numConstraints)
+		for i := range zeros {
+			zeros[i] = gl.ZeroExtension()
+		}
+		testCase(test.testGate, wires, zeros)
+
+		// The circuit agrees with the native evaluation on random wires.
+		vars := gates.NewNativeEvaluationVars(nil, nativeLocalWires, nativePublicInputsHash)
+		expectedConstraints := []gl.QuadraticExtensionVariable{}
+		for _, constraint := range test.testGate.EvalUnfilteredNative(*vars) {
+			expectedConstraints = append(expectedConstraints, gl.NewQuadraticExtensionVariable(
+				gl.NewVariable(constraint[0].Uint64()),
+				gl.NewVariable(constraint[1].Uint64()),
+			))
+		}
+		testCase(test.testGate, localWires, expectedConstraints)
+	}
+
+	for _, test := range u32GateInvalidRowTests {
+		wires := []gl.QuadraticExtensionVariable{}
+		for _, wire := range test.wires() {
+			wires = append(wires, gl.NewVariable(wire).ToQuadraticExtension())
+		}
+		numConstraints := len(test.testGate.EvalUnfilteredNative(*gates.NewNativeEvaluationVars(nil, nativeLocalWires, nativePublicInputsHash)))
+		expectedConstraints := make([]gl.QuadraticExtensionVariable, numConstraints)
+		for i := range expectedConstraints {
+			expectedConstraints[i] = gl.NewVariable(test.nonzeroConstraints[i]).ToQuadraticExtension()
+		}
+		testCase(test.testGate, wires, expectedConstraints)
+	}
+}
+
+// Reads the constraints that plonky2-u32's eval_unfiltered evaluates its gates to on the same vars
+// as TestGates, by gate ID, in the form of the testGates entries.
+func readU32GateExpectedConstraints(t *testing.T) map[string][]gl.QuadraticExtensionVariable {
+	data, err := os.ReadFile("../../testdata/u32_gates/expected_constraints.json")
+	if err != nil {
+		t.Fatalf("%v, see testdata/u32_gates.md", err)
+	}
+
+	var raw map[string][][2]uint64
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedConstraints := make(map[string][]gl.QuadraticExtensionVariable, len(raw))
+	for id, constraints := range raw {
+		for _, constraint := range constraints {
+			expectedConstraints[id] = append(
+				expectedConstraints[id],
+				gl.NewQuadraticExtensionVariable(gl.NewVariable(constraint[0]), gl.NewVariable(constraint[1])),
+			)
+		}
+	}
+	return expectedConstraints
+}
+
+// Unlike TestU32Gates, checks the plonky2-u32 gates against constraints that plonky2-u32 evaluated.
+func TestU32GatesPlonky2(t *testing.T) {
+	// The commit based range checker can't pick its base width for circuits this small.
+	t.Setenv("USE_BIT_DECOMPOSITION_RANGE_CHECK", "true")
+
+	assert := test.NewAssert(t)
+	expectedConstraints := readU32GateExpectedConstraints(t)
+
+	commonCircuitData := types.ReadCommonCircuitData("../../testdata/decode_block/common_circuit_data.json")
+	numSelectors := commonCircuitData.SelectorsInfo.NumSelectors()
+	var nativePublicInputsHash poseidon.GoldilocksNativeHashOut
+	vars := gates.NewNativeEvaluationVars(
+		toNativeQuadraticExtensions(localConstants)[numSelectors:],
+		toNativeQuadraticExtensions(localWires),
+		nativePublicInputsHash,
+	)
+
+	for _, u32GateTest := range u32GateTests {
+		id := u32GateTest.testGate.Id()
+		expected, ok := expectedConstraints[id]
+		if !ok {
+			t.Errorf("no expected constraints for %s", id)
+			continue
+		}
+
+		constraints := u32GateTest.testGate.EvalUnfilteredNative(*vars)
+		if len(constraints) != len(expected) {
+			t.Fatalf("%s: gate constraints length mismatch", id)
+		}
+		for i, expectedConstraint := range toNativeQuadraticExtensions(expected) {
+			if !constraints[i].Equal(expectedConstraint) {
+				t.Errorf("%s: constraint %d is %s, expected %s", id, i, constraints[i], expectedConstraint)
+			}
+		}
+
+		circuit := &TestGateCircuit{testGate: u32GateTest.testGate, ExpectedConstraints: expected}
+		witness := &TestGateCircuit{testGate: u32GateTest.testGate, ExpectedConstraints: expected}
+		assert.NoError(test.IsSolved(circuit, witness, ecc.BN254.ScalarField()), id)
+	}
+}
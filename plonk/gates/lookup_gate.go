@@ -0,0 +1,110 @@
+package gates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+)
+
+var lookupGateRegex = regexp.MustCompile(`LookupGate {num_slots: (?P<numSlots>[0-9]+), lut_hash: \[(?P<lutHash>[0-9, ]+)\]}`)
+
+func deserializeLookupGate(parameters map[string]string) Gate {
+	// Has the format "LookupGate {num_slots: 40, lut_hash: [210, 32, ..., 117]}"
+	numSlots, hasNumSlots := parameters["numSlots"]
+	lutHash, hasLutHash := parameters["lutHash"]
+	if !hasNumSlots || !hasLutHash {
+		panic("missing field in LookupGate")
+	}
+
+	numSlotsInt, err := strconv.Atoi(numSlots)
+	if err != nil {
+		panic("Invalid num_slots field in LookupGate")
+	}
+
+	return NewLookupGate(uint64(numSlotsInt), parseLutHash(lutHash, "LookupGate"))
+}
+
+// The number of challenges that the lookup argument uses for each of the num_challenges
+// repetitions, and their indices within them.
+const NUM_COINS_LOOKUP = 4
+
+const (
+	// Combines the inputs and outputs of the lookups and of the lookup table entries.
+	LOOKUP_CHALLENGE_A = iota
+	// Combines the inputs and outputs of the lookup table entries, to check that the lookup table
+	// matches the common circuit data.
+	LOOKUP_CHALLENGE_B
+	// The point at which the logarithmic derivatives are evaluated.
+	LOOKUP_CHALLENGE_ALPHA
+	// The point at which the lookup tables are evaluated as polynomials.
+	LOOKUP_CHALLENGE_DELTA
+)
+
+// Parses the Debug format of the keccak hash of a lookup table, without the brackets.
+func parseLutHash(lutHash string, gateName string) []uint8 {
+	bytes := []uint8{}
+	for _, byteStr := range strings.Split(lutHash, ", ") {
+		b, err := strconv.ParseUint(byteStr, 10, 8)
+		if err != nil {
+			panic(fmt.Sprintf("Invalid lut_hash field in %s", gateName))
+		}
+		bytes = append(bytes, uint8(b))
+	}
+	return bytes
+}
+
+func formatLutHash(lutHash []uint8) string {
+	bytes := make([]string, len(lutHash))
+	for i, b := range lutHash {
+		bytes[i] = strconv.Itoa(int(b))
+	}
+	return "[" + strings.Join(bytes, ", ") + "]"
+}
+
+// The number of lookups done in each row of LookupGates.  Each lookup uses two routed wires, one
+// for the input and one for the output.
+func LookupGateNumSlots(numRoutedWires uint64) uint64 {
+	return numRoutedWires / 2
+}
+
+func LookupGateWireIthLookingInp(i uint64) uint64 {
+	return 2 * i
+}
+
+func LookupGateWireIthLookingOut(i uint64) uint64 {
+	return 2*i + 1
+}
+
+// A row of lookups into a lookup table.  The gate doesn't have any constraints of its own, the
+// lookups are checked by the lookup argument within the vanishing polynomial.
+type LookupGate struct {
+	numSlots uint64
+	lutHash  []uint8
+}
+
+func NewLookupGate(numSlots uint64, lutHash []uint8) *LookupGate {
+	return &LookupGate{
+		numSlots: numSlots,
+		lutHash:  lutHash,
+	}
+}
+
+func (g *LookupGate) Id() string {
+	return fmt.Sprintf("LookupGate {num_slots: %d, lut_hash: %s}", g.numSlots, formatLutHash(g.lutHash))
+}
+
+func (g *LookupGate) EvalUnfiltered(
+	api frontend.API,
+	glApi *gl.Chip,
+	vars EvaluationVars,
+) []gl.QuadraticExtensionVariable {
+	return []gl.QuadraticExtensionVariable{}
+}
+
+func (g *LookupGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	return []gl.QuadraticExtension{}
+}
@@ -0,0 +1,89 @@
+package gates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/consensys/gnark/frontend"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+)
+
+var lookupTableGateRegex = regexp.MustCompile(`LookupTableGate {num_slots: (?P<numSlots>[0-9]+), lut_hash: \[(?P<lutHash>[0-9, ]+)\], last_lut_row: (?P<lastLutRow>[0-9]+)}`)
+
+func deserializeLookupTableGate(parameters map[string]string) Gate {
+	// Has the format "LookupTableGate {num_slots: 26, lut_hash: [210, 32, ..., 117], last_lut_row: 1034}"
+	numSlots, hasNumSlots := parameters["numSlots"]
+	lutHash, hasLutHash := parameters["lutHash"]
+	lastLutRow, hasLastLutRow := parameters["lastLutRow"]
+	if !hasNumSlots || !hasLutHash || !hasLastLutRow {
+		panic("missing field in LookupTableGate")
+	}
+
+	numSlotsInt, err := strconv.Atoi(numSlots)
+	if err != nil {
+		panic("Invalid num_slots field in LookupTableGate")
+	}
+
+	lastLutRowInt, err := strconv.Atoi(lastLutRow)
+	if err != nil {
+		panic("Invalid last_lut_row field in LookupTableGate")
+	}
+
+	return NewLookupTableGate(uint64(numSlotsInt), parseLutHash(lutHash, "LookupTableGate"), uint64(lastLutRowInt))
+}
+
+// The number of lookup table entries within each row of LookupTableGates.  Each entry uses three
+// routed wires, for the input, the output and the number of times the entry is looked up.
+func LookupTableGateNumSlots(numRoutedWires uint64) uint64 {
+	return numRoutedWires / 3
+}
+
+func LookupTableGateWireIthLookedInp(i uint64) uint64 {
+	return 3 * i
+}
+
+func LookupTableGateWireIthLookedOut(i uint64) uint64 {
+	return 3*i + 1
+}
+
+func LookupTableGateWireIthMultiplicity(i uint64) uint64 {
+	return 3*i + 2
+}
+
+// A row of a lookup table's entries.  Like LookupGate, it doesn't have any constraints of its
+// own.
+type LookupTableGate struct {
+	numSlots   uint64
+	lutHash    []uint8
+	lastLutRow uint64
+}
+
+func NewLookupTableGate(numSlots uint64, lutHash []uint8, lastLutRow uint64) *LookupTableGate {
+	return &LookupTableGate{
+		numSlots:   numSlots,
+		lutHash:    lutHash,
+		lastLutRow: lastLutRow,
+	}
+}
+
+func (g *LookupTableGate) Id() string {
+	return fmt.Sprintf(
+		"LookupTableGate {num_slots: %d, lut_hash: %s, last_lut_row: %d}",
+		g.numSlots,
+		formatLutHash(g.lutHash),
+		g.lastLutRow,
+	)
+}
+
+func (g *LookupTableGate) EvalUnfiltered(
+	api frontend.API,
+	glApi *gl.Chip,
+	vars EvaluationVars,
+) []gl.QuadraticExtensionVariable {
+	return []gl.QuadraticExtensionVariable{}
+}
+
+func (g *LookupTableGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	return []gl.QuadraticExtension{}
+}
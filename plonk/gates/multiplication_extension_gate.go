@@ -74,3 +74,21 @@ func (g *MultiplicationExtensionGate) EvalUnfiltered(
 	}
 	return constraints
 }
+
+func (g *MultiplicationExtensionGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	const0 := vars.localConstants[0]
+	constraints := []gl.QuadraticExtension{}
+	for i := uint64(0); i < g.numOps; i++ {
+		multiplicand0 := vars.GetLocalExtAlgebra(g.wiresIthMultiplicand0(i))
+		multiplicand1 := vars.GetLocalExtAlgebra(g.wiresIthMultiplicand1(i))
+		output := vars.GetLocalExtAlgebra(g.wiresIthOutput(i))
+
+		computedOutput := multiplicand0.Mul(multiplicand1).ScalarMul(const0)
+
+		diff := output.Sub(computedOutput)
+		for j := 0; j < gl.D; j++ {
+			constraints = append(constraints, diff[j])
+		}
+	}
+	return constraints
+}
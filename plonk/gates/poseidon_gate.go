@@ -179,3 +179,88 @@ func (g *PoseidonGate) EvalUnfiltered(
 
 	return constraints
 }
+
+func (g *PoseidonGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	constraints := []gl.QuadraticExtension{}
+
+	hasher := poseidon.NewGoldilocksHasher()
+
+	// Assert that `swap` is binary.
+	swap := vars.localWires[g.WireSwap()]
+	constraints = append(constraints, swap.Mul(swap.Sub(gl.OneQuadraticExtension())))
+
+	// Assert that each delta wire is set properly: `delta_i = swap * (rhs - lhs)`.
+	for i := uint64(0); i < 4; i++ {
+		inputLhs := vars.localWires[g.WireInput(i)]
+		inputRhs := vars.localWires[g.WireInput(i+4)]
+		deltaI := vars.localWires[g.WireDelta(i)]
+		expectedDeltaI := swap.Mul(inputRhs.Sub(inputLhs))
+		constraints = append(constraints, expectedDeltaI.Sub(deltaI))
+	}
+
+	// Compute the possibly-swapped input layer.
+	var state poseidon.GoldilocksNativeStateExtension
+	for i := uint64(0); i < 4; i++ {
+		deltaI := vars.localWires[g.WireDelta(i)]
+		inputLhs := vars.localWires[g.WireInput(i)]
+		inputRhs := vars.localWires[g.WireInput(i+4)]
+		state[i] = inputLhs.Add(deltaI)
+		state[i+4] = inputRhs.Sub(deltaI)
+	}
+	for i := uint64(8); i < poseidon.SPONGE_WIDTH; i++ {
+		state[i] = vars.localWires[g.WireInput(i)]
+	}
+
+	roundCounter := 0
+
+	// First set of full rounds.
+	for r := uint64(0); r < poseidon.HALF_N_FULL_ROUNDS; r++ {
+		state = hasher.ConstantLayerExtension(state, &roundCounter)
+		if r != 0 {
+			for i := uint64(0); i < poseidon.SPONGE_WIDTH; i++ {
+				sBoxIn := vars.localWires[g.WireFullSBox0(r, i)]
+				constraints = append(constraints, state[i].Sub(sBoxIn))
+				state[i] = sBoxIn
+			}
+		}
+		state = hasher.SBoxLayerExtension(state)
+		state = hasher.MdsLayerExtension(state)
+		roundCounter++
+	}
+
+	// Partial rounds.
+	state = hasher.PartialFirstConstantLayerExtension(state)
+	state = hasher.MdsPartialLayerInitExtension(state)
+
+	for r := uint64(0); r < poseidon.N_PARTIAL_ROUNDS-1; r++ {
+		sBoxIn := vars.localWires[g.WirePartialSBox(r)]
+		constraints = append(constraints, state[0].Sub(sBoxIn))
+		state[0] = hasher.SBoxMonomialExtension(sBoxIn)
+		state[0] = state[0].Add(gl.NewQuadraticExtensionFromUint64(poseidon.FAST_PARTIAL_ROUND_CONSTANTS[r].(uint64)))
+		state = hasher.MdsPartialLayerFastExtension(state, int(r))
+	}
+	sBoxIn := vars.localWires[g.WirePartialSBox(poseidon.N_PARTIAL_ROUNDS-1)]
+	constraints = append(constraints, state[0].Sub(sBoxIn))
+	state[0] = hasher.SBoxMonomialExtension(sBoxIn)
+	state = hasher.MdsPartialLayerFastExtension(state, poseidon.N_PARTIAL_ROUNDS-1)
+	roundCounter += poseidon.N_PARTIAL_ROUNDS
+
+	// Second set of full rounds.
+	for r := uint64(0); r < poseidon.HALF_N_FULL_ROUNDS; r++ {
+		state = hasher.ConstantLayerExtension(state, &roundCounter)
+		for i := uint64(0); i < poseidon.SPONGE_WIDTH; i++ {
+			sBoxIn := vars.localWires[g.WireFullSBox1(r, i)]
+			constraints = append(constraints, state[i].Sub(sBoxIn))
+			state[i] = sBoxIn
+		}
+		state = hasher.SBoxLayerExtension(state)
+		state = hasher.MdsLayerExtension(state)
+		roundCounter++
+	}
+
+	for i := uint64(0); i < poseidon.SPONGE_WIDTH; i++ {
+		constraints = append(constraints, state[i].Sub(vars.localWires[g.WireOutput(i)]))
+	}
+
+	return constraints
+}
@@ -97,3 +97,42 @@ func (g *PoseidonMdsGate) EvalUnfiltered(
 
 	return constraints
 }
+
+func (g *PoseidonMdsGate) mdsRowShfAlgebraNative(
+	r uint64,
+	v [poseidon.SPONGE_WIDTH]gl.QuadraticExtensionAlgebra,
+) gl.QuadraticExtensionAlgebra {
+	if r >= poseidon.SPONGE_WIDTH {
+		panic("MDS row index out of range")
+	}
+
+	res := gl.ZeroQuadraticExtensionAlgebra()
+	for i := uint64(0); i < poseidon.SPONGE_WIDTH; i++ {
+		coeff := gl.NewQuadraticExtensionFromUint64(poseidon.MDS_MATRIX_CIRC[i].(uint64))
+		res = res.Add(v[(i+r)%poseidon.SPONGE_WIDTH].ScalarMul(coeff))
+	}
+
+	coeff := gl.NewQuadraticExtensionFromUint64(poseidon.MDS_MATRIX_DIAG[r].(uint64))
+	res = res.Add(v[r].ScalarMul(coeff))
+
+	return res
+}
+
+func (g *PoseidonMdsGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	constraints := []gl.QuadraticExtension{}
+
+	var inputs [poseidon.SPONGE_WIDTH]gl.QuadraticExtensionAlgebra
+	for i := uint64(0); i < poseidon.SPONGE_WIDTH; i++ {
+		inputs[i] = vars.GetLocalExtAlgebra(g.WireInput(i))
+	}
+
+	for i := uint64(0); i < poseidon.SPONGE_WIDTH; i++ {
+		output := vars.GetLocalExtAlgebra(g.WireOutput(i))
+		diff := output.Sub(g.mdsRowShfAlgebraNative(i, inputs))
+		for j := 0; j < gl.D; j++ {
+			constraints = append(constraints, diff[j])
+		}
+	}
+
+	return constraints
+}
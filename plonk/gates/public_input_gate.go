@@ -49,3 +49,16 @@ func (g *PublicInputGate) EvalUnfiltered(
 
 	return constraints
 }
+
+func (g *PublicInputGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	constraints := []gl.QuadraticExtension{}
+
+	wires := g.WiresPublicInputsHash()
+	hashParts := vars.publicInputsHash
+	for i := 0; i < len(wires); i++ {
+		diff := vars.localWires[wires[i]].Sub(gl.NewQuadraticExtensionFromBase(hashParts[i]))
+		constraints = append(constraints, diff)
+	}
+
+	return constraints
+}
@@ -107,3 +107,30 @@ func (g *ReducingExtensionGate) EvalUnfiltered(
 
 	return constraints
 }
+
+func (g *ReducingExtensionGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	alpha := vars.GetLocalExtAlgebra(g.wiresAlpha())
+	oldAcc := vars.GetLocalExtAlgebra(g.wiresOldAcc())
+
+	coeffs := []gl.QuadraticExtensionAlgebra{}
+	for i := uint64(0); i < g.numCoeffs; i++ {
+		coeffs = append(coeffs, vars.GetLocalExtAlgebra(g.wiresCoeff(i)))
+	}
+
+	accs := []gl.QuadraticExtensionAlgebra{}
+	for i := uint64(0); i < g.numCoeffs; i++ {
+		accs = append(accs, vars.GetLocalExtAlgebra(g.wiresAccs(i)))
+	}
+
+	constraints := []gl.QuadraticExtension{}
+	acc := oldAcc
+	for i := uint64(0); i < g.numCoeffs; i++ {
+		tmp := acc.Mul(alpha).Add(coeffs[i]).Sub(accs[i])
+		for j := uint64(0); j < gl.D; j++ {
+			constraints = append(constraints, tmp[j])
+		}
+		acc = accs[i]
+	}
+
+	return constraints
+}
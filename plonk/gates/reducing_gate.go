@@ -108,3 +108,31 @@ func (g *ReducingGate) EvalUnfiltered(
 
 	return constraints
 }
+
+func (g *ReducingGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	alpha := vars.GetLocalExtAlgebra(g.wiresAlpha())
+	oldAcc := vars.GetLocalExtAlgebra(g.wiresOldAcc())
+
+	coeffs := []gl.QuadraticExtension{}
+	coeffsRange := g.wiresCoeff()
+	for i := coeffsRange.start; i < coeffsRange.end; i++ {
+		coeffs = append(coeffs, vars.localWires[i])
+	}
+
+	accs := []gl.QuadraticExtensionAlgebra{}
+	for i := uint64(0); i < g.numCoeffs; i++ {
+		accs = append(accs, vars.GetLocalExtAlgebra(g.wiresAccs(i)))
+	}
+
+	constraints := []gl.QuadraticExtension{}
+	acc := oldAcc
+	for i := uint64(0); i < g.numCoeffs; i++ {
+		tmp := acc.Mul(alpha).Add(coeffs[i].ToQuadraticExtensionAlgebra()).Sub(accs[i])
+		for j := 0; j < gl.D; j++ {
+			constraints = append(constraints, tmp[j])
+		}
+		acc = accs[i]
+	}
+
+	return constraints
+}
@@ -2,11 +2,29 @@ package gates
 
 const UNUSED_SELECTOR = uint64(^uint32(0)) // max uint32
 
+// The lookup selectors, which come right after the gate selectors within the constants.  There is
+// one LOOKUP_SELECTOR_START_END selector for each lookup table, starting at that index.
+const (
+	LOOKUP_SELECTOR_TRANS_SRE = iota
+	LOOKUP_SELECTOR_TRANS_LDC
+	LOOKUP_SELECTOR_INIT_SRE
+	LOOKUP_SELECTOR_LAST_LDC
+	LOOKUP_SELECTOR_START_END
+)
+
 type Range struct {
 	start uint64
 	end   uint64
 }
 
+func (r Range) Start() uint64 {
+	return r.start
+}
+
+func (r Range) End() uint64 {
+	return r.end
+}
+
 type SelectorsInfo struct {
 	selectorIndices []uint64
 	groups          []Range
@@ -34,3 +52,11 @@ func NewSelectorsInfo(selectorIndices []uint64, groupStarts []uint64, groupEnds
 func (s *SelectorsInfo) NumSelectors() uint64 {
 	return uint64(len(s.groups))
 }
+
+func (s *SelectorsInfo) SelectorIndices() []uint64 {
+	return s.selectorIndices
+}
+
+func (s *SelectorsInfo) Groups() []Range {
+	return s.groups
+}
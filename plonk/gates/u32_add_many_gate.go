@@ -0,0 +1,161 @@
+package gates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/consensys/gnark/frontend"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+)
+
+var u32AddManyGateRegex = regexp.MustCompile("U32AddManyGate { num_addends: (?P<numAddends>[0-9]+), num_ops: (?P<numOps>[0-9]+), _phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField> }")
+
+func deserializeU32AddManyGate(parameters map[string]string) Gate {
+	// Has the format "U32AddManyGate { num_addends: 4, num_ops: 5, _phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField> }"
+	numAddends, hasNumAddends := parameters["numAddends"]
+	numOps, hasNumOps := parameters["numOps"]
+	if !hasNumAddends || !hasNumOps {
+		panic("Missing field num_addends or num_ops in U32AddManyGate")
+	}
+
+	numAddendsInt, err := strconv.Atoi(numAddends)
+	if err != nil {
+		panic("Invalid num_addends field in U32AddManyGate")
+	}
+
+	numOpsInt, err := strconv.Atoi(numOps)
+	if err != nil {
+		panic("Invalid num_ops field in U32AddManyGate")
+	}
+
+	return NewU32AddManyGate(uint64(numAddendsInt), uint64(numOpsInt))
+}
+
+const (
+	U32_ADD_MANY_GATE_LOG2_MAX_NUM_ADDENDS = 4
+	U32_ADD_MANY_GATE_LIMB_BITS            = 2
+	U32_ADD_MANY_GATE_NUM_RESULT_LIMBS     = (32 + U32_ADD_MANY_GATE_LIMB_BITS - 1) / U32_ADD_MANY_GATE_LIMB_BITS
+	U32_ADD_MANY_GATE_NUM_CARRY_LIMBS      = (U32_ADD_MANY_GATE_LOG2_MAX_NUM_ADDENDS + U32_ADD_MANY_GATE_LIMB_BITS - 1) / U32_ADD_MANY_GATE_LIMB_BITS
+	U32_ADD_MANY_GATE_NUM_LIMBS            = U32_ADD_MANY_GATE_NUM_RESULT_LIMBS + U32_ADD_MANY_GATE_NUM_CARRY_LIMBS
+)
+
+// Adds up num_addends u32 values and an input carry, and splits the sum into a u32 result and an
+// output carry.  From the plonky2-u32 crate.
+type U32AddManyGate struct {
+	numAddends uint64
+	numOps     uint64
+}
+
+func NewU32AddManyGate(numAddends uint64, numOps uint64) *U32AddManyGate {
+	return &U32AddManyGate{
+		numAddends: numAddends,
+		numOps:     numOps,
+	}
+}
+
+func (g *U32AddManyGate) Id() string {
+	return fmt.Sprintf(
+		"U32AddManyGate { num_addends: %d, num_ops: %d, _phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField> }",
+		g.numAddends,
+		g.numOps,
+	)
+}
+
+func (g *U32AddManyGate) routedWiresPerOp() uint64 {
+	return g.numAddends + 3
+}
+
+func (g *U32AddManyGate) WireIthOpJthAddend(i uint64, j uint64) uint64 {
+	return g.routedWiresPerOp()*i + j
+}
+
+func (g *U32AddManyGate) WireIthCarry(i uint64) uint64 {
+	return g.routedWiresPerOp()*i + g.numAddends
+}
+
+func (g *U32AddManyGate) WireIthOutputResult(i uint64) uint64 {
+	return g.routedWiresPerOp()*i + g.numAddends + 1
+}
+
+func (g *U32AddManyGate) WireIthOutputCarry(i uint64) uint64 {
+	return g.routedWiresPerOp()*i + g.numAddends + 2
+}
+
+func (g *U32AddManyGate) WireIthOutputJthLimb(i uint64, j uint64) uint64 {
+	return g.routedWiresPerOp()*g.numOps + U32_ADD_MANY_GATE_NUM_LIMBS*i + j
+}
+
+func (g *U32AddManyGate) EvalUnfiltered(
+	api frontend.API,
+	glApi *gl.Chip,
+	vars EvaluationVars,
+) []gl.QuadraticExtensionVariable {
+	outputBase := gl.NewQuadraticExtensionVariable(gl.NewVariable(uint64(1<<32)), gl.Zero())
+	limbBase := gl.NewQuadraticExtensionVariable(gl.NewVariable(uint64(1<<U32_ADD_MANY_GATE_LIMB_BITS)), gl.Zero())
+
+	constraints := []gl.QuadraticExtensionVariable{}
+	for i := uint64(0); i < g.numOps; i++ {
+		computedOutput := vars.localWires[g.WireIthCarry(i)]
+		for j := uint64(0); j < g.numAddends; j++ {
+			computedOutput = glApi.AddExtension(computedOutput, vars.localWires[g.WireIthOpJthAddend(i, j)])
+		}
+
+		outputResult := vars.localWires[g.WireIthOutputResult(i)]
+		outputCarry := vars.localWires[g.WireIthOutputCarry(i)]
+		combinedOutput := glApi.MulAddExtension(outputCarry, outputBase, outputResult)
+		constraints = append(constraints, glApi.SubExtension(combinedOutput, computedOutput))
+
+		combinedResultLimbs := gl.ZeroExtension()
+		combinedCarryLimbs := gl.ZeroExtension()
+		for j := int(U32_ADD_MANY_GATE_NUM_LIMBS) - 1; j >= 0; j-- {
+			thisLimb := vars.localWires[g.WireIthOutputJthLimb(i, uint64(j))]
+			constraints = append(constraints, evalRangeCheck(glApi, thisLimb, 1<<U32_ADD_MANY_GATE_LIMB_BITS))
+
+			if j < U32_ADD_MANY_GATE_NUM_RESULT_LIMBS {
+				combinedResultLimbs = glApi.MulAddExtension(limbBase, combinedResultLimbs, thisLimb)
+			} else {
+				combinedCarryLimbs = glApi.MulAddExtension(limbBase, combinedCarryLimbs, thisLimb)
+			}
+		}
+		constraints = append(constraints, glApi.SubExtension(combinedResultLimbs, outputResult))
+		constraints = append(constraints, glApi.SubExtension(combinedCarryLimbs, outputCarry))
+	}
+
+	return constraints
+}
+
+func (g *U32AddManyGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	outputBase := gl.NewQuadraticExtensionFromUint64(1 << 32)
+	limbBase := gl.NewQuadraticExtensionFromUint64(1 << U32_ADD_MANY_GATE_LIMB_BITS)
+
+	constraints := []gl.QuadraticExtension{}
+	for i := uint64(0); i < g.numOps; i++ {
+		computedOutput := vars.localWires[g.WireIthCarry(i)]
+		for j := uint64(0); j < g.numAddends; j++ {
+			computedOutput = computedOutput.Add(vars.localWires[g.WireIthOpJthAddend(i, j)])
+		}
+
+		outputResult := vars.localWires[g.WireIthOutputResult(i)]
+		outputCarry := vars.localWires[g.WireIthOutputCarry(i)]
+		combinedOutput := outputCarry.Mul(outputBase).Add(outputResult)
+		constraints = append(constraints, combinedOutput.Sub(computedOutput))
+
+		combinedResultLimbs := gl.ZeroQuadraticExtension()
+		combinedCarryLimbs := gl.ZeroQuadraticExtension()
+		for j := int(U32_ADD_MANY_GATE_NUM_LIMBS) - 1; j >= 0; j-- {
+			thisLimb := vars.localWires[g.WireIthOutputJthLimb(i, uint64(j))]
+			constraints = append(constraints, evalRangeCheckNative(thisLimb, 1<<U32_ADD_MANY_GATE_LIMB_BITS))
+
+			if j < U32_ADD_MANY_GATE_NUM_RESULT_LIMBS {
+				combinedResultLimbs = limbBase.Mul(combinedResultLimbs).Add(thisLimb)
+			} else {
+				combinedCarryLimbs = limbBase.Mul(combinedCarryLimbs).Add(thisLimb)
+			}
+		}
+		constraints = append(constraints, combinedResultLimbs.Sub(outputResult))
+		constraints = append(constraints, combinedCarryLimbs.Sub(outputCarry))
+	}
+
+	return constraints
+}
@@ -0,0 +1,187 @@
+package gates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/consensys/gnark/frontend"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+)
+
+var u32ArithmeticGateRegex = regexp.MustCompile("U32ArithmeticGate { num_ops: (?P<numOps>[0-9]+), _phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField> }")
+
+func deserializeU32ArithmeticGate(parameters map[string]string) Gate {
+	// Has the format "U32ArithmeticGate { num_ops: 3, _phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField> }"
+	numOps, hasNumOps := parameters["numOps"]
+	if !hasNumOps {
+		panic("Missing field num_ops in U32ArithmeticGate")
+	}
+
+	numOpsInt, err := strconv.Atoi(numOps)
+	if err != nil {
+		panic("Invalid num_ops field in U32ArithmeticGate")
+	}
+
+	return NewU32ArithmeticGate(uint64(numOpsInt))
+}
+
+const (
+	U32_ARITHMETIC_GATE_LIMB_BITS           = 2
+	U32_ARITHMETIC_GATE_NUM_LIMBS           = 64 / U32_ARITHMETIC_GATE_LIMB_BITS
+	U32_ARITHMETIC_GATE_ROUTED_WIRES_PER_OP = 6
+)
+
+// Computes multiplicand_0 * multiplicand_1 + addend for u32 operands, and splits the 64 bit result
+// into its low and high halves.  From the plonky2-u32 crate.
+type U32ArithmeticGate struct {
+	numOps uint64
+}
+
+func NewU32ArithmeticGate(numOps uint64) *U32ArithmeticGate {
+	return &U32ArithmeticGate{
+		numOps: numOps,
+	}
+}
+
+func (g *U32ArithmeticGate) Id() string {
+	return fmt.Sprintf("U32ArithmeticGate { num_ops: %d, _phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField> }", g.numOps)
+}
+
+func (g *U32ArithmeticGate) WireIthMultiplicand0(i uint64) uint64 {
+	return U32_ARITHMETIC_GATE_ROUTED_WIRES_PER_OP * i
+}
+
+func (g *U32ArithmeticGate) WireIthMultiplicand1(i uint64) uint64 {
+	return U32_ARITHMETIC_GATE_ROUTED_WIRES_PER_OP*i + 1
+}
+
+func (g *U32ArithmeticGate) WireIthAddend(i uint64) uint64 {
+	return U32_ARITHMETIC_GATE_ROUTED_WIRES_PER_OP*i + 2
+}
+
+func (g *U32ArithmeticGate) WireIthOutputLowHalf(i uint64) uint64 {
+	return U32_ARITHMETIC_GATE_ROUTED_WIRES_PER_OP*i + 3
+}
+
+func (g *U32ArithmeticGate) WireIthOutputHighHalf(i uint64) uint64 {
+	return U32_ARITHMETIC_GATE_ROUTED_WIRES_PER_OP*i + 4
+}
+
+func (g *U32ArithmeticGate) WireIthInverse(i uint64) uint64 {
+	return U32_ARITHMETIC_GATE_ROUTED_WIRES_PER_OP*i + 5
+}
+
+func (g *U32ArithmeticGate) WireIthOutputJthLimb(i uint64, j uint64) uint64 {
+	return U32_ARITHMETIC_GATE_ROUTED_WIRES_PER_OP*g.numOps + U32_ARITHMETIC_GATE_NUM_LIMBS*i + j
+}
+
+// Returns prod_{i < n} (x - i), which is zero iff x is in [0, n).
+func evalRangeCheck(glApi *gl.Chip, x gl.QuadraticExtensionVariable, n uint64) gl.QuadraticExtensionVariable {
+	acc := gl.OneExtension()
+	for i := uint64(0); i < n; i++ {
+		acc = glApi.MulExtension(acc, glApi.SubExtension(x, gl.NewQuadraticExtensionVariable(gl.NewVariable(i), gl.Zero())))
+	}
+	return acc
+}
+
+func evalRangeCheckNative(x gl.QuadraticExtension, n uint64) gl.QuadraticExtension {
+	acc := gl.OneQuadraticExtension()
+	for i := uint64(0); i < n; i++ {
+		acc = acc.Mul(x.Sub(gl.NewQuadraticExtensionFromUint64(i)))
+	}
+	return acc
+}
+
+func (g *U32ArithmeticGate) EvalUnfiltered(
+	api frontend.API,
+	glApi *gl.Chip,
+	vars EvaluationVars,
+) []gl.QuadraticExtensionVariable {
+	one := gl.OneExtension()
+	u32Max := gl.NewQuadraticExtensionVariable(gl.NewVariable(uint64(1<<32-1)), gl.Zero())
+	outputBase := gl.NewQuadraticExtensionVariable(gl.NewVariable(uint64(1<<32)), gl.Zero())
+	limbBase := gl.NewQuadraticExtensionVariable(gl.NewVariable(uint64(1<<U32_ARITHMETIC_GATE_LIMB_BITS)), gl.Zero())
+
+	constraints := []gl.QuadraticExtensionVariable{}
+	for i := uint64(0); i < g.numOps; i++ {
+		multiplicand0 := vars.localWires[g.WireIthMultiplicand0(i)]
+		multiplicand1 := vars.localWires[g.WireIthMultiplicand1(i)]
+		addend := vars.localWires[g.WireIthAddend(i)]
+		computedOutput := glApi.MulAddExtension(multiplicand0, multiplicand1, addend)
+
+		outputLow := vars.localWires[g.WireIthOutputLowHalf(i)]
+		outputHigh := vars.localWires[g.WireIthOutputHighHalf(i)]
+		inverse := vars.localWires[g.WireIthInverse(i)]
+
+		// The combined output is canonical iff either the high half isn't u32::MAX, which is the
+		// case iff the difference below has an inverse, or the low half is zero.
+		diff := glApi.SubExtension(u32Max, outputHigh)
+		hiNotMax := glApi.SubExtension(glApi.MulExtension(inverse, diff), one)
+		constraints = append(constraints, glApi.MulExtension(hiNotMax, outputLow))
+
+		combinedOutput := glApi.MulAddExtension(outputHigh, outputBase, outputLow)
+		constraints = append(constraints, glApi.SubExtension(combinedOutput, computedOutput))
+
+		combinedLowLimbs := gl.ZeroExtension()
+		combinedHighLimbs := gl.ZeroExtension()
+		midpoint := uint64(U32_ARITHMETIC_GATE_NUM_LIMBS / 2)
+		for j := int(U32_ARITHMETIC_GATE_NUM_LIMBS) - 1; j >= 0; j-- {
+			thisLimb := vars.localWires[g.WireIthOutputJthLimb(i, uint64(j))]
+			constraints = append(constraints, evalRangeCheck(glApi, thisLimb, 1<<U32_ARITHMETIC_GATE_LIMB_BITS))
+
+			if uint64(j) < midpoint {
+				combinedLowLimbs = glApi.MulAddExtension(limbBase, combinedLowLimbs, thisLimb)
+			} else {
+				combinedHighLimbs = glApi.MulAddExtension(limbBase, combinedHighLimbs, thisLimb)
+			}
+		}
+		constraints = append(constraints, glApi.SubExtension(combinedLowLimbs, outputLow))
+		constraints = append(constraints, glApi.SubExtension(combinedHighLimbs, outputHigh))
+	}
+
+	return constraints
+}
+
+func (g *U32ArithmeticGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	one := gl.OneQuadraticExtension()
+	u32Max := gl.NewQuadraticExtensionFromUint64(1<<32 - 1)
+	outputBase := gl.NewQuadraticExtensionFromUint64(1 << 32)
+	limbBase := gl.NewQuadraticExtensionFromUint64(1 << U32_ARITHMETIC_GATE_LIMB_BITS)
+
+	constraints := []gl.QuadraticExtension{}
+	for i := uint64(0); i < g.numOps; i++ {
+		multiplicand0 := vars.localWires[g.WireIthMultiplicand0(i)]
+		multiplicand1 := vars.localWires[g.WireIthMultiplicand1(i)]
+		addend := vars.localWires[g.WireIthAddend(i)]
+		computedOutput := multiplicand0.Mul(multiplicand1).Add(addend)
+
+		outputLow := vars.localWires[g.WireIthOutputLowHalf(i)]
+		outputHigh := vars.localWires[g.WireIthOutputHighHalf(i)]
+		inverse := vars.localWires[g.WireIthInverse(i)]
+
+		hiNotMax := inverse.Mul(u32Max.Sub(outputHigh)).Sub(one)
+		constraints = append(constraints, hiNotMax.Mul(outputLow))
+
+		combinedOutput := outputHigh.Mul(outputBase).Add(outputLow)
+		constraints = append(constraints, combinedOutput.Sub(computedOutput))
+
+		combinedLowLimbs := gl.ZeroQuadraticExtension()
+		combinedHighLimbs := gl.ZeroQuadraticExtension()
+		midpoint := uint64(U32_ARITHMETIC_GATE_NUM_LIMBS / 2)
+		for j := int(U32_ARITHMETIC_GATE_NUM_LIMBS) - 1; j >= 0; j-- {
+			thisLimb := vars.localWires[g.WireIthOutputJthLimb(i, uint64(j))]
+			constraints = append(constraints, evalRangeCheckNative(thisLimb, 1<<U32_ARITHMETIC_GATE_LIMB_BITS))
+
+			if uint64(j) < midpoint {
+				combinedLowLimbs = limbBase.Mul(combinedLowLimbs).Add(thisLimb)
+			} else {
+				combinedHighLimbs = limbBase.Mul(combinedHighLimbs).Add(thisLimb)
+			}
+		}
+		constraints = append(constraints, combinedLowLimbs.Sub(outputLow))
+		constraints = append(constraints, combinedHighLimbs.Sub(outputHigh))
+	}
+
+	return constraints
+}
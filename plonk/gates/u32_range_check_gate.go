@@ -0,0 +1,103 @@
+package gates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/consensys/gnark/frontend"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+)
+
+var u32RangeCheckGateRegex = regexp.MustCompile("U32RangeCheckGate { num_input_limbs: (?P<numInputLimbs>[0-9]+), _phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField> }")
+
+func deserializeU32RangeCheckGate(parameters map[string]string) Gate {
+	// Has the format "U32RangeCheckGate { num_input_limbs: 8, _phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField> }"
+	numInputLimbs, hasNumInputLimbs := parameters["numInputLimbs"]
+	if !hasNumInputLimbs {
+		panic("Missing field num_input_limbs in U32RangeCheckGate")
+	}
+
+	numInputLimbsInt, err := strconv.Atoi(numInputLimbs)
+	if err != nil {
+		panic("Invalid num_input_limbs field in U32RangeCheckGate")
+	}
+
+	return NewU32RangeCheckGate(uint64(numInputLimbsInt))
+}
+
+const (
+	U32_RANGE_CHECK_GATE_AUX_LIMB_BITS            = 2
+	U32_RANGE_CHECK_GATE_BASE                     = 1 << U32_RANGE_CHECK_GATE_AUX_LIMB_BITS
+	U32_RANGE_CHECK_GATE_AUX_LIMBS_PER_INPUT_LIMB = (32 + U32_RANGE_CHECK_GATE_AUX_LIMB_BITS - 1) / U32_RANGE_CHECK_GATE_AUX_LIMB_BITS
+)
+
+// Checks that each of num_input_limbs values is a u32, by decomposing it into little endian base
+// 4 limbs.  From the plonky2-u32 crate.
+type U32RangeCheckGate struct {
+	numInputLimbs uint64
+}
+
+func NewU32RangeCheckGate(numInputLimbs uint64) *U32RangeCheckGate {
+	return &U32RangeCheckGate{
+		numInputLimbs: numInputLimbs,
+	}
+}
+
+func (g *U32RangeCheckGate) Id() string {
+	return fmt.Sprintf("U32RangeCheckGate { num_input_limbs: %d, _phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField> }", g.numInputLimbs)
+}
+
+func (g *U32RangeCheckGate) WireIthInputLimb(i uint64) uint64 {
+	return i
+}
+
+func (g *U32RangeCheckGate) WireIthInputLimbJthAuxLimb(i uint64, j uint64) uint64 {
+	return g.numInputLimbs + U32_RANGE_CHECK_GATE_AUX_LIMBS_PER_INPUT_LIMB*i + j
+}
+
+func (g *U32RangeCheckGate) EvalUnfiltered(
+	api frontend.API,
+	glApi *gl.Chip,
+	vars EvaluationVars,
+) []gl.QuadraticExtensionVariable {
+	base := gl.NewQuadraticExtensionVariable(gl.NewVariable(uint64(U32_RANGE_CHECK_GATE_BASE)), gl.Zero())
+
+	constraints := []gl.QuadraticExtensionVariable{}
+	for i := uint64(0); i < g.numInputLimbs; i++ {
+		inputLimb := vars.localWires[g.WireIthInputLimb(i)]
+		auxLimbs := make([]gl.QuadraticExtensionVariable, U32_RANGE_CHECK_GATE_AUX_LIMBS_PER_INPUT_LIMB)
+		for j := range auxLimbs {
+			auxLimbs[j] = vars.localWires[g.WireIthInputLimbJthAuxLimb(i, uint64(j))]
+		}
+
+		computedSum := glApi.ReduceWithPowers(auxLimbs, base)
+		constraints = append(constraints, glApi.SubExtension(computedSum, inputLimb))
+		for _, auxLimb := range auxLimbs {
+			constraints = append(constraints, evalRangeCheck(glApi, auxLimb, U32_RANGE_CHECK_GATE_BASE))
+		}
+	}
+
+	return constraints
+}
+
+func (g *U32RangeCheckGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	base := gl.NewQuadraticExtensionFromUint64(U32_RANGE_CHECK_GATE_BASE)
+
+	constraints := []gl.QuadraticExtension{}
+	for i := uint64(0); i < g.numInputLimbs; i++ {
+		inputLimb := vars.localWires[g.WireIthInputLimb(i)]
+		auxLimbs := make([]gl.QuadraticExtension, U32_RANGE_CHECK_GATE_AUX_LIMBS_PER_INPUT_LIMB)
+		for j := range auxLimbs {
+			auxLimbs[j] = vars.localWires[g.WireIthInputLimbJthAuxLimb(i, uint64(j))]
+		}
+
+		computedSum := gl.ReduceWithPowers(auxLimbs, base)
+		constraints = append(constraints, computedSum.Sub(inputLimb))
+		for _, auxLimb := range auxLimbs {
+			constraints = append(constraints, evalRangeCheckNative(auxLimb, U32_RANGE_CHECK_GATE_BASE))
+		}
+	}
+
+	return constraints
+}
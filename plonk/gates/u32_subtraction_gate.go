@@ -0,0 +1,142 @@
+package gates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/consensys/gnark/frontend"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+)
+
+var u32SubtractionGateRegex = regexp.MustCompile("U32SubtractionGate { num_ops: (?P<numOps>[0-9]+), _phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField> }")
+
+func deserializeU32SubtractionGate(parameters map[string]string) Gate {
+	// Has the format "U32SubtractionGate { num_ops: 6, _phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField> }"
+	numOps, hasNumOps := parameters["numOps"]
+	if !hasNumOps {
+		panic("Missing field num_ops in U32SubtractionGate")
+	}
+
+	numOpsInt, err := strconv.Atoi(numOps)
+	if err != nil {
+		panic("Invalid num_ops field in U32SubtractionGate")
+	}
+
+	return NewU32SubtractionGate(uint64(numOpsInt))
+}
+
+const (
+	U32_SUBTRACTION_GATE_LIMB_BITS           = 2
+	U32_SUBTRACTION_GATE_NUM_LIMBS           = 32 / U32_SUBTRACTION_GATE_LIMB_BITS
+	U32_SUBTRACTION_GATE_ROUTED_WIRES_PER_OP = 5
+)
+
+// Computes x - y - borrow for u32 operands, as a u32 result and an output borrow bit.  From the
+// plonky2-u32 crate.
+type U32SubtractionGate struct {
+	numOps uint64
+}
+
+func NewU32SubtractionGate(numOps uint64) *U32SubtractionGate {
+	return &U32SubtractionGate{
+		numOps: numOps,
+	}
+}
+
+func (g *U32SubtractionGate) Id() string {
+	return fmt.Sprintf("U32SubtractionGate { num_ops: %d, _phantom: PhantomData<plonky2_field::goldilocks_field::GoldilocksField> }", g.numOps)
+}
+
+func (g *U32SubtractionGate) WireIthInputX(i uint64) uint64 {
+	return U32_SUBTRACTION_GATE_ROUTED_WIRES_PER_OP * i
+}
+
+func (g *U32SubtractionGate) WireIthInputY(i uint64) uint64 {
+	return U32_SUBTRACTION_GATE_ROUTED_WIRES_PER_OP*i + 1
+}
+
+func (g *U32SubtractionGate) WireIthInputBorrow(i uint64) uint64 {
+	return U32_SUBTRACTION_GATE_ROUTED_WIRES_PER_OP*i + 2
+}
+
+func (g *U32SubtractionGate) WireIthOutputResult(i uint64) uint64 {
+	return U32_SUBTRACTION_GATE_ROUTED_WIRES_PER_OP*i + 3
+}
+
+func (g *U32SubtractionGate) WireIthOutputBorrow(i uint64) uint64 {
+	return U32_SUBTRACTION_GATE_ROUTED_WIRES_PER_OP*i + 4
+}
+
+func (g *U32SubtractionGate) WireIthOutputJthLimb(i uint64, j uint64) uint64 {
+	return U32_SUBTRACTION_GATE_ROUTED_WIRES_PER_OP*g.numOps + U32_SUBTRACTION_GATE_NUM_LIMBS*i + j
+}
+
+func (g *U32SubtractionGate) EvalUnfiltered(
+	api frontend.API,
+	glApi *gl.Chip,
+	vars EvaluationVars,
+) []gl.QuadraticExtensionVariable {
+	one := gl.OneExtension()
+	outputBase := gl.NewQuadraticExtensionVariable(gl.NewVariable(uint64(1<<32)), gl.Zero())
+	limbBase := gl.NewQuadraticExtensionVariable(gl.NewVariable(uint64(1<<U32_SUBTRACTION_GATE_LIMB_BITS)), gl.Zero())
+
+	constraints := []gl.QuadraticExtensionVariable{}
+	for i := uint64(0); i < g.numOps; i++ {
+		inputX := vars.localWires[g.WireIthInputX(i)]
+		inputY := vars.localWires[g.WireIthInputY(i)]
+		inputBorrow := vars.localWires[g.WireIthInputBorrow(i)]
+		resultInitial := glApi.SubExtension(glApi.SubExtension(inputX, inputY), inputBorrow)
+
+		outputResult := vars.localWires[g.WireIthOutputResult(i)]
+		outputBorrow := vars.localWires[g.WireIthOutputBorrow(i)]
+		constraints = append(constraints, glApi.SubExtension(
+			outputResult,
+			glApi.MulAddExtension(outputBase, outputBorrow, resultInitial),
+		))
+
+		// Range check the result to be at most 32 bits.
+		combinedLimbs := gl.ZeroExtension()
+		for j := int(U32_SUBTRACTION_GATE_NUM_LIMBS) - 1; j >= 0; j-- {
+			thisLimb := vars.localWires[g.WireIthOutputJthLimb(i, uint64(j))]
+			constraints = append(constraints, evalRangeCheck(glApi, thisLimb, 1<<U32_SUBTRACTION_GATE_LIMB_BITS))
+			combinedLimbs = glApi.MulAddExtension(limbBase, combinedLimbs, thisLimb)
+		}
+		constraints = append(constraints, glApi.SubExtension(combinedLimbs, outputResult))
+
+		// Range check the output borrow to be one bit.
+		constraints = append(constraints, glApi.MulExtension(outputBorrow, glApi.SubExtension(one, outputBorrow)))
+	}
+
+	return constraints
+}
+
+func (g *U32SubtractionGate) EvalUnfilteredNative(vars NativeEvaluationVars) []gl.QuadraticExtension {
+	one := gl.OneQuadraticExtension()
+	outputBase := gl.NewQuadraticExtensionFromUint64(1 << 32)
+	limbBase := gl.NewQuadraticExtensionFromUint64(1 << U32_SUBTRACTION_GATE_LIMB_BITS)
+
+	constraints := []gl.QuadraticExtension{}
+	for i := uint64(0); i < g.numOps; i++ {
+		inputX := vars.localWires[g.WireIthInputX(i)]
+		inputY := vars.localWires[g.WireIthInputY(i)]
+		inputBorrow := vars.localWires[g.WireIthInputBorrow(i)]
+		resultInitial := inputX.Sub(inputY).Sub(inputBorrow)
+
+		outputResult := vars.localWires[g.WireIthOutputResult(i)]
+		outputBorrow := vars.localWires[g.WireIthOutputBorrow(i)]
+		constraints = append(constraints, outputResult.Sub(resultInitial.Add(outputBase.Mul(outputBorrow))))
+
+		combinedLimbs := gl.ZeroQuadraticExtension()
+		for j := int(U32_SUBTRACTION_GATE_NUM_LIMBS) - 1; j >= 0; j-- {
+			thisLimb := vars.localWires[g.WireIthOutputJthLimb(i, uint64(j))]
+			constraints = append(constraints, evalRangeCheckNative(thisLimb, 1<<U32_SUBTRACTION_GATE_LIMB_BITS))
+			combinedLimbs = limbBase.Mul(combinedLimbs).Add(thisLimb)
+		}
+		constraints = append(constraints, combinedLimbs.Sub(outputResult))
+
+		constraints = append(constraints, outputBorrow.Mul(one.Sub(outputBorrow)))
+	}
+
+	return constraints
+}
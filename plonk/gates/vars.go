@@ -40,3 +40,40 @@ func (e *EvaluationVars) GetLocalExtAlgebra(wireRange Range) gl.QuadraticExtensi
 
 	return ret
 }
+
+// The out of circuit counterpart of EvaluationVars.
+type NativeEvaluationVars struct {
+	localConstants   []gl.QuadraticExtension
+	localWires       []gl.QuadraticExtension
+	publicInputsHash poseidon.GoldilocksNativeHashOut
+}
+
+func NewNativeEvaluationVars(
+	localConstants []gl.QuadraticExtension,
+	localWires []gl.QuadraticExtension,
+	publicInputsHash poseidon.GoldilocksNativeHashOut,
+) *NativeEvaluationVars {
+	return &NativeEvaluationVars{
+		localConstants:   localConstants,
+		localWires:       localWires,
+		publicInputsHash: publicInputsHash,
+	}
+}
+
+func (e *NativeEvaluationVars) RemovePrefix(numSelectors uint64) {
+	e.localConstants = e.localConstants[numSelectors:]
+}
+
+func (e *NativeEvaluationVars) GetLocalExtAlgebra(wireRange Range) gl.QuadraticExtensionAlgebra {
+	// For now, only support degree 2
+	if wireRange.end-wireRange.start != gl.D {
+		panic("Range must be of size D")
+	}
+
+	var ret gl.QuadraticExtensionAlgebra
+	for i := wireRange.start; i < wireRange.end; i++ {
+		ret[i-wireRange.start] = e.localWires[i]
+	}
+
+	return ret
+}
@@ -0,0 +1,227 @@
+package plonk
+
+import (
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/plonk/gates"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+	"github.com/succinctlabs/gnark-plonky2-verifier/variables"
+)
+
+// Evaluates the lookup table as a polynomial at delta, i.e. the value that the running evaluation
+// polynomial must reach once it has gone through all of the table's rows.  The entries are
+// combined with challenge b, and the table is padded with zeros to fill its last row.
+func (p *PlonkChip) evalLutPoly(lut types.LookupTable, deltas []gl.Variable, degree uint64) gl.Variable {
+	glApi := gl.New(p.api)
+	b := deltas[gates.LOOKUP_CHALLENGE_B]
+	delta := deltas[gates.LOOKUP_CHALLENGE_DELTA]
+
+	inputs := gl.Zero()
+	outputs := gl.Zero()
+	for _, entry := range lut {
+		inputs = glApi.MulAdd(inputs, delta, gl.NewVariable(uint64(entry[0])))
+		outputs = glApi.MulAdd(outputs, delta, gl.NewVariable(uint64(entry[1])))
+	}
+	eval := glApi.MulAdd(b, outputs, inputs)
+
+	for i := uint64(len(lut)); i < degree; i++ {
+		eval = glApi.Mul(eval, delta)
+	}
+
+	return eval
+}
+
+// Computes prod_{j in [start, end), j != skip} (alpha - combos[j]).  Pass skip = end to take the
+// product over the whole range.
+func (p *PlonkChip) lookupProduct(
+	alpha gl.QuadraticExtensionVariable,
+	combos []gl.QuadraticExtensionVariable,
+	start uint64,
+	end uint64,
+	skip uint64,
+) gl.QuadraticExtensionVariable {
+	glApi := gl.New(p.api)
+	product := gl.OneExtension()
+	for j := start; j < end; j++ {
+		if j != skip {
+			product = glApi.MulExtension(product, glApi.SubExtension(alpha, combos[j]))
+		}
+	}
+	return product
+}
+
+// Returns the constraints of plonky2's lookup argument for one of the num_challenges repetitions.
+// It's a port of check_lookup_constraints from plonk::vanishing_poly in plonky2.
+//
+// The first lookup polynomial is the running evaluation (RE) of the lookup tables, which checks
+// that the LookupTableGates hold the tables of the common circuit data.  The others hold partial
+// sums of logarithmic derivatives (SLDC): they add up multiplicity / (alpha - entry) over the
+// LookupTableGates' entries, and then subtract 1 / (alpha - lookup) over the LookupGates' lookups,
+// which must bring the sum back to 0.
+func (p *PlonkChip) checkLookupConstraints(
+	wires []gl.QuadraticExtensionVariable,
+	localLookupZs []gl.QuadraticExtensionVariable,
+	nextLookupZs []gl.QuadraticExtensionVariable,
+	lookupSelectors []gl.QuadraticExtensionVariable,
+	deltas []gl.Variable,
+) []gl.QuadraticExtensionVariable {
+	glApi := gl.New(p.api)
+	numRoutedWires := p.commonData.Config.NumRoutedWires
+	numLuSlots := gates.LookupGateNumSlots(numRoutedWires)
+	numLutSlots := gates.LookupTableGateNumSlots(numRoutedWires)
+	luDegree := p.commonData.QuotientDegreeFactor - 1
+	numSldcPolys := uint64(len(localLookupZs) - 1)
+	lutDegree := (numLutSlots + numSldcPolys - 1) / numSldcPolys
+
+	constraints := make([]gl.QuadraticExtensionVariable, 0, 4+len(p.commonData.Luts)+2*int(numSldcPolys))
+
+	// RE is the first polynomial stored.
+	zRe := localLookupZs[0]
+	nextZRe := nextLookupZs[0]
+
+	// Partial Sums and LDCs are both stored in the remaining SLDC polynomials.
+	zXLookupSldcs := localLookupZs[1 : numSldcPolys+1]
+	zGxLookupSldcs := nextLookupZs[1 : numSldcPolys+1]
+
+	challengeA := deltas[gates.LOOKUP_CHALLENGE_A]
+	challengeB := deltas[gates.LOOKUP_CHALLENGE_B]
+	challengeAlpha := gl.NewQuadraticExtensionVariable(deltas[gates.LOOKUP_CHALLENGE_ALPHA], gl.Zero())
+	challengeDelta := deltas[gates.LOOKUP_CHALLENGE_DELTA]
+
+	// Compute all current looked and looking combos, i.e. the combos we need for the SLDC polynomials.
+	currentLookedCombos := make([]gl.QuadraticExtensionVariable, numLutSlots)
+	currentLookupCombos := make([]gl.QuadraticExtensionVariable, numLutSlots)
+	for s := uint64(0); s < numLutSlots; s++ {
+		inputWire := wires[gates.LookupTableGateWireIthLookedInp(s)]
+		outputWire := wires[gates.LookupTableGateWireIthLookedOut(s)]
+		currentLookedCombos[s] = glApi.AddExtension(inputWire, glApi.ScalarMulExtension(outputWire, challengeA))
+		// The lookup combos are used to check that the LUT is correct.
+		currentLookupCombos[s] = glApi.AddExtension(inputWire, glApi.ScalarMulExtension(outputWire, challengeB))
+	}
+
+	currentLookingCombos := make([]gl.QuadraticExtensionVariable, numLuSlots)
+	for s := uint64(0); s < numLuSlots; s++ {
+		inputWire := wires[gates.LookupGateWireIthLookingInp(s)]
+		outputWire := wires[gates.LookupGateWireIthLookingOut(s)]
+		currentLookingCombos[s] = glApi.AddExtension(inputWire, glApi.ScalarMulExtension(outputWire, challengeA))
+	}
+
+	// Check last LDC constraint.
+	constraints = append(constraints, glApi.MulExtension(
+		lookupSelectors[gates.LOOKUP_SELECTOR_LAST_LDC],
+		zXLookupSldcs[numSldcPolys-1],
+	))
+
+	// Check initial Sum constraint.
+	constraints = append(constraints, glApi.MulExtension(
+		lookupSelectors[gates.LOOKUP_SELECTOR_INIT_SRE],
+		zXLookupSldcs[0],
+	))
+
+	// Check initial RE constraint.
+	constraints = append(constraints, glApi.MulExtension(lookupSelectors[gates.LOOKUP_SELECTOR_INIT_SRE], zRe))
+
+	// Check final RE constraints for each different LUT.
+	for r := uint64(gates.LOOKUP_SELECTOR_START_END); r < p.commonData.NumLookupSelectors; r++ {
+		lut := p.commonData.Luts[r-gates.LOOKUP_SELECTOR_START_END]
+		lutRowNumber := (uint64(len(lut)) + numLutSlots - 1) / numLutSlots
+		curFunctionEval := p.evalLutPoly(lut, deltas, numLutSlots*lutRowNumber)
+
+		constraints = append(constraints, glApi.MulExtension(
+			lookupSelectors[r],
+			glApi.SubExtension(zRe, gl.NewQuadraticExtensionVariable(curFunctionEval, gl.Zero())),
+		))
+	}
+
+	// Check RE row transition constraint.
+	curSum := nextZRe
+	for _, combo := range currentLookupCombos {
+		curSum = glApi.AddExtension(glApi.ScalarMulExtension(curSum, challengeDelta), combo)
+	}
+	constraints = append(constraints, glApi.MulExtension(
+		lookupSelectors[gates.LOOKUP_SELECTOR_TRANS_SRE],
+		glApi.SubExtension(zRe, curSum),
+	))
+
+	for poly := uint64(0); poly < numSldcPolys; poly++ {
+		lutStart, lutEnd := poly*lutDegree, (poly+1)*lutDegree
+		if lutEnd > numLutSlots {
+			lutEnd = numLutSlots
+		}
+		luStart, luEnd := poly*luDegree, (poly+1)*luDegree
+		if luEnd > numLuSlots {
+			luEnd = numLuSlots
+		}
+
+		// Compute prod(alpha - combo) for the current slot for LookupTable and Lookup gates.
+		lutProd := p.lookupProduct(challengeAlpha, currentLookedCombos, lutStart, lutEnd, lutEnd)
+		luProd := p.lookupProduct(challengeAlpha, currentLookingCombos, luStart, luEnd, luEnd)
+
+		// Compute sum_i(multiplicity_i * prod_{j!=i}(alpha - combo_j)) for LookupTable gates.
+		lutSumProds := gl.ZeroExtension()
+		for i := lutStart; i < lutEnd; i++ {
+			lutSumProds = glApi.AddExtension(lutSumProds, glApi.MulExtension(
+				wires[gates.LookupTableGateWireIthMultiplicity(i)],
+				p.lookupProduct(challengeAlpha, currentLookedCombos, lutStart, lutEnd, i),
+			))
+		}
+
+		// Compute sum_i(prod_{j!=i}(alpha - combo_j)) for Lookup gates.
+		luSumProds := gl.ZeroExtension()
+		for i := luStart; i < luEnd; i++ {
+			luSumProds = glApi.AddExtension(luSumProds, p.lookupProduct(challengeAlpha, currentLookingCombos, luStart, luEnd, i))
+		}
+
+		// The previous element is the previous poly of the current row or the last poly of the next row.
+		var prev gl.QuadraticExtensionVariable
+		if poly == 0 {
+			prev = zGxLookupSldcs[numSldcPolys-1]
+		} else {
+			prev = zXLookupSldcs[poly-1]
+		}
+		diff := glApi.SubExtension(zXLookupSldcs[poly], prev)
+
+		// Check Sum row and col transitions.
+		unfilteredSumTransition := glApi.SubExtension(glApi.MulExtension(lutProd, diff), lutSumProds)
+		constraints = append(constraints, glApi.MulExtension(
+			lookupSelectors[gates.LOOKUP_SELECTOR_TRANS_SRE],
+			unfilteredSumTransition,
+		))
+
+		// Check LDC row and col transitions.
+		unfilteredLdcTransition := glApi.AddExtension(glApi.MulExtension(luProd, diff), luSumProds)
+		constraints = append(constraints, glApi.MulExtension(
+			lookupSelectors[gates.LOOKUP_SELECTOR_TRANS_LDC],
+			unfilteredLdcTransition,
+		))
+	}
+
+	return constraints
+}
+
+// Returns the lookup constraints of all of the num_challenges repetitions, or nothing if the
+// circuit doesn't use lookups.
+func (p *PlonkChip) evalLookupTerms(
+	proofChallenges variables.ProofChallenges,
+	openings variables.OpeningSet,
+) []gl.QuadraticExtensionVariable {
+	numLookupPolys := p.commonData.NumLookupPolys
+	if numLookupPolys == 0 {
+		return nil
+	}
+
+	// The lookup selectors come right after the gate selectors.
+	numSelectors := p.commonData.SelectorsInfo.NumSelectors()
+	lookupSelectors := openings.Constants[numSelectors : numSelectors+p.commonData.NumLookupSelectors]
+
+	lookupTerms := []gl.QuadraticExtensionVariable{}
+	for i := uint64(0); i < p.commonData.Config.NumChallenges; i++ {
+		lookupTerms = append(lookupTerms, p.checkLookupConstraints(
+			openings.Wires,
+			openings.LookupZs[i*numLookupPolys:(i+1)*numLookupPolys],
+			openings.LookupZsNext[i*numLookupPolys:(i+1)*numLookupPolys],
+			lookupSelectors,
+			proofChallenges.PlonkDeltas[i*gates.NUM_COINS_LOOKUP:(i+1)*gates.NUM_COINS_LOOKUP],
+		)...)
+	}
+	return lookupTerms
+}
@@ -22,13 +22,32 @@ type PlonkChip struct {
 	commonDataKIs []gl.Variable                 `gnark:"-"`
 
 	evaluateGatesChip *gates.EvaluateGatesChip
+
+	// When not nil, the proof is only checked when enabled is 1.
+	enabled frontend.Variable `gnark:"-"`
 }
 
+// Panics if one of the circuit's gate IDs is unknown or ambiguous.  See NewPlonkChipWithRegistry
+// for a version that returns an error.
 func NewPlonkChip(api frontend.API, commonData types.CommonCircuitData) *PlonkChip {
+	p, err := NewPlonkChipWithRegistry(api, commonData, gates.DefaultRegistry)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Like NewPlonkChip, but looks up the circuit's gates in the given registry, which may contain
+// custom gates, and returns an error when one of them isn't found.
+func NewPlonkChipWithRegistry(api frontend.API, commonData types.CommonCircuitData, registry *gates.Registry) (*PlonkChip, error) {
 	// Create the gates based on commonData GateIds
 	createdGates := []gates.Gate{}
 	for _, gateId := range commonData.GateIds {
-		createdGates = append(createdGates, gates.GateInstanceFromId(gateId))
+		gate, err := registry.TryGateInstanceFromId(gateId)
+		if err != nil {
+			return nil, err
+		}
+		createdGates = append(createdGates, gate)
 	}
 
 	evaluateGatesChip := gates.NewEvaluateGatesChip(
@@ -36,6 +55,7 @@ func NewPlonkChip(api frontend.API, commonData types.CommonCircuitData) *PlonkCh
 		createdGates,
 		commonData.NumGateConstraints,
 		commonData.SelectorsInfo,
+		commonData.NumLookupSelectors,
 	)
 
 	return &PlonkChip{
@@ -49,7 +69,23 @@ func NewPlonkChip(api frontend.API, commonData types.CommonCircuitData) *PlonkCh
 		commonDataKIs: gl.Uint64ArrayToVariableArray(commonData.KIs),
 
 		evaluateGatesChip: evaluateGatesChip,
+	}, nil
+}
+
+// Like NewPlonkChipWithRegistry, but the vanishing polynomial is only checked when enabled is 1,
+// so that the openings don't have to be valid when enabled is 0.  enabled must be 0 or 1.
+func NewConditionalPlonkChip(
+	api frontend.API,
+	commonData types.CommonCircuitData,
+	registry *gates.Registry,
+	enabled frontend.Variable,
+) (*PlonkChip, error) {
+	p, err := NewPlonkChipWithRegistry(api, commonData, registry)
+	if err != nil {
+		return nil, err
 	}
+	p.enabled = enabled
+	return p, nil
 }
 
 func (p *PlonkChip) expPowerOf2Extension(x gl.QuadraticExtensionVariable) gl.QuadraticExtensionVariable {
@@ -183,6 +219,7 @@ func (p *PlonkChip) evalVanishingPoly(
 	}
 
 	vanishingTerms := append(vanishingZ1Terms, vanishingPartialProductsTerms...)
+	vanishingTerms = append(vanishingTerms, p.evalLookupTerms(proofChallenges, openings)...)
 	vanishingTerms = append(vanishingTerms, constraintTerms...)
 
 	reducedValues := make([]gl.QuadraticExtensionVariable, p.commonData.Config.NumChallenges)
@@ -245,6 +282,12 @@ func (p *PlonkChip) Verify(
 			),
 		)
 
-		glApi.AssertIsEqualExtension(vanishingPolysZeta[i], prod)
+		if p.enabled == nil {
+			glApi.AssertIsEqualExtension(vanishingPolysZeta[i], prod)
+		} else {
+			for j := 0; j < 2; j++ {
+				p.api.AssertIsEqual(p.api.Mul(p.enabled, p.api.Sub(vanishingPolysZeta[i][j].Limb, prod[j].Limb)), 0)
+			}
+		}
 	}
 }
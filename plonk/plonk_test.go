@@ -1,12 +1,14 @@
 package plonk_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/test"
 	"github.com/succinctlabs/gnark-plonky2-verifier/plonk"
+	"github.com/succinctlabs/gnark-plonky2-verifier/plonk/gates"
 	"github.com/succinctlabs/gnark-plonky2-verifier/types"
 	"github.com/succinctlabs/gnark-plonky2-verifier/variables"
 	"github.com/succinctlabs/gnark-plonky2-verifier/verifier"
@@ -43,9 +45,11 @@ func TestPlonkDecodeBlock(t *testing.T) {
 	commonCircuitDataFilename := "../testdata/decode_block/common_circuit_data.json"
 	verifierOnlyCircuitDataFilename := "../testdata/decode_block/verifier_only_circuit_data.json"
 
-	proofWithPis := variables.DeserializeProofWithPublicInputs(types.ReadProofWithPublicInputs(proofWithPIsFilename))
+	proofWithPis, err := variables.DeserializeProofWithPublicInputs(types.ReadProofWithPublicInputs(proofWithPIsFilename))
+	assert.NoError(err)
 	commonCircuitData := types.ReadCommonCircuitData(commonCircuitDataFilename)
-	verifierOnlyCircuitData := variables.DeserializeVerifierOnlyCircuitData(types.ReadVerifierOnlyCircuitData(verifierOnlyCircuitDataFilename))
+	verifierOnlyCircuitData, err := variables.DeserializeVerifierOnlyCircuitData(types.ReadVerifierOnlyCircuitData(verifierOnlyCircuitDataFilename))
+	assert.NoError(err)
 
 	testCase := func() {
 		circuit := TestPlonkCircuit{
@@ -64,3 +68,13 @@ func TestPlonkDecodeBlock(t *testing.T) {
 
 	testCase()
 }
+
+func TestPlonkChipUnknownGate(t *testing.T) {
+	commonCircuitData := types.ReadCommonCircuitData("../testdata/decode_block/common_circuit_data.json")
+	commonCircuitData.GateIds = append(append([]string{}, commonCircuitData.GateIds...), "UnknownGate")
+
+	_, err := plonk.NewPlonkChipWithRegistry(nil, commonCircuitData, gates.DefaultRegistry)
+	if err == nil || !strings.Contains(err.Error(), "unknown gate ID UnknownGate") {
+		t.Errorf("expected an error for an unknown gate ID, got %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package poseidon
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+type BN254NativeState = [BN254_SPONGE_WIDTH]fr.Element
+type BN254NativeHashOut = fr.Element
+
+// The out of circuit counterpart of BN254Chip.  It uses the same constants as the chip.
+type BN254Hasher struct{}
+
+func NewBN254Hasher() *BN254Hasher {
+	return &BN254Hasher{}
+}
+
+var (
+	cConstantsNative []fr.Element
+	sConstantsNative []fr.Element
+	mMatrixNative    [][]fr.Element
+	pMatrixNative    [][]fr.Element
+	constantsOnce    sync.Once
+)
+
+// The BN254 constants are set within bn254_constants.go's init function, so convert them lazily.
+func loadBN254NativeConstants() {
+	constantsOnce.Do(func() {
+		cConstantsNative = make([]fr.Element, len(cConstants))
+		for i := range cConstants {
+			cConstantsNative[i].SetBigInt(cConstants[i])
+		}
+
+		sConstantsNative = make([]fr.Element, len(sConstants))
+		for i := range sConstants {
+			sConstantsNative[i].SetBigInt(sConstants[i])
+		}
+
+		mMatrixNative = make([][]fr.Element, len(mMatrix))
+		pMatrixNative = make([][]fr.Element, len(pMatrix))
+		for i := range mMatrix {
+			mMatrixNative[i] = make([]fr.Element, len(mMatrix[i]))
+			pMatrixNative[i] = make([]fr.Element, len(pMatrix[i]))
+			for j := range mMatrix[i] {
+				mMatrixNative[i][j].SetBigInt(mMatrix[i][j])
+				pMatrixNative[i][j].SetBigInt(pMatrix[i][j])
+			}
+		}
+	})
+}
+
+func (h *BN254Hasher) Poseidon(state BN254NativeState) BN254NativeState {
+	loadBN254NativeConstants()
+	state = h.ark(state, 0)
+	state = h.fullRounds(state, true)
+	state = h.partialRounds(state)
+	state = h.fullRounds(state, false)
+	return state
+}
+
+// The out of circuit counterpart of BN254Chip.HashNoPad.
+func (h *BN254Hasher) HashNoPad(input []goldilocks.Element) BN254NativeHashOut {
+	var state BN254NativeState
+
+	for i := 0; i < len(input); i += BN254_SPONGE_RATE * 3 {
+		endI := h.min(len(input), i+BN254_SPONGE_RATE*3)
+		rateChunk := input[i:endI]
+		for j, stateIdx := 0, 0; j < len(rateChunk); j, stateIdx = j+3, stateIdx+1 {
+			endJ := h.min(len(rateChunk), j+3)
+			state[stateIdx+1] = h.pack(rateChunk[j:endJ])
+		}
+
+		state = h.Poseidon(state)
+	}
+
+	return BN254NativeHashOut(state[0])
+}
+
+// The out of circuit counterpart of BN254Chip.HashOrNoop.
+func (h *BN254Hasher) HashOrNoop(input []goldilocks.Element) BN254NativeHashOut {
+	if len(input) <= 3 {
+		return BN254NativeHashOut(h.pack(input))
+	} else {
+		return h.HashNoPad(input)
+	}
+}
+
+// The out of circuit counterpart of BN254Chip.TwoToOne.
+func (h *BN254Hasher) TwoToOne(left BN254NativeHashOut, right BN254NativeHashOut) BN254NativeHashOut {
+	var inputs BN254NativeState
+	inputs[2] = left
+	inputs[3] = right
+	state := h.Poseidon(inputs)
+	return state[0]
+}
+
+// The out of circuit counterpart of BN254Chip.ToVec.
+func (h *BN254Hasher) ToVec(hash BN254NativeHashOut) []goldilocks.Element {
+	var value big.Int
+	hash.BigInt(&value)
+
+	returnElements := []goldilocks.Element{}
+
+	// Split into 7 byte chunks, since 8 byte chunks can result in collisions
+	chunkSize := 56
+	chunkMask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(chunkSize)), big.NewInt(1))
+	for i := 0; i < fr.Bits; i += chunkSize {
+		chunk := new(big.Int).Rsh(&value, uint(i))
+		chunk.And(chunk, chunkMask)
+		returnElements = append(returnElements, goldilocks.NewElement(chunk.Uint64()))
+	}
+
+	return returnElements
+}
+
+// Packs up to 3 goldilocks elements into a single BN254 element, in base 2^64.
+func (h *BN254Hasher) pack(input []goldilocks.Element) fr.Element {
+	var packed, limb, twoTo64 fr.Element
+	twoTo64.SetBigInt(new(big.Int).Lsh(big.NewInt(1), 64))
+
+	for i := len(input) - 1; i >= 0; i-- {
+		packed.Mul(&packed, &twoTo64)
+		limb.SetUint64(input[i].Uint64())
+		packed.Add(&packed, &limb)
+	}
+
+	return packed
+}
+
+func (h *BN254Hasher) min(x, y int) int {
+	if x < y {
+		return x
+	}
+
+	return y
+}
+
+func (h *BN254Hasher) fullRounds(state BN254NativeState, isFirst bool) BN254NativeState {
+	for i := 0; i < BN254_FULL_ROUNDS/2-1; i++ {
+		state = h.exp5state(state)
+		if isFirst {
+			state = h.ark(state, (i+1)*BN254_SPONGE_WIDTH)
+		} else {
+			state = h.ark(state, (BN254_FULL_ROUNDS/2+1)*BN254_SPONGE_WIDTH+BN254_PARTIAL_ROUNDS+i*BN254_SPONGE_WIDTH)
+		}
+		state = h.mix(state, mMatrixNative)
+	}
+
+	state = h.exp5state(state)
+	if isFirst {
+		state = h.ark(state, (BN254_FULL_ROUNDS/2)*BN254_SPONGE_WIDTH)
+		state = h.mix(state, pMatrixNative)
+	} else {
+		state = h.mix(state, mMatrixNative)
+	}
+
+	return state
+}
+
+func (h *BN254Hasher) partialRounds(state BN254NativeState) BN254NativeState {
+	var tmp fr.Element
+	for i := 0; i < BN254_PARTIAL_ROUNDS; i++ {
+		state[0] = h.exp5(state[0])
+		state[0].Add(&state[0], &cConstantsNative[(BN254_FULL_ROUNDS/2+1)*BN254_SPONGE_WIDTH+i])
+
+		var newState0 fr.Element
+		for j := 0; j < BN254_SPONGE_WIDTH; j++ {
+			tmp.Mul(&sConstantsNative[(BN254_SPONGE_WIDTH*2-1)*i+j], &state[j])
+			newState0.Add(&newState0, &tmp)
+		}
+
+		for k := 1; k < BN254_SPONGE_WIDTH; k++ {
+			tmp.Mul(&state[0], &sConstantsNative[(BN254_SPONGE_WIDTH*2-1)*i+BN254_SPONGE_WIDTH+k-1])
+			state[k].Add(&state[k], &tmp)
+		}
+		state[0] = newState0
+	}
+
+	return state
+}
+
+func (h *BN254Hasher) ark(state BN254NativeState, it int) BN254NativeState {
+	var result BN254NativeState
+
+	for i := 0; i < len(state); i++ {
+		result[i].Add(&state[i], &cConstantsNative[it+i])
+	}
+
+	return result
+}
+
+func (h *BN254Hasher) exp5(x fr.Element) fr.Element {
+	var x2, x4 fr.Element
+	x2.Square(&x)
+	x4.Square(&x2)
+	return *x4.Mul(&x4, &x)
+}
+
+func (h *BN254Hasher) exp5state(state BN254NativeState) BN254NativeState {
+	for i := 0; i < BN254_SPONGE_WIDTH; i++ {
+		state[i] = h.exp5(state[i])
+	}
+	return state
+}
+
+func (h *BN254Hasher) mix(state BN254NativeState, constantMatrix [][]fr.Element) BN254NativeState {
+	var result BN254NativeState
+	var tmp fr.Element
+
+	for i := 0; i < BN254_SPONGE_WIDTH; i++ {
+		for j := 0; j < BN254_SPONGE_WIDTH; j++ {
+			tmp.Mul(&constantMatrix[j][i], &state[j])
+			result[i].Add(&result[i], &tmp)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,129 @@
+package poseidon
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+)
+
+// Checks each of the chip's functions against the outputs of BN254Hasher.
+type TestBN254HasherCircuit struct {
+	PoseidonIn    [BN254_SPONGE_WIDTH]frontend.Variable
+	PoseidonOut   [BN254_SPONGE_WIDTH]frontend.Variable
+	HashIn        []frontend.Variable
+	HashNoPadOut  frontend.Variable
+	HashOrNoopOut frontend.Variable
+	Left          frontend.Variable
+	Right         frontend.Variable
+	TwoToOneOut   frontend.Variable
+	ToVecOut      []frontend.Variable
+}
+
+func (circuit *TestBN254HasherCircuit) Define(api frontend.API) error {
+	poseidonChip := NewBN254Chip(api)
+
+	poseidonOut := poseidonChip.Poseidon(circuit.PoseidonIn)
+	for i := 0; i < BN254_SPONGE_WIDTH; i++ {
+		api.AssertIsEqual(poseidonOut[i], circuit.PoseidonOut[i])
+	}
+
+	hashIn := make([]gl.Variable, len(circuit.HashIn))
+	for i := range circuit.HashIn {
+		hashIn[i] = gl.NewVariable(circuit.HashIn[i])
+	}
+	api.AssertIsEqual(poseidonChip.HashNoPad(hashIn), circuit.HashNoPadOut)
+	api.AssertIsEqual(poseidonChip.HashOrNoop(hashIn), circuit.HashOrNoopOut)
+
+	twoToOneOut := poseidonChip.TwoToOne(circuit.Left, circuit.Right)
+	api.AssertIsEqual(twoToOneOut, circuit.TwoToOneOut)
+
+	toVecOut := poseidonChip.ToVec(twoToOneOut)
+	if len(toVecOut) != len(circuit.ToVecOut) {
+		panic("ToVec returned the wrong number of elements")
+	}
+	for i := range toVecOut {
+		api.AssertIsEqual(toVecOut[i].Limb, circuit.ToVecOut[i])
+	}
+
+	return nil
+}
+
+func randomBN254Element(rng *rand.Rand) fr.Element {
+	var element fr.Element
+	element.SetBigInt(new(big.Int).Rand(rng, fr.Modulus()))
+	return element
+}
+
+func TestBN254HasherPoseidon(t *testing.T) {
+	in := BN254NativeState{}
+	for i := range in {
+		in[i].SetUint64(uint64(i))
+	}
+	outStr := []string{
+		"6542985608222806190361240322586112750744169038454362455181422643027100751666",
+		"3478427836468552423396868478117894008061261013954248157992395910462939736589",
+		"1904980799580062506738911865015687096398867595589699208837816975692422464009",
+		"11971464497515232077059236682405357499403220967704831154657374522418385384151",
+	}
+
+	out := NewBN254Hasher().Poseidon(in)
+	for i := range out {
+		if out[i].String() != outStr[i] {
+			t.Errorf("state[%d]: expected %s, got %s", i, outStr[i], out[i].String())
+		}
+	}
+}
+
+func TestBN254HasherMatchesChip(t *testing.T) {
+	// The commit based range checker can't pick its base width for circuits this small.
+	t.Setenv("USE_BIT_DECOMPOSITION_RANGE_CHECK", "true")
+
+	assert := test.NewAssert(t)
+	rng := rand.New(rand.NewSource(0))
+	hasher := NewBN254Hasher()
+
+	// Cover the HashOrNoop packing path, and inputs that span partial and multiple rate chunks.
+	for _, inputLen := range []int{1, 3, 4, 9, 10, 20} {
+		var poseidonIn BN254NativeState
+		for i := range poseidonIn {
+			poseidonIn[i] = randomBN254Element(rng)
+		}
+		poseidonOut := hasher.Poseidon(poseidonIn)
+
+		hashIn := randomGoldilocksElements(rng, inputLen)
+		left := randomBN254Element(rng)
+		right := randomBN254Element(rng)
+		hashNoPadOut := hasher.HashNoPad(hashIn)
+		hashOrNoopOut := hasher.HashOrNoop(hashIn)
+		twoToOneOut := hasher.TwoToOne(left, right)
+		toVecOut := hasher.ToVec(twoToOneOut)
+
+		witness := TestBN254HasherCircuit{
+			HashIn:        elementsToVariables(hashIn),
+			HashNoPadOut:  hashNoPadOut.String(),
+			HashOrNoopOut: hashOrNoopOut.String(),
+			Left:          left.String(),
+			Right:         right.String(),
+			TwoToOneOut:   twoToOneOut.String(),
+			ToVecOut:      elementsToVariables(toVecOut),
+		}
+		for i := range poseidonIn {
+			witness.PoseidonIn[i] = poseidonIn[i].String()
+			witness.PoseidonOut[i] = poseidonOut[i].String()
+		}
+
+		circuit := TestBN254HasherCircuit{
+			HashIn:   make([]frontend.Variable, inputLen),
+			ToVecOut: make([]frontend.Variable, len(toVecOut)),
+		}
+
+		err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err)
+	}
+}
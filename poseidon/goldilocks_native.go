@@ -0,0 +1,293 @@
+package poseidon
+
+import (
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/frontend"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+)
+
+type GoldilocksNativeState = [SPONGE_WIDTH]goldilocks.Element
+type GoldilocksNativeStateExtension = [SPONGE_WIDTH]gl.QuadraticExtension
+type GoldilocksNativeHashOut = [POSEIDON_GL_HASH_SIZE]goldilocks.Element
+
+// The out of circuit counterpart of GoldilocksChip.  It uses the same round constants as the chip.
+type GoldilocksHasher struct{}
+
+func NewGoldilocksHasher() *GoldilocksHasher {
+	return &GoldilocksHasher{}
+}
+
+var (
+	allRoundConstantsNative             = variablesToElements(ALL_ROUND_CONSTANTS)
+	mdsMatrixCircNative                 = variablesToElements(MDS_MATRIX_CIRC)
+	mdsMatrixDiagNative                 = variablesToElements(MDS_MATRIX_DIAG)
+	mds0To0Native                       = variableToElement(MDS0TO0)
+	fastPartialFirstRoundConstantNative = variablesToElements(FAST_PARTIAL_FIRST_ROUND_CONSTANT)
+	fastPartialRoundConstantsNative     = variablesToElements(FAST_PARTIAL_ROUND_CONSTANTS)
+	fastPartialRoundVsNative            = matrixToElements(FAST_PARTIAL_ROUND_VS)
+	fastPartialRoundWHatsNative         = matrixToElements(FAST_PARTIAL_ROUND_W_HATS)
+	fastPartialRoundInitialMatrixNative = matrixToElements(FAST_PARTIAL_ROUND_INITIAL_MATRIX)
+)
+
+func variableToElement(v frontend.Variable) goldilocks.Element {
+	return goldilocks.NewElement(v.(uint64))
+}
+
+func variablesToElements(vs []frontend.Variable) []goldilocks.Element {
+	elements := make([]goldilocks.Element, len(vs))
+	for i := range vs {
+		elements[i] = variableToElement(vs[i])
+	}
+	return elements
+}
+
+func matrixToElements(m [N_PARTIAL_ROUNDS][11]frontend.Variable) [N_PARTIAL_ROUNDS][11]goldilocks.Element {
+	var elements [N_PARTIAL_ROUNDS][11]goldilocks.Element
+	for i := range m {
+		for j := range m[i] {
+			// FAST_PARTIAL_ROUND_INITIAL_MATRIX only sets its first SPONGE_WIDTH - 1 rows.
+			if m[i][j] == nil {
+				continue
+			}
+			elements[i][j] = variableToElement(m[i][j])
+		}
+	}
+	return elements
+}
+
+// The permutation function.
+func (h *GoldilocksHasher) Poseidon(input GoldilocksNativeState) GoldilocksNativeState {
+	state := input
+	roundCounter := 0
+	state = h.fullRounds(state, &roundCounter)
+	state = h.partialRounds(state, &roundCounter)
+	state = h.fullRounds(state, &roundCounter)
+	return state
+}
+
+// The out of circuit counterpart of GoldilocksChip.HashNToMNoPad.
+func (h *GoldilocksHasher) HashNToMNoPad(input []goldilocks.Element, nbOutputs int) []goldilocks.Element {
+	var state GoldilocksNativeState
+
+	for i := 0; i < len(input); i += SPONGE_RATE {
+		for j := 0; j < SPONGE_RATE; j++ {
+			if i+j < len(input) {
+				state[j] = input[i+j]
+			}
+		}
+		state = h.Poseidon(state)
+	}
+
+	var outputs []goldilocks.Element
+
+	for {
+		for i := 0; i < SPONGE_RATE; i++ {
+			outputs = append(outputs, state[i])
+			if len(outputs) == nbOutputs {
+				return outputs
+			}
+		}
+		state = h.Poseidon(state)
+	}
+}
+
+// The out of circuit counterpart of GoldilocksChip.HashNoPad.  Unlike the chip, the input elements
+// are always within the Goldilocks field, so no reduction is needed.
+func (h *GoldilocksHasher) HashNoPad(input []goldilocks.Element) GoldilocksNativeHashOut {
+	var hash GoldilocksNativeHashOut
+	copy(hash[:], h.HashNToMNoPad(input, len(hash)))
+	return hash
+}
+
+func (h *GoldilocksHasher) ToVec(hash GoldilocksNativeHashOut) []goldilocks.Element {
+	return hash[:]
+}
+
+func (h *GoldilocksHasher) fullRounds(state GoldilocksNativeState, roundCounter *int) GoldilocksNativeState {
+	for i := 0; i < HALF_N_FULL_ROUNDS; i++ {
+		state = h.constantLayer(state, roundCounter)
+		state = h.sBoxLayer(state)
+		state = h.mdsLayer(state)
+		*roundCounter += 1
+	}
+	return state
+}
+
+func (h *GoldilocksHasher) partialRounds(state GoldilocksNativeState, roundCounter *int) GoldilocksNativeState {
+	state = h.partialFirstConstantLayer(state)
+	state = h.mdsPartialLayerInit(state)
+
+	for i := 0; i < N_PARTIAL_ROUNDS; i++ {
+		state[0] = h.sBoxMonomial(state[0])
+		state[0].Add(&state[0], &fastPartialRoundConstantsNative[i])
+		state = h.mdsPartialLayerFast(state, i)
+	}
+
+	*roundCounter += N_PARTIAL_ROUNDS
+
+	return state
+}
+
+func (h *GoldilocksHasher) constantLayer(state GoldilocksNativeState, roundCounter *int) GoldilocksNativeState {
+	for i := 0; i < SPONGE_WIDTH; i++ {
+		state[i].Add(&state[i], &allRoundConstantsNative[i+SPONGE_WIDTH*(*roundCounter)])
+	}
+	return state
+}
+
+func (h *GoldilocksHasher) ConstantLayerExtension(state GoldilocksNativeStateExtension, roundCounter *int) GoldilocksNativeStateExtension {
+	for i := 0; i < SPONGE_WIDTH; i++ {
+		roundConstant := gl.NewQuadraticExtensionFromBase(allRoundConstantsNative[i+SPONGE_WIDTH*(*roundCounter)])
+		state[i] = state[i].Add(roundConstant)
+	}
+	return state
+}
+
+func (h *GoldilocksHasher) sBoxMonomial(x goldilocks.Element) goldilocks.Element {
+	var x2, x4, x3 goldilocks.Element
+	x2.Square(&x)
+	x4.Square(&x2)
+	x3.Mul(&x, &x2)
+	return *x3.Mul(&x3, &x4)
+}
+
+func (h *GoldilocksHasher) SBoxMonomialExtension(x gl.QuadraticExtension) gl.QuadraticExtension {
+	x2 := x.Mul(x)
+	x4 := x2.Mul(x2)
+	x3 := x.Mul(x2)
+	return x4.Mul(x3)
+}
+
+func (h *GoldilocksHasher) sBoxLayer(state GoldilocksNativeState) GoldilocksNativeState {
+	for i := 0; i < SPONGE_WIDTH; i++ {
+		state[i] = h.sBoxMonomial(state[i])
+	}
+	return state
+}
+
+func (h *GoldilocksHasher) SBoxLayerExtension(state GoldilocksNativeStateExtension) GoldilocksNativeStateExtension {
+	for i := 0; i < SPONGE_WIDTH; i++ {
+		state[i] = h.SBoxMonomialExtension(state[i])
+	}
+	return state
+}
+
+func (h *GoldilocksHasher) mdsRowShf(r int, v GoldilocksNativeState) goldilocks.Element {
+	var res, tmp goldilocks.Element
+
+	for i := 0; i < SPONGE_WIDTH; i++ {
+		tmp.Mul(&v[(i+r)%SPONGE_WIDTH], &mdsMatrixCircNative[i])
+		res.Add(&res, &tmp)
+	}
+
+	tmp.Mul(&v[r], &mdsMatrixDiagNative[r])
+	res.Add(&res, &tmp)
+	return res
+}
+
+func (h *GoldilocksHasher) MdsRowShfExtension(r int, v GoldilocksNativeStateExtension) gl.QuadraticExtension {
+	res := gl.ZeroQuadraticExtension()
+
+	for i := 0; i < SPONGE_WIDTH; i++ {
+		res = res.Add(v[(i+r)%SPONGE_WIDTH].ScalarMul(mdsMatrixCircNative[i]))
+	}
+
+	res = res.Add(v[r].ScalarMul(mdsMatrixDiagNative[r]))
+	return res
+}
+
+func (h *GoldilocksHasher) mdsLayer(state GoldilocksNativeState) GoldilocksNativeState {
+	var result GoldilocksNativeState
+	for r := 0; r < SPONGE_WIDTH; r++ {
+		result[r] = h.mdsRowShf(r, state)
+	}
+	return result
+}
+
+func (h *GoldilocksHasher) MdsLayerExtension(state GoldilocksNativeStateExtension) GoldilocksNativeStateExtension {
+	var result GoldilocksNativeStateExtension
+	for r := 0; r < SPONGE_WIDTH; r++ {
+		result[r] = h.MdsRowShfExtension(r, state)
+	}
+	return result
+}
+
+func (h *GoldilocksHasher) partialFirstConstantLayer(state GoldilocksNativeState) GoldilocksNativeState {
+	for i := 0; i < SPONGE_WIDTH; i++ {
+		state[i].Add(&state[i], &fastPartialFirstRoundConstantNative[i])
+	}
+	return state
+}
+
+func (h *GoldilocksHasher) PartialFirstConstantLayerExtension(state GoldilocksNativeStateExtension) GoldilocksNativeStateExtension {
+	for i := 0; i < SPONGE_WIDTH; i++ {
+		state[i] = state[i].Add(gl.NewQuadraticExtensionFromBase(fastPartialFirstRoundConstantNative[i]))
+	}
+	return state
+}
+
+func (h *GoldilocksHasher) mdsPartialLayerInit(state GoldilocksNativeState) GoldilocksNativeState {
+	var result GoldilocksNativeState
+	result[0] = state[0]
+
+	var tmp goldilocks.Element
+	for r := 1; r < SPONGE_WIDTH; r++ {
+		for d := 1; d < SPONGE_WIDTH; d++ {
+			tmp.Mul(&state[r], &fastPartialRoundInitialMatrixNative[r-1][d-1])
+			result[d].Add(&result[d], &tmp)
+		}
+	}
+
+	return result
+}
+
+func (h *GoldilocksHasher) MdsPartialLayerInitExtension(state GoldilocksNativeStateExtension) GoldilocksNativeStateExtension {
+	var result GoldilocksNativeStateExtension
+	for i := 0; i < SPONGE_WIDTH; i++ {
+		result[i] = gl.ZeroQuadraticExtension()
+	}
+
+	result[0] = state[0]
+
+	for r := 1; r < SPONGE_WIDTH; r++ {
+		for d := 1; d < SPONGE_WIDTH; d++ {
+			t := fastPartialRoundInitialMatrixNative[r-1][d-1]
+			result[d] = result[d].Add(state[r].ScalarMul(t))
+		}
+	}
+
+	return result
+}
+
+func (h *GoldilocksHasher) mdsPartialLayerFast(state GoldilocksNativeState, r int) GoldilocksNativeState {
+	var d, tmp goldilocks.Element
+	d.Mul(&state[0], &mds0To0Native)
+	for i := 1; i < SPONGE_WIDTH; i++ {
+		tmp.Mul(&state[i], &fastPartialRoundWHatsNative[r][i-1])
+		d.Add(&d, &tmp)
+	}
+
+	var result GoldilocksNativeState
+	result[0] = d
+	for i := 1; i < SPONGE_WIDTH; i++ {
+		tmp.Mul(&state[0], &fastPartialRoundVsNative[r][i-1])
+		result[i].Add(&tmp, &state[i])
+	}
+
+	return result
+}
+
+func (h *GoldilocksHasher) MdsPartialLayerFastExtension(state GoldilocksNativeStateExtension, r int) GoldilocksNativeStateExtension {
+	d := state[0].ScalarMul(mds0To0Native)
+	for i := 1; i < SPONGE_WIDTH; i++ {
+		d = d.Add(state[i].ScalarMul(fastPartialRoundWHatsNative[r][i-1]))
+	}
+
+	var result GoldilocksNativeStateExtension
+	result[0] = d
+	for i := 1; i < SPONGE_WIDTH; i++ {
+		result[i] = state[0].ScalarMul(fastPartialRoundVsNative[r][i-1]).Add(state[i])
+	}
+
+	return result
+}
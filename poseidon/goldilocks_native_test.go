@@ -0,0 +1,143 @@
+package poseidon
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+)
+
+// Checks the chip's permutation and hashers against the outputs of GoldilocksHasher.
+type TestGoldilocksHasherCircuit struct {
+	PoseidonIn    [SPONGE_WIDTH]frontend.Variable
+	PoseidonOut   [SPONGE_WIDTH]frontend.Variable
+	HashIn        []frontend.Variable
+	HashNoPadOut  [POSEIDON_GL_HASH_SIZE]frontend.Variable
+	HashNToMNoPad []frontend.Variable
+}
+
+func (circuit *TestGoldilocksHasherCircuit) Define(api frontend.API) error {
+	poseidonChip := NewGoldilocksChip(api)
+	glApi := gl.New(api)
+
+	var poseidonIn GoldilocksState
+	for i := 0; i < SPONGE_WIDTH; i++ {
+		poseidonIn[i] = gl.NewVariable(circuit.PoseidonIn[i])
+	}
+	poseidonOut := poseidonChip.Poseidon(poseidonIn)
+	for i := 0; i < SPONGE_WIDTH; i++ {
+		glApi.AssertIsEqual(poseidonOut[i], gl.NewVariable(circuit.PoseidonOut[i]))
+	}
+
+	hashIn := make([]gl.Variable, len(circuit.HashIn))
+	for i := range circuit.HashIn {
+		hashIn[i] = gl.NewVariable(circuit.HashIn[i])
+	}
+
+	hashNoPadOut := poseidonChip.HashNoPad(hashIn)
+	for i := 0; i < POSEIDON_GL_HASH_SIZE; i++ {
+		glApi.AssertIsEqual(hashNoPadOut[i], gl.NewVariable(circuit.HashNoPadOut[i]))
+	}
+
+	hashNToMNoPadOut := poseidonChip.HashNToMNoPad(hashIn, len(circuit.HashNToMNoPad))
+	for i := range circuit.HashNToMNoPad {
+		glApi.AssertIsEqual(hashNToMNoPadOut[i], gl.NewVariable(circuit.HashNToMNoPad[i]))
+	}
+
+	return nil
+}
+
+func randomGoldilocksElements(rng *rand.Rand, n int) []goldilocks.Element {
+	elements := make([]goldilocks.Element, n)
+	for i := range elements {
+		elements[i] = goldilocks.NewElement(rng.Uint64())
+	}
+	return elements
+}
+
+func elementsToVariables(elements []goldilocks.Element) []frontend.Variable {
+	variables := make([]frontend.Variable, len(elements))
+	for i := range elements {
+		variables[i] = elements[i].Uint64()
+	}
+	return variables
+}
+
+func TestGoldilocksHasherZeroState(t *testing.T) {
+	outStr := []string{
+		"4330397376401421145", "14124799381142128323", "8742572140681234676",
+		"14345658006221440202", "15524073338516903644", "5091405722150716653",
+		"15002163819607624508", "2047012902665707362", "16106391063450633726",
+		"4680844749859802542", "15019775476387350140", "1698615465718385111",
+	}
+
+	var in GoldilocksNativeState
+	out := NewGoldilocksHasher().Poseidon(in)
+	for i := range out {
+		if out[i].String() != outStr[i] {
+			t.Errorf("state[%d]: expected %s, got %s", i, outStr[i], out[i].String())
+		}
+	}
+}
+
+func TestGoldilocksHasherMatchesChip(t *testing.T) {
+	// The commit based range checker can't pick its base width for circuits this small.
+	t.Setenv("USE_BIT_DECOMPOSITION_RANGE_CHECK", "true")
+
+	assert := test.NewAssert(t)
+	rng := rand.New(rand.NewSource(0))
+	hasher := NewGoldilocksHasher()
+
+	// Cover partial and full rate chunks, and outputs that need more than one squeeze.
+	testCases := []struct {
+		inputLen  int
+		nbOutputs int
+	}{
+		{1, 4},
+		{3, 4},
+		{SPONGE_RATE, 1},
+		{SPONGE_RATE + 1, SPONGE_RATE},
+		{2*SPONGE_RATE + 5, SPONGE_RATE + 3},
+	}
+
+	for _, testCase := range testCases {
+		var poseidonIn GoldilocksNativeState
+		copy(poseidonIn[:], randomGoldilocksElements(rng, SPONGE_WIDTH))
+		poseidonOut := hasher.Poseidon(poseidonIn)
+
+		hashIn := randomGoldilocksElements(rng, testCase.inputLen)
+		hashNoPadOut := hasher.HashNoPad(hashIn)
+		hashNToMNoPadOut := hasher.HashNToMNoPad(hashIn, testCase.nbOutputs)
+
+		var witness TestGoldilocksHasherCircuit
+		copy(witness.PoseidonIn[:], elementsToVariables(poseidonIn[:]))
+		copy(witness.PoseidonOut[:], elementsToVariables(poseidonOut[:]))
+		witness.HashIn = elementsToVariables(hashIn)
+		copy(witness.HashNoPadOut[:], elementsToVariables(hashNoPadOut[:]))
+		witness.HashNToMNoPad = elementsToVariables(hashNToMNoPadOut)
+
+		circuit := TestGoldilocksHasherCircuit{
+			HashIn:        make([]frontend.Variable, testCase.inputLen),
+			HashNToMNoPad: make([]frontend.Variable, testCase.nbOutputs),
+		}
+
+		err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err)
+	}
+}
+
+func TestGoldilocksHasherPublicInputsHash(t *testing.T) {
+	in := []goldilocks.Element{goldilocks.NewElement(0), goldilocks.NewElement(1), goldilocks.NewElement(3736710860384812976)}
+	outStr := []string{"8416658900775745054", "12574228347150446423", "9629056739760131473", "3119289788404190010"}
+
+	out := NewGoldilocksHasher().HashNoPad(in)
+	for i := range out {
+		if out[i].String() != outStr[i] {
+			t.Errorf("hash[%d]: expected %s, got %s", i, outStr[i], out[i].String())
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+)
+
+// Readers for plonky2's native byte serialization (the `to_bytes`/`from_bytes` Buffer format) of
+// proofs made with the PoseidonBN128 config.  They produce the same raw structs as the JSON
+// readers, so the result can be validated and deserialized into the circuit's witness in the
+// same way.  As in plonky2, most of the lengths aren't serialized and are instead implied by the
+// CommonCircuitData.
+
+// PoseidonBN128 hashes are serialized as a little endian 32 byte integer.
+const bn254HashSize = 32
+
+// Decodes plonky2's Buffer format.  After the first failed read, every read returns a zero value
+// and err holds the path of the value that could not be read.
+type binaryReader struct {
+	data   []byte
+	offset int
+	err    error
+}
+
+func (r *binaryReader) read(path string, n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.data)-r.offset {
+		r.err = &ParseError{Path: path, Err: io.ErrUnexpectedEOF}
+		return nil
+	}
+	bytes := r.data[r.offset : r.offset+n]
+	r.offset += n
+	return bytes
+}
+
+func (r *binaryReader) readU8(path string) uint8 {
+	bytes := r.read(path, 1)
+	if bytes == nil {
+		return 0
+	}
+	return bytes[0]
+}
+
+func (r *binaryReader) readU64(path string) uint64 {
+	bytes := r.read(path, 8)
+	if bytes == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(bytes)
+}
+
+// Reads a usize, which plonky2 serializes as a u64.  Checks that at least elementSize bytes are
+// left for each of the elements that the length counts, so that a corrupted length can't trigger
+// a huge allocation.
+func (r *binaryReader) readLen(path string, elementSize int) int {
+	length := r.readU64(path)
+	if r.err != nil {
+		return 0
+	}
+	if length > uint64((len(r.data)-r.offset)/elementSize) {
+		r.err = &ParseError{Path: path, Err: fmt.Errorf("length %d exceeds the remaining input", length)}
+		return 0
+	}
+	return int(length)
+}
+
+func (r *binaryReader) readFieldVec(path string, length uint64) []uint64 {
+	if r.err != nil {
+		return nil
+	}
+	if length > uint64((len(r.data)-r.offset)/8) {
+		r.err = &ParseError{Path: path, Err: io.ErrUnexpectedEOF}
+		return nil
+	}
+
+	elements := make([]uint64, length)
+	for i := range elements {
+		elements[i] = r.readU64(path + "." + strconv.Itoa(i))
+	}
+	return elements
+}
+
+func (r *binaryReader) readFieldExtVec(path string, length uint64) [][]uint64 {
+	if r.err != nil {
+		return nil
+	}
+	if length > uint64((len(r.data)-r.offset)/16) {
+		r.err = &ParseError{Path: path, Err: io.ErrUnexpectedEOF}
+		return nil
+	}
+
+	elements := make([][]uint64, length)
+	for i := range elements {
+		elements[i] = r.readFieldVec(path+"."+strconv.Itoa(i), 2)
+	}
+	return elements
+}
+
+func (r *binaryReader) readHash(path string) string {
+	bytes := r.read(path, bn254HashSize)
+	if bytes == nil {
+		return ""
+	}
+
+	// Convert to big endian for big.Int.
+	bigEndian := make([]byte, bn254HashSize)
+	for i := range bytes {
+		bigEndian[bn254HashSize-1-i] = bytes[i]
+	}
+	return new(big.Int).SetBytes(bigEndian).String()
+}
+
+func (r *binaryReader) readMerkleCap(path string, capHeight uint64) []string {
+	if r.err != nil {
+		return nil
+	}
+	if capHeight >= 64 || uint64(1)<<capHeight > uint64((len(r.data)-r.offset)/bn254HashSize) {
+		r.err = &ParseError{Path: path, Err: io.ErrUnexpectedEOF}
+		return nil
+	}
+
+	cap := make([]string, uint64(1)<<capHeight)
+	for i := range cap {
+		cap[i] = r.readHash(path + "." + strconv.Itoa(i))
+	}
+	return cap
+}
+
+// Merkle proofs are serialized with their number of siblings as a u8.
+func (r *binaryReader) readMerkleProof(path string) []string {
+	length := r.readU8(path)
+	if r.err != nil {
+		return nil
+	}
+
+	siblings := make([]string, length)
+	for i := range siblings {
+		siblings[i] = r.readHash(path + "." + strconv.Itoa(i))
+	}
+	return siblings
+}
+
+func (r *binaryReader) finish() error {
+	if r.err == nil && r.offset != len(r.data) {
+		r.err = &ParseError{Err: fmt.Errorf("%d trailing bytes", len(r.data)-r.offset)}
+	}
+	return r.err
+}
+
+// Reads a ProofWithPublicInputs serialized with plonky2's `to_bytes`, which the common circuit
+// data is needed to decode.
+func ParseProofWithPublicInputsBinary(r io.Reader, commonData *CommonCircuitData) (ProofWithPublicInputsRaw, error) {
+	var raw ProofWithPublicInputsRaw
+
+	data, err := readAll(r)
+	if err != nil {
+		return raw, err
+	}
+
+	reader := &binaryReader{data: data}
+	friParams := commonData.FriParams
+	capHeight := friParams.Config.CapHeight
+	numChallenges := commonData.Config.NumChallenges
+
+	raw.Proof.WiresCap = reader.readMerkleCap("proof.wires_cap", capHeight)
+	raw.Proof.PlonkZsPartialProductsCap = reader.readMerkleCap("proof.plonk_zs_partial_products_cap", capHeight)
+	raw.Proof.QuotientPolysCap = reader.readMerkleCap("proof.quotient_polys_cap", capHeight)
+
+	openings := &raw.Proof.Openings
+	openings.Constants = reader.readFieldExtVec("proof.openings.constants", commonData.NumConstants)
+	openings.PlonkSigmas = reader.readFieldExtVec("proof.openings.plonk_sigmas", commonData.Config.NumRoutedWires)
+	openings.Wires = reader.readFieldExtVec("proof.openings.wires", commonData.Config.NumWires)
+	openings.PlonkZs = reader.readFieldExtVec("proof.openings.plonk_zs", numChallenges)
+	openings.PlonkZsNext = reader.readFieldExtVec("proof.openings.plonk_zs_next", numChallenges)
+	// The JSON of a proof has the lookup openings when the common data has the lookup fields.
+	if numLookupPolys := commonData.NumAllLookupPolys(); numLookupPolys != 0 || commonData.HasLookupFields {
+		lookupZs := reader.readFieldExtVec("proof.openings.lookup_zs", numLookupPolys)
+		lookupZsNext := reader.readFieldExtVec("proof.openings.lookup_zs_next", numLookupPolys)
+		openings.LookupZs = &lookupZs
+		openings.LookupZsNext = &lookupZsNext
+	}
+	openings.PartialProducts = reader.readFieldExtVec(
+		"proof.openings.partial_products",
+		numChallenges*commonData.NumPartialProducts,
+	)
+	openings.QuotientPolys = reader.readFieldExtVec(
+		"proof.openings.quotient_polys",
+		numChallenges*commonData.QuotientDegreeFactor,
+	)
+
+	openingProof := &raw.Proof.OpeningProof
+	openingProof.CommitPhaseMerkleCaps = make([][]string, len(friParams.ReductionArityBits))
+	for i := range openingProof.CommitPhaseMerkleCaps {
+		path := fmt.Sprintf("proof.opening_proof.commit_phase_merkle_caps.%d", i)
+		openingProof.CommitPhaseMerkleCaps[i] = reader.readMerkleCap(path, capHeight)
+	}
+
+	leafLens := commonData.OracleLeafLens()
+	openingProof.QueryRoundProofs = make([]FriQueryRoundRaw, friParams.Config.NumQueryRounds)
+	for i := range openingProof.QueryRoundProofs {
+		if reader.err != nil {
+			break
+		}
+		roundPath := fmt.Sprintf("proof.opening_proof.query_round_proofs.%d", i)
+		queryRound := &openingProof.QueryRoundProofs[i]
+
+		queryRound.InitialTreesProof.EvalsProofs = make([]EvalProofRaw, len(leafLens))
+		for j := range queryRound.InitialTreesProof.EvalsProofs {
+			evalsProofPath := fmt.Sprintf("%s.initial_trees_proof.evals_proofs.%d", roundPath, j)
+			evalsProof := &queryRound.InitialTreesProof.EvalsProofs[j]
+			evalsProof.LeafElements = reader.readFieldVec(evalsProofPath+".0", leafLens[j])
+			evalsProof.MerkleProof.Hash = reader.readMerkleProof(evalsProofPath + ".1.siblings")
+		}
+
+		queryRound.Steps = make([]FriQueryStepRaw, len(friParams.ReductionArityBits))
+		for j, arityBits := range friParams.ReductionArityBits {
+			stepPath := fmt.Sprintf("%s.steps.%d", roundPath, j)
+			queryRound.Steps[j].Evals = reader.readFieldExtVec(stepPath+".evals", uint64(1)<<arityBits)
+			queryRound.Steps[j].MerkleProof.Siblings = reader.readMerkleProof(stepPath + ".merkle_proof.siblings")
+		}
+	}
+
+	openingProof.FinalPoly.Coeffs = reader.readFieldExtVec(
+		"proof.opening_proof.final_poly.coeffs",
+		uint64(friParams.FinalPolyLen()),
+	)
+	openingProof.PowWitness = reader.readU64("proof.opening_proof.pow_witness")
+
+	numPublicInputs := reader.readLen("public_inputs", 8)
+	raw.PublicInputs = reader.readFieldVec("public_inputs", uint64(numPublicInputs))
+
+	return raw, reader.finish()
+}
+
+// Reads a VerifierOnlyCircuitData serialized with plonky2's `to_bytes`.
+func ParseVerifierOnlyCircuitDataBinary(r io.Reader) (VerifierOnlyCircuitDataRaw, error) {
+	var raw VerifierOnlyCircuitDataRaw
+
+	data, err := readAll(r)
+	if err != nil {
+		return raw, err
+	}
+
+	reader := &binaryReader{data: data}
+	capHeight := reader.readU64("constants_sigmas_cap")
+	raw.ConstantsSigmasCap = reader.readMerkleCap("constants_sigmas_cap", capHeight)
+	raw.CircuitDigest = reader.readHash("circuit_digest")
+
+	return raw, reader.finish()
+}
@@ -0,0 +1,231 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// Serializes proofs in plonky2's Buffer format, mirroring plonky2's Write trait.
+type binaryWriter struct {
+	bytes.Buffer
+}
+
+func (w *binaryWriter) writeU64(value uint64) {
+	w.Write(binary.LittleEndian.AppendUint64(nil, value))
+}
+
+func (w *binaryWriter) writeFieldVec(values []uint64) {
+	for _, value := range values {
+		w.writeU64(value)
+	}
+}
+
+func (w *binaryWriter) writeFieldExtVec(values [][]uint64) {
+	for _, value := range values {
+		w.writeFieldVec(value)
+	}
+}
+
+func (w *binaryWriter) writeHash(t *testing.T, hash string) {
+	value, ok := new(big.Int).SetString(hash, 10)
+	if !ok {
+		t.Fatalf("bad hash %s", hash)
+	}
+	bigEndian := value.FillBytes(make([]byte, bn254HashSize))
+	for i := bn254HashSize - 1; i >= 0; i-- {
+		w.WriteByte(bigEndian[i])
+	}
+}
+
+func (w *binaryWriter) writeMerkleCap(t *testing.T, cap []string) {
+	for _, hash := range cap {
+		w.writeHash(t, hash)
+	}
+}
+
+func (w *binaryWriter) writeMerkleProof(t *testing.T, siblings []string) {
+	w.WriteByte(uint8(len(siblings)))
+	for _, hash := range siblings {
+		w.writeHash(t, hash)
+	}
+}
+
+func proofWithPublicInputsToBytes(t *testing.T, raw *ProofWithPublicInputsRaw) []byte {
+	var w binaryWriter
+
+	w.writeMerkleCap(t, raw.Proof.WiresCap)
+	w.writeMerkleCap(t, raw.Proof.PlonkZsPartialProductsCap)
+	w.writeMerkleCap(t, raw.Proof.QuotientPolysCap)
+
+	openings := raw.Proof.Openings
+	w.writeFieldExtVec(openings.Constants)
+	w.writeFieldExtVec(openings.PlonkSigmas)
+	w.writeFieldExtVec(openings.Wires)
+	w.writeFieldExtVec(openings.PlonkZs)
+	w.writeFieldExtVec(openings.PlonkZsNext)
+	w.writeFieldExtVec(LookupOpeningValues(openings.LookupZs))
+	w.writeFieldExtVec(LookupOpeningValues(openings.LookupZsNext))
+	w.writeFieldExtVec(openings.PartialProducts)
+	w.writeFieldExtVec(openings.QuotientPolys)
+
+	openingProof := raw.Proof.OpeningProof
+	for _, cap := range openingProof.CommitPhaseMerkleCaps {
+		w.writeMerkleCap(t, cap)
+	}
+	for _, queryRound := range openingProof.QueryRoundProofs {
+		for _, evalsProof := range queryRound.InitialTreesProof.EvalsProofs {
+			w.writeFieldVec(evalsProof.LeafElements)
+			w.writeMerkleProof(t, evalsProof.MerkleProof.Hash)
+		}
+		for _, step := range queryRound.Steps {
+			w.writeFieldExtVec(step.Evals)
+			w.writeMerkleProof(t, step.MerkleProof.Siblings)
+		}
+	}
+	w.writeFieldExtVec(openingProof.FinalPoly.Coeffs)
+	w.writeU64(openingProof.PowWitness)
+
+	w.writeU64(uint64(len(raw.PublicInputs)))
+	w.writeFieldVec(raw.PublicInputs)
+
+	return w.Bytes()
+}
+
+func verifierOnlyCircuitDataToBytes(t *testing.T, raw *VerifierOnlyCircuitDataRaw, capHeight uint64) []byte {
+	var w binaryWriter
+	w.writeU64(capHeight)
+	w.writeMerkleCap(t, raw.ConstantsSigmasCap)
+	w.writeHash(t, raw.CircuitDigest)
+	return w.Bytes()
+}
+
+func TestParseBinary(t *testing.T) {
+	for _, testCase := range []string{"decode_block", "step"} {
+		commonCircuitData := ReadCommonCircuitData("../testdata/" + testCase + "/common_circuit_data.json")
+		proofWithPis := ReadProofWithPublicInputs("../testdata/" + testCase + "/proof_with_public_inputs.json")
+		verifierOnlyCircuitData := ReadVerifierOnlyCircuitData("../testdata/" + testCase + "/verifier_only_circuit_data.json")
+
+		proofBytes := proofWithPublicInputsToBytes(t, &proofWithPis)
+		parsedProof, err := ParseProofWithPublicInputsBinary(bytes.NewReader(proofBytes), &commonCircuitData)
+		if err != nil {
+			t.Fatalf("%s: %v", testCase, err)
+		}
+		if !reflect.DeepEqual(parsedProof, proofWithPis) {
+			t.Errorf("%s: the binary proof does not match the JSON proof", testCase)
+		}
+
+		capHeight := commonCircuitData.FriParams.Config.CapHeight
+		verifierDataBytes := verifierOnlyCircuitDataToBytes(t, &verifierOnlyCircuitData, capHeight)
+		parsedVerifierData, err := ParseVerifierOnlyCircuitDataBinary(bytes.NewReader(verifierDataBytes))
+		if err != nil {
+			t.Fatalf("%s: %v", testCase, err)
+		}
+		if !reflect.DeepEqual(parsedVerifierData, verifierOnlyCircuitData) {
+			t.Errorf("%s: the binary verifier only circuit data does not match the JSON data", testCase)
+		}
+	}
+}
+
+// Unlike TestParseBinary, parses bytes that plonky2 serialized, so that the parser and
+// binaryWriter are both checked against plonky2 rather than against each other.
+func TestParseBinaryFixture(t *testing.T) {
+	const testCase = "decode_block"
+	proofBytes, err := os.ReadFile("../testdata/" + testCase + "/proof_with_public_inputs.bin")
+	if err != nil {
+		t.Fatalf("%v, see testdata/binary.md", err)
+	}
+	verifierDataBytes, err := os.ReadFile("../testdata/" + testCase + "/verifier_only_circuit_data.bin")
+	if err != nil {
+		t.Fatalf("%v, see testdata/binary.md", err)
+	}
+
+	commonCircuitData := ReadCommonCircuitData("../testdata/" + testCase + "/common_circuit_data.json")
+	proofWithPis := ReadProofWithPublicInputs("../testdata/" + testCase + "/proof_with_public_inputs.json")
+	verifierOnlyCircuitData := ReadVerifierOnlyCircuitData("../testdata/" + testCase + "/verifier_only_circuit_data.json")
+
+	parsedProof, err := ParseProofWithPublicInputsBinary(bytes.NewReader(proofBytes), &commonCircuitData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsedProof, proofWithPis) {
+		t.Error("the plonky2 binary proof does not match the JSON proof")
+	}
+	if !bytes.Equal(proofWithPublicInputsToBytes(t, &proofWithPis), proofBytes) {
+		t.Error("binaryWriter doesn't serialize the proof the way plonky2 does")
+	}
+
+	parsedVerifierData, err := ParseVerifierOnlyCircuitDataBinary(bytes.NewReader(verifierDataBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsedVerifierData, verifierOnlyCircuitData) {
+		t.Error("the plonky2 binary verifier only circuit data does not match the JSON data")
+	}
+}
+
+func TestParseBinaryErrors(t *testing.T) {
+	commonCircuitData := ReadCommonCircuitData("../testdata/decode_block/common_circuit_data.json")
+	proofWithPis := ReadProofWithPublicInputs("../testdata/decode_block/proof_with_public_inputs.json")
+	proofBytes := proofWithPublicInputsToBytes(t, &proofWithPis)
+
+	// Truncated within the openings, after the three caps and the constants.
+	capsLen := 3 * bn254HashSize << commonCircuitData.FriParams.Config.CapHeight
+	truncatedLen := capsLen + 16*int(commonCircuitData.NumConstants) + 20
+	_, err := ParseProofWithPublicInputsBinary(bytes.NewReader(proofBytes[:truncatedLen]), &commonCircuitData)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected an unexpected EOF ParseError, got %v", err)
+	}
+	if parseErr.Path != "proof.openings.plonk_sigmas" {
+		t.Errorf("expected path proof.openings.plonk_sigmas, got %s", parseErr.Path)
+	}
+
+	_, err = ParseProofWithPublicInputsBinary(bytes.NewReader(append(proofBytes, 0)), &commonCircuitData)
+	if !errors.As(err, &parseErr) {
+		t.Errorf("expected trailing bytes to be rejected, got %v", err)
+	}
+
+	// A corrupted public inputs length must not be trusted.
+	corrupted := append([]byte{}, proofBytes...)
+	binary.LittleEndian.PutUint64(corrupted[len(corrupted)-8*len(proofWithPis.PublicInputs)-8:], 1<<60)
+	_, err = ParseProofWithPublicInputsBinary(bytes.NewReader(corrupted), &commonCircuitData)
+	if !errors.As(err, &parseErr) || parseErr.Path != "public_inputs" {
+		t.Errorf("expected a ParseError at public_inputs, got %v", err)
+	}
+}
+
+func TestParseBinaryHiding(t *testing.T) {
+	commonCircuitData := ReadCommonCircuitData("../testdata/decode_block/common_circuit_data.json")
+	commonCircuitData.Config.ZeroKnowledge = true
+	commonCircuitData.FriParams.Hiding = true
+
+	// Salt the leaves of the blinded oracles, as a hiding proof would.
+	proofWithPis := ReadProofWithPublicInputs("../testdata/decode_block/proof_with_public_inputs.json")
+	leafLens := commonCircuitData.OracleLeafLens()
+	for i := range proofWithPis.Proof.OpeningProof.QueryRoundProofs {
+		evalsProofs := proofWithPis.Proof.OpeningProof.QueryRoundProofs[i].InitialTreesProof.EvalsProofs
+		for j := range evalsProofs {
+			for uint64(len(evalsProofs[j].LeafElements)) < leafLens[j] {
+				evalsProofs[j].LeafElements = append(evalsProofs[j].LeafElements, uint64(i+j))
+			}
+		}
+	}
+	for _, violation := range ValidateProofWithPublicInputs(&proofWithPis, &commonCircuitData) {
+		t.Error(violation)
+	}
+
+	proofBytes := proofWithPublicInputsToBytes(t, &proofWithPis)
+	parsedProof, err := ParseProofWithPublicInputsBinary(bytes.NewReader(proofBytes), &commonCircuitData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsedProof, proofWithPis) {
+		t.Error("the binary proof does not match the salted proof")
+	}
+}
@@ -2,45 +2,38 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 
 	"github.com/succinctlabs/gnark-plonky2-verifier/plonk/gates"
 )
 
+type FriConfigRaw struct {
+	RateBits          uint64               `json:"rate_bits"`
+	CapHeight         uint64               `json:"cap_height"`
+	ProofOfWorkBits   uint64               `json:"proof_of_work_bits"`
+	ReductionStrategy FriReductionStrategy `json:"reduction_strategy"`
+	NumQueryRounds    uint64               `json:"num_query_rounds"`
+}
+
 type CommonCircuitDataRaw struct {
 	Config struct {
-		NumWires                uint64 `json:"num_wires"`
-		NumRoutedWires          uint64 `json:"num_routed_wires"`
-		NumConstants            uint64 `json:"num_constants"`
-		UseBaseArithmeticGate   bool   `json:"use_base_arithmetic_gate"`
-		SecurityBits            uint64 `json:"security_bits"`
-		NumChallenges           uint64 `json:"num_challenges"`
-		ZeroKnowledge           bool   `json:"zero_knowledge"`
-		MaxQuotientDegreeFactor uint64 `json:"max_quotient_degree_factor"`
-		FriConfig               struct {
-			RateBits          uint64 `json:"rate_bits"`
-			CapHeight         uint64 `json:"cap_height"`
-			ProofOfWorkBits   uint64 `json:"proof_of_work_bits"`
-			ReductionStrategy struct {
-				ConstantArityBits []uint64 `json:"ConstantArityBits"`
-			} `json:"reduction_strategy"`
-			NumQueryRounds uint64 `json:"num_query_rounds"`
-		} `json:"fri_config"`
+		NumWires                uint64       `json:"num_wires"`
+		NumRoutedWires          uint64       `json:"num_routed_wires"`
+		NumConstants            uint64       `json:"num_constants"`
+		UseBaseArithmeticGate   bool         `json:"use_base_arithmetic_gate"`
+		SecurityBits            uint64       `json:"security_bits"`
+		NumChallenges           uint64       `json:"num_challenges"`
+		ZeroKnowledge           bool         `json:"zero_knowledge"`
+		MaxQuotientDegreeFactor uint64       `json:"max_quotient_degree_factor"`
+		FriConfig               FriConfigRaw `json:"fri_config"`
 	} `json:"config"`
 	FriParams struct {
-		Config struct {
-			RateBits          uint64 `json:"rate_bits"`
-			CapHeight         uint64 `json:"cap_height"`
-			ProofOfWorkBits   uint64 `json:"proof_of_work_bits"`
-			ReductionStrategy struct {
-				ConstantArityBits []uint64 `json:"ConstantArityBits"`
-			} `json:"reduction_strategy"`
-			NumQueryRounds uint64 `json:"num_query_rounds"`
-		} `json:"config"`
-		Hiding             bool     `json:"hiding"`
-		DegreeBits         uint64   `json:"degree_bits"`
-		ReductionArityBits []uint64 `json:"reduction_arity_bits"`
+		Config             FriConfigRaw `json:"config"`
+		Hiding             bool         `json:"hiding"`
+		DegreeBits         uint64       `json:"degree_bits"`
+		ReductionArityBits []uint64     `json:"reduction_arity_bits"`
 	} `json:"fri_params"`
 	Gates         []string `json:"gates"`
 	SelectorsInfo struct {
@@ -56,24 +49,132 @@ type CommonCircuitDataRaw struct {
 	NumPublicInputs      uint64   `json:"num_public_inputs"`
 	KIs                  []uint64 `json:"k_is"`
 	NumPartialProducts   uint64   `json:"num_partial_products"`
+	// Common data serialized before plonky2 supported lookups doesn't have the lookup fields, so
+	// they're pointers to tell a missing field apart from a zero one.
+	NumLookupPolys     *uint64        `json:"num_lookup_polys,omitempty"`
+	NumLookupSelectors *uint64        `json:"num_lookup_selectors,omitempty"`
+	Luts               *[]LookupTable `json:"luts,omitempty"`
+}
+
+func (s FriReductionStrategy) MarshalJSON() ([]byte, error) {
+	// serde serializes enums as an object keyed by the variant name.
+	switch s.Kind {
+	case FriReductionStrategyFixed:
+		params := s.Params
+		if params == nil {
+			params = []uint64{}
+		}
+		return json.Marshal(map[string][]uint64{"Fixed": params})
+	case FriReductionStrategyConstantArityBits:
+		if len(s.Params) != 2 {
+			return nil, fmt.Errorf("ConstantArityBits takes 2 parameters, got %d", len(s.Params))
+		}
+		return json.Marshal(map[string][]uint64{"ConstantArityBits": s.Params})
+	case FriReductionStrategyMinSize:
+		switch len(s.Params) {
+		case 0:
+			return json.Marshal(map[string]*uint64{"MinSize": nil})
+		case 1:
+			return json.Marshal(map[string]uint64{"MinSize": s.Params[0]})
+		default:
+			return nil, fmt.Errorf("MinSize takes at most 1 parameter, got %d", len(s.Params))
+		}
+	default:
+		return nil, fmt.Errorf("unknown reduction strategy %d", s.Kind)
+	}
 }
 
+func (s *FriReductionStrategy) UnmarshalJSON(data []byte) error {
+	var variant map[string]json.RawMessage
+	if err := json.Unmarshal(data, &variant); err != nil {
+		return err
+	}
+	if len(variant) != 1 {
+		return fmt.Errorf("unknown reduction strategy %s", data)
+	}
+
+	for name, params := range variant {
+		switch name {
+		case "Fixed":
+			var arityBits []uint64
+			if err := json.Unmarshal(params, &arityBits); err != nil {
+				return err
+			}
+			*s = FriReductionStrategy{Kind: FriReductionStrategyFixed, Params: arityBits}
+		case "ConstantArityBits":
+			var arityBits [2]uint64
+			if err := json.Unmarshal(params, &arityBits); err != nil {
+				return err
+			}
+			*s = FriReductionStrategy{Kind: FriReductionStrategyConstantArityBits, Params: arityBits[:]}
+		case "MinSize":
+			// The max arity bits are optional, and serialized as null for MinSize(None).
+			var maxArityBits *uint64
+			if err := json.Unmarshal(params, &maxArityBits); err != nil {
+				return err
+			}
+			*s = FriReductionStrategy{Kind: FriReductionStrategyMinSize}
+			if maxArityBits != nil {
+				s.Params = []uint64{*maxArityBits}
+			}
+		default:
+			return fmt.Errorf("unknown reduction strategy %s", data)
+		}
+	}
+
+	return nil
+}
+
+func friConfigFromRaw(raw FriConfigRaw) FriConfig {
+	return FriConfig{
+		RateBits:          raw.RateBits,
+		CapHeight:         raw.CapHeight,
+		ProofOfWorkBits:   raw.ProofOfWorkBits,
+		NumQueryRounds:    raw.NumQueryRounds,
+		ReductionStrategy: raw.ReductionStrategy,
+	}
+}
+
+func friConfigToRaw(c FriConfig) FriConfigRaw {
+	return FriConfigRaw{
+		RateBits:          c.RateBits,
+		CapHeight:         c.CapHeight,
+		ProofOfWorkBits:   c.ProofOfWorkBits,
+		ReductionStrategy: c.ReductionStrategy,
+		NumQueryRounds:    c.NumQueryRounds,
+	}
+}
+
+// Reads a plonky2 CommonCircuitData serialized as JSON.  Panics if the file can't be read or
+// parsed, see ParseCommonCircuitData for a version that returns an error.
 func ReadCommonCircuitData(path string) CommonCircuitData {
 	jsonFile, err := os.Open(path)
 	if err != nil {
 		panic(err)
 	}
-
 	defer jsonFile.Close()
-	rawBytes, _ := io.ReadAll(jsonFile)
 
-	var raw CommonCircuitDataRaw
-	err = json.Unmarshal(rawBytes, &raw)
+	commonCircuitData, err := ParseCommonCircuitData(jsonFile)
 	if err != nil {
 		panic(err)
 	}
 
+	return commonCircuitData
+}
+
+func ParseCommonCircuitData(r io.Reader) (CommonCircuitData, error) {
 	var commonCircuitData CommonCircuitData
+
+	rawBytes, err := readAll(r)
+	if err != nil {
+		return commonCircuitData, err
+	}
+
+	var raw CommonCircuitDataRaw
+	if err := unmarshal(rawBytes, &raw); err != nil {
+		return commonCircuitData, err
+	}
+
 	commonCircuitData.Config.NumWires = raw.Config.NumWires
 	commonCircuitData.Config.NumRoutedWires = raw.Config.NumRoutedWires
 	commonCircuitData.Config.NumConstants = raw.Config.NumConstants
@@ -83,17 +184,12 @@ func ReadCommonCircuitData(path string) CommonCircuitData {
 	commonCircuitData.Config.ZeroKnowledge = raw.Config.ZeroKnowledge
 	commonCircuitData.Config.MaxQuotientDegreeFactor = raw.Config.MaxQuotientDegreeFactor
 
-	commonCircuitData.Config.FriConfig.RateBits = raw.Config.FriConfig.RateBits
-	commonCircuitData.Config.FriConfig.CapHeight = raw.Config.FriConfig.CapHeight
-	commonCircuitData.Config.FriConfig.ProofOfWorkBits = raw.Config.FriConfig.ProofOfWorkBits
-	commonCircuitData.Config.FriConfig.NumQueryRounds = raw.Config.FriConfig.NumQueryRounds
+	commonCircuitData.Config.FriConfig = friConfigFromRaw(raw.Config.FriConfig)
 
 	commonCircuitData.FriParams.DegreeBits = raw.FriParams.DegreeBits
 	commonCircuitData.DegreeBits = raw.FriParams.DegreeBits
-	commonCircuitData.FriParams.Config.RateBits = raw.FriParams.Config.RateBits
-	commonCircuitData.FriParams.Config.CapHeight = raw.FriParams.Config.CapHeight
-	commonCircuitData.FriParams.Config.ProofOfWorkBits = raw.FriParams.Config.ProofOfWorkBits
-	commonCircuitData.FriParams.Config.NumQueryRounds = raw.FriParams.Config.NumQueryRounds
+	commonCircuitData.FriParams.Config = friConfigFromRaw(raw.FriParams.Config)
+	commonCircuitData.FriParams.Hiding = raw.FriParams.Hiding
 	commonCircuitData.FriParams.ReductionArityBits = raw.FriParams.ReductionArityBits
 
 	commonCircuitData.GateIds = raw.Gates
@@ -117,11 +213,69 @@ func ReadCommonCircuitData(path string) CommonCircuitData {
 	commonCircuitData.NumPublicInputs = raw.NumPublicInputs
 	commonCircuitData.KIs = raw.KIs
 	commonCircuitData.NumPartialProducts = raw.NumPartialProducts
+	// plonky2 serializes all of the lookup fields or, before it supported lookups, none of them.
+	hasLookupFields := raw.NumLookupPolys != nil
+	if (raw.NumLookupSelectors != nil) != hasLookupFields || (raw.Luts != nil) != hasLookupFields {
+		return commonCircuitData, &ParseError{
+			Path: "luts",
+			Err:  fmt.Errorf("num_lookup_polys, num_lookup_selectors and luts must all be present or all be missing"),
+		}
+	}
+	if hasLookupFields {
+		commonCircuitData.HasLookupFields = true
+		commonCircuitData.NumLookupPolys = *raw.NumLookupPolys
+		commonCircuitData.NumLookupSelectors = *raw.NumLookupSelectors
+		commonCircuitData.Luts = *raw.Luts
+	}
+
+	return commonCircuitData, nil
+}
+
+// The inverse of the conversion in ParseCommonCircuitData.
+func commonCircuitDataToRaw(c *CommonCircuitData) CommonCircuitDataRaw {
+	var raw CommonCircuitDataRaw
+	raw.Config.NumWires = c.Config.NumWires
+	raw.Config.NumRoutedWires = c.Config.NumRoutedWires
+	raw.Config.NumConstants = c.Config.NumConstants
+	raw.Config.UseBaseArithmeticGate = c.Config.UseBaseArithmeticGate
+	raw.Config.SecurityBits = c.Config.SecurityBits
+	raw.Config.NumChallenges = c.Config.NumChallenges
+	raw.Config.ZeroKnowledge = c.Config.ZeroKnowledge
+	raw.Config.MaxQuotientDegreeFactor = c.Config.MaxQuotientDegreeFactor
+	raw.Config.FriConfig = friConfigToRaw(c.Config.FriConfig)
 
-	// Don't support circuits that have hiding enabled
-	if raw.FriParams.Hiding {
-		panic("Circuit has hiding enabled, which is not supported")
+	raw.FriParams.Config = friConfigToRaw(c.FriParams.Config)
+	raw.FriParams.Hiding = c.FriParams.Hiding
+	raw.FriParams.DegreeBits = c.FriParams.DegreeBits
+	raw.FriParams.ReductionArityBits = c.FriParams.ReductionArityBits
+
+	raw.Gates = c.GateIds
+
+	raw.SelectorsInfo.SelectorIndices = c.SelectorsInfo.SelectorIndices()
+	raw.SelectorsInfo.Groups = make([]struct {
+		Start uint64 `json:"start"`
+		End   uint64 `json:"end"`
+	}, len(c.SelectorsInfo.Groups()))
+	for i, group := range c.SelectorsInfo.Groups() {
+		raw.SelectorsInfo.Groups[i].Start = group.Start()
+		raw.SelectorsInfo.Groups[i].End = group.End()
 	}
 
-	return commonCircuitData
+	raw.QuotientDegreeFactor = c.QuotientDegreeFactor
+	raw.NumGateConstraints = c.NumGateConstraints
+	raw.NumConstants = c.NumConstants
+	raw.NumPublicInputs = c.NumPublicInputs
+	raw.KIs = c.KIs
+	raw.NumPartialProducts = c.NumPartialProducts
+	if c.HasLookupFields || c.NumLookupPolys != 0 || c.NumLookupSelectors != 0 || len(c.Luts) != 0 {
+		numLookupPolys, numLookupSelectors, luts := c.NumLookupPolys, c.NumLookupSelectors, c.Luts
+		if luts == nil {
+			luts = []LookupTable{}
+		}
+		raw.NumLookupPolys = &numLookupPolys
+		raw.NumLookupSelectors = &numLookupSelectors
+		raw.Luts = &luts
+	}
+
+	return raw
 }
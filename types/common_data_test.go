@@ -1,9 +1,104 @@
 package types
 
 import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/succinctlabs/gnark-plonky2-verifier/plonk/gates"
 )
 
 func TestReadCommonCircuitData(t *testing.T) {
 	ReadCommonCircuitData("../testdata/decode_block/common_circuit_data.json")
 }
+
+func TestParseCommonCircuitData(t *testing.T) {
+	const commonDataPath = "../testdata/decode_block/common_circuit_data.json"
+
+	jsonFile, err := os.Open(commonDataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer jsonFile.Close()
+
+	commonCircuitData, err := ParseCommonCircuitData(jsonFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commonCircuitData.GateIds) == 0 {
+		t.Error("no gates were read")
+	}
+
+	value := readJSONValue(t, commonDataPath)
+	setJSONValue(t, value, "fri_params.reduction_arity_bits.1", "4")
+	_, err = ParseCommonCircuitData(strings.NewReader(marshalJSONValue(t, value)))
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Path != "fri_params.reduction_arity_bits.1" {
+		t.Errorf("expected a ParseError at fri_params.reduction_arity_bits.1, got %v", err)
+	}
+}
+
+func TestParseHidingCommonCircuitData(t *testing.T) {
+	value := readJSONValue(t, "../testdata/decode_block/common_circuit_data.json")
+	setJSONValue(t, value, "config.zero_knowledge", true)
+	setJSONValue(t, value, "fri_params.hiding", true)
+
+	commonCircuitData, err := ParseCommonCircuitData(strings.NewReader(marshalJSONValue(t, value)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !commonCircuitData.FriParams.Hiding {
+		t.Error("expected hiding to be enabled")
+	}
+	for _, violation := range commonCircuitData.Validate() {
+		t.Error(violation)
+	}
+
+	// Every oracle but the constants/sigmas is salted.
+	numPolys := commonCircuitData.OracleNumPolys()
+	expectedLeafLens := []uint64{numPolys[0], numPolys[1] + SALT_SIZE, numPolys[2] + SALT_SIZE, numPolys[3] + SALT_SIZE}
+	if leafLens := commonCircuitData.OracleLeafLens(); !reflect.DeepEqual(leafLens, expectedLeafLens) {
+		t.Errorf("expected leaf lengths %v, got %v", expectedLeafLens, leafLens)
+	}
+
+	commonCircuitData.Config.ZeroKnowledge = false
+	violations := commonCircuitData.Validate()
+	if len(violations) != 1 || violations[0].Path != "fri_params.hiding" {
+		t.Errorf("expected a violation at fri_params.hiding, got %v", violations)
+	}
+}
+
+func TestParseLookupCommonCircuitData(t *testing.T) {
+	value := readJSONValue(t, "../testdata/decode_block/common_circuit_data.json")
+	// A single 3 entry table.  The lookup selectors come before the gate constants.
+	setJSONValue(t, value, "num_constants", 5+gates.LOOKUP_SELECTOR_START_END+1)
+	setJSONValue(t, value, "num_lookup_polys", 7)
+	setJSONValue(t, value, "num_lookup_selectors", gates.LOOKUP_SELECTOR_START_END+1)
+	setJSONValue(t, value, "luts", [][][2]uint16{{{0, 1}, {1, 2}, {2, 4}}})
+
+	commonCircuitData, err := ParseCommonCircuitData(strings.NewReader(marshalJSONValue(t, value)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(commonCircuitData.Luts, []LookupTable{{{0, 1}, {1, 2}, {2, 4}}}) {
+		t.Errorf("unexpected lookup tables %v", commonCircuitData.Luts)
+	}
+	for _, violation := range commonCircuitData.Validate() {
+		t.Error(violation)
+	}
+
+	// The lookup polynomials of both challenges are committed to along with the zs and partial products.
+	numChallenges := commonCircuitData.Config.NumChallenges
+	expectedNumPolys := numChallenges*(1+commonCircuitData.NumPartialProducts) + numChallenges*7
+	if numPolys := commonCircuitData.OracleNumPolys()[2]; numPolys != expectedNumPolys {
+		t.Errorf("expected %d zs/partial products/lookup polynomials, got %d", expectedNumPolys, numPolys)
+	}
+
+	commonCircuitData.NumLookupSelectors++
+	violations := commonCircuitData.Validate()
+	if len(violations) != 2 || violations[0].Path != "num_lookup_selectors" || violations[1].Path != "num_constants" {
+		t.Errorf("expected violations at num_lookup_selectors and num_constants, got %v", violations)
+	}
+}
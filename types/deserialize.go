@@ -19,21 +19,16 @@ type ProofWithPublicInputsRaw struct {
 			PlonkZsNext     [][]uint64 `json:"plonk_zs_next"`
 			PartialProducts [][]uint64 `json:"partial_products"`
 			QuotientPolys   [][]uint64 `json:"quotient_polys"`
+			// nil when the proof was serialized before plonky2 supported lookups, which leaves the
+			// fields out, rather than with empty arrays like it does since, so that both are
+			// written back the way they were read.
+			LookupZs     *[][]uint64 `json:"lookup_zs,omitempty"`
+			LookupZsNext *[][]uint64 `json:"lookup_zs_next,omitempty"`
 		} `json:"openings"`
 		OpeningProof struct {
-			CommitPhaseMerkleCaps [][]string `json:"commit_phase_merkle_caps"`
-			QueryRoundProofs      []struct {
-				InitialTreesProof struct {
-					EvalsProofs []EvalProofRaw `json:"evals_proofs"`
-				} `json:"initial_trees_proof"`
-				Steps []struct {
-					Evals       [][]uint64 `json:"evals"`
-					MerkleProof struct {
-						Siblings []string `json:"siblings"`
-					} `json:"merkle_proof"`
-				} `json:"steps"`
-			} `json:"query_round_proofs"`
-			FinalPoly struct {
+			CommitPhaseMerkleCaps [][]string         `json:"commit_phase_merkle_caps"`
+			QueryRoundProofs      []FriQueryRoundRaw `json:"query_round_proofs"`
+			FinalPoly             struct {
 				Coeffs [][]uint64 `json:"coeffs"`
 			} `json:"final_poly"`
 			PowWitness uint64 `json:"pow_witness"`
@@ -42,46 +37,64 @@ type ProofWithPublicInputsRaw struct {
 	PublicInputs []uint64 `json:"public_inputs"`
 }
 
-type EvalProofRaw struct {
-	LeafElements []uint64
-	MerkleProof  MerkleProofRaw
+// Returns the values of a lookup opening of ProofWithPublicInputsRaw, which are empty when it's
+// left out.
+func LookupOpeningValues(values *[][]uint64) [][]uint64 {
+	if values == nil {
+		return nil
+	}
+	return *values
 }
 
-func (e *EvalProofRaw) UnmarshalJSON(data []byte) error {
-	return json.Unmarshal(data, &[]interface{}{&e.LeafElements, &e.MerkleProof})
+type FriQueryRoundRaw struct {
+	InitialTreesProof struct {
+		EvalsProofs []EvalProofRaw `json:"evals_proofs"`
+	} `json:"initial_trees_proof"`
+	Steps []FriQueryStepRaw `json:"steps"`
 }
 
-type MerkleProofRaw struct {
-	Hash []string
+type FriQueryStepRaw struct {
+	Evals       [][]uint64 `json:"evals"`
+	MerkleProof struct {
+		Siblings []string `json:"siblings"`
+	} `json:"merkle_proof"`
 }
 
-func (m *MerkleProofRaw) UnmarshalJSON(data []byte) error {
-	type SiblingObject struct {
-		Siblings []string // "siblings"
-	}
+type EvalProofRaw struct {
+	LeafElements []uint64
+	MerkleProof  MerkleProofRaw
+}
 
-	var siblings SiblingObject
-	if err := json.Unmarshal(data, &siblings); err != nil {
-		panic(err)
+func (e *EvalProofRaw) UnmarshalJSON(data []byte) error {
+	// The evals proof is serialized as a (leaf elements, merkle proof) tuple.
+	if err := json.Unmarshal(data, &[]interface{}{&e.LeafElements, &e.MerkleProof}); err != nil {
+		return &evalProofError{err: err}
 	}
+	return nil
+}
 
-	m.Hash = make([]string, len(siblings.Siblings))
-	copy(m.Hash[:], siblings.Siblings)
+func (e EvalProofRaw) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{e.LeafElements, e.MerkleProof})
+}
 
-	return nil
+type MerkleProofRaw struct {
+	Hash []string `json:"siblings"`
 }
 
 type ProofChallengesRaw struct {
-	PlonkBetas    []uint64 `json:"plonk_betas"`
-	PlonkGammas   []uint64 `json:"plonk_gammas"`
-	PlonkAlphas   []uint64 `json:"plonk_alphas"`
-	PlonkZeta     []uint64 `json:"plonk_zeta"`
-	FriChallenges struct {
-		FriAlpha        []uint64   `json:"fri_alpha"`
-		FriBetas        [][]uint64 `json:"fri_betas"`
-		FriPowResponse  uint64     `json:"fri_pow_response"`
-		FriQueryIndices []uint64   `json:"fri_query_indices"`
-	} `json:"fri_challenges"`
+	PlonkBetas    []uint64         `json:"plonk_betas"`
+	PlonkGammas   []uint64         `json:"plonk_gammas"`
+	PlonkAlphas   []uint64         `json:"plonk_alphas"`
+	PlonkDeltas   []uint64         `json:"plonk_deltas,omitempty"`
+	PlonkZeta     []uint64         `json:"plonk_zeta"`
+	FriChallenges FriChallengesRaw `json:"fri_challenges"`
+}
+
+type FriChallengesRaw struct {
+	FriAlpha        []uint64   `json:"fri_alpha"`
+	FriBetas        [][]uint64 `json:"fri_betas"`
+	FriPowResponse  uint64     `json:"fri_pow_response"`
+	FriQueryIndices []uint64   `json:"fri_query_indices"`
 }
 
 type VerifierOnlyCircuitDataRaw struct {
@@ -89,17 +102,16 @@ type VerifierOnlyCircuitDataRaw struct {
 	CircuitDigest      string   `json:"circuit_digest"`
 }
 
+// Reads a plonky2 ProofWithPublicInputs serialized as JSON.  Panics if the file can't be read or
+// parsed, see ParseProofWithPublicInputs for a version that returns an error.
 func ReadProofWithPublicInputs(path string) ProofWithPublicInputsRaw {
 	jsonFile, err := os.Open(path)
 	if err != nil {
 		panic(err)
 	}
-
 	defer jsonFile.Close()
-	rawBytes, _ := io.ReadAll(jsonFile)
 
-	var raw ProofWithPublicInputsRaw
-	err = json.Unmarshal(rawBytes, &raw)
+	raw, err := ParseProofWithPublicInputs(jsonFile)
 	if err != nil {
 		panic(err)
 	}
@@ -107,20 +119,43 @@ func ReadProofWithPublicInputs(path string) ProofWithPublicInputsRaw {
 	return raw
 }
 
+func ParseProofWithPublicInputs(r io.Reader) (ProofWithPublicInputsRaw, error) {
+	var raw ProofWithPublicInputsRaw
+
+	rawBytes, err := readAll(r)
+	if err != nil {
+		return raw, err
+	}
+
+	err = unmarshal(rawBytes, &raw)
+	return raw, err
+}
+
+// Reads a plonky2 VerifierOnlyCircuitData serialized as JSON.  Panics if the file can't be read or
+// parsed, see ParseVerifierOnlyCircuitData for a version that returns an error.
 func ReadVerifierOnlyCircuitData(path string) VerifierOnlyCircuitDataRaw {
 	jsonFile, err := os.Open(path)
 	if err != nil {
 		panic(err)
 	}
-
 	defer jsonFile.Close()
-	rawBytes, _ := io.ReadAll(jsonFile)
 
-	var raw VerifierOnlyCircuitDataRaw
-	err = json.Unmarshal(rawBytes, &raw)
+	raw, err := ParseVerifierOnlyCircuitData(jsonFile)
 	if err != nil {
 		panic(err)
 	}
 
 	return raw
 }
+
+func ParseVerifierOnlyCircuitData(r io.Reader) (VerifierOnlyCircuitDataRaw, error) {
+	var raw VerifierOnlyCircuitDataRaw
+
+	rawBytes, err := readAll(r)
+	if err != nil {
+		return raw, err
+	}
+
+	err = unmarshal(rawBytes, &raw)
+	return raw, err
+}
@@ -1,6 +1,10 @@
 package types
 
 import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -11,3 +15,141 @@ func TestReadProofWithPublicInputs(t *testing.T) {
 func TestReadVerifierOnlyCircuitData(t *testing.T) {
 	ReadVerifierOnlyCircuitData("../testdata/decode_block/verifier_only_circuit_data.json")
 }
+
+// Reads a JSON test file into a generic value, so that tests can corrupt it.
+func readJSONValue(t *testing.T, path string) interface{} {
+	rawBytes, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(rawBytes, &value); err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+// Replaces the value at the dotted path within a generic JSON value.
+func setJSONValue(t *testing.T, value interface{}, path string, newValue interface{}) {
+	elements := strings.Split(path, ".")
+	for i, element := range elements {
+		last := i == len(elements)-1
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if last {
+				v[element] = newValue
+				return
+			}
+			value = v[element]
+		case []interface{}:
+			var index int
+			if err := json.Unmarshal([]byte(element), &index); err != nil {
+				t.Fatalf("bad index %s in %s", element, path)
+			}
+			if last {
+				v[index] = newValue
+				return
+			}
+			value = v[index]
+		default:
+			t.Fatalf("%s is not a path within the JSON value", path)
+		}
+	}
+}
+
+func marshalJSONValue(t *testing.T, value interface{}) string {
+	rawBytes, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(rawBytes)
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestParseProofWithPublicInputs(t *testing.T) {
+	for _, testCase := range []string{"decode_block", "step"} {
+		jsonFile, err := os.Open("../testdata/" + testCase + "/proof_with_public_inputs.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer jsonFile.Close()
+
+		raw, err := ParseProofWithPublicInputs(jsonFile)
+		if err != nil {
+			t.Fatalf("%s: %v", testCase, err)
+		}
+		if len(raw.Proof.OpeningProof.QueryRoundProofs) == 0 {
+			t.Errorf("%s: no query round proofs were read", testCase)
+		}
+	}
+}
+
+func TestParseProofWithPublicInputsErrors(t *testing.T) {
+	const proofPath = "../testdata/step/proof_with_public_inputs.json"
+
+	testCases := []struct {
+		name  string
+		path  string
+		value interface{}
+	}{
+		{"public input", "public_inputs.2", "not a number"},
+		{"opening", "proof.openings.wires.3.1", -1},
+		{"cap", "proof.wires_cap.0", 7},
+		{"leaf element", "proof.opening_proof.query_round_proofs.4.initial_trees_proof.evals_proofs.1.0.3", true},
+		{"sibling", "proof.opening_proof.query_round_proofs.2.initial_trees_proof.evals_proofs.0.1.siblings.5", 1.5},
+		{"evals proof", "proof.opening_proof.query_round_proofs.1.initial_trees_proof.evals_proofs.2", "tuple"},
+	}
+
+	for _, testCase := range testCases {
+		value := readJSONValue(t, proofPath)
+		setJSONValue(t, value, testCase.path, testCase.value)
+
+		_, err := ParseProofWithPublicInputs(strings.NewReader(marshalJSONValue(t, value)))
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s: expected a ParseError, got %v", testCase.name, err)
+			continue
+		}
+		if parseErr.Path != testCase.path {
+			t.Errorf("%s: expected path %s, got %s", testCase.name, testCase.path, parseErr.Path)
+		}
+	}
+}
+
+func TestParseTruncatedJSON(t *testing.T) {
+	rawBytes, err := os.ReadFile("../testdata/decode_block/proof_with_public_inputs.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ParseProofWithPublicInputs(strings.NewReader(string(rawBytes[:len(rawBytes)/2])))
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected a ParseError, got %v", err)
+	}
+
+	_, err = ParseVerifierOnlyCircuitData(strings.NewReader(`{"constants_sigmas_cap": ["1", "2"`))
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected a ParseError, got %v", err)
+	}
+	if parseErr.Path != "constants_sigmas_cap.1" {
+		t.Errorf("expected path constants_sigmas_cap.1, got %s", parseErr.Path)
+	}
+}
+
+func TestParseReaderError(t *testing.T) {
+	_, err := ParseVerifierOnlyCircuitData(failingReader{})
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected a ParseError, got %v", err)
+	}
+	if parseErr.Path != "" {
+		t.Errorf("expected an empty path, got %s", parseErr.Path)
+	}
+}
@@ -0,0 +1,179 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// The error returned by the Parse functions.  Path is the JSON path of the value that could not be
+// read (e.g. "proof.openings.wires.3.1"), and is empty when the error is not about a specific
+// value, such as an I/O error.
+type ParseError struct {
+	Path string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func readAll(r io.Reader) ([]byte, error) {
+	rawBytes, err := io.ReadAll(r)
+	if err != nil {
+		return nil, &ParseError{Err: err}
+	}
+	return rawBytes, nil
+}
+
+// Unmarshals data into v.  If that fails, the returned ParseError holds the path of the offending
+// value.
+func unmarshal(data []byte, v interface{}) error {
+	err := json.Unmarshal(data, v)
+	if err == nil {
+		return nil
+	}
+
+	var evalProofErr *evalProofError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &evalProofErr):
+		return locateEvalProofError(data, evalProofErr)
+	case errors.As(err, &syntaxErr):
+		return &ParseError{Path: jsonPathAtOffset(data, syntaxErr.Offset), Err: err}
+	case errors.As(err, &typeErr):
+		return &ParseError{Path: jsonPathAtOffset(data, typeErr.Offset), Err: err}
+	default:
+		return &ParseError{Err: err}
+	}
+}
+
+type jsonPathFrame struct {
+	isObject bool
+	key      string
+	hasKey   bool
+	index    int
+}
+
+func jsonPath(stack []*jsonPathFrame) string {
+	elements := make([]string, 0, len(stack))
+	for _, frame := range stack {
+		if frame.isObject {
+			if !frame.hasKey {
+				break
+			}
+			elements = append(elements, frame.key)
+		} else {
+			elements = append(elements, strconv.Itoa(frame.index))
+		}
+	}
+	return strings.Join(elements, ".")
+}
+
+// Returns the path of the value that ends at, or spans, the given byte offset within data.  The
+// encoding/json errors report the offset just after the value that could not be decoded.
+func jsonPathAtOffset(data []byte, offset int64) string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	stack := []*jsonPathFrame{}
+
+	// Marks the value at the top of the stack as read.
+	finishValue := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		if top.isObject {
+			top.hasKey = false
+		} else {
+			top.index++
+		}
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return jsonPath(stack)
+		}
+
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if key, ok := token.(string); ok && top.isObject && !top.hasKey {
+				top.key = key
+				top.hasKey = true
+				continue
+			}
+		}
+
+		switch token {
+		case json.Delim('{'):
+			stack = append(stack, &jsonPathFrame{isObject: true})
+			continue
+		case json.Delim('['):
+			stack = append(stack, &jsonPathFrame{})
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+		}
+
+		if decoder.InputOffset() >= offset {
+			return jsonPath(stack)
+		}
+		finishValue()
+	}
+}
+
+// Returned by EvalProofRaw.UnmarshalJSON.  encoding/json doesn't add any context to the errors of
+// custom unmarshalers, so the offset of the inner error is relative to the evals proof.
+type evalProofError struct {
+	err error
+}
+
+func (e *evalProofError) Error() string {
+	return e.err.Error()
+}
+
+// Finds the evals proof that could not be decoded, to give the full path of its bad value.
+func locateEvalProofError(data []byte, evalProofErr *evalProofError) error {
+	var proofWithPis struct {
+		Proof struct {
+			OpeningProof struct {
+				QueryRoundProofs []struct {
+					InitialTreesProof struct {
+						EvalsProofs []json.RawMessage `json:"evals_proofs"`
+					} `json:"initial_trees_proof"`
+				} `json:"query_round_proofs"`
+			} `json:"opening_proof"`
+		} `json:"proof"`
+	}
+
+	if json.Unmarshal(data, &proofWithPis) == nil {
+		for i, queryRound := range proofWithPis.Proof.OpeningProof.QueryRoundProofs {
+			for j, evalsProof := range queryRound.InitialTreesProof.EvalsProofs {
+				var evalProof EvalProofRaw
+				err := unmarshal(evalsProof, &[]interface{}{&evalProof.LeafElements, &evalProof.MerkleProof})
+				var parseErr *ParseError
+				if errors.As(err, &parseErr) {
+					path := fmt.Sprintf("proof.opening_proof.query_round_proofs.%d.initial_trees_proof.evals_proofs.%d", i, j)
+					if parseErr.Path != "" {
+						path += "." + parseErr.Path
+					}
+					return &ParseError{Path: path, Err: parseErr.Err}
+				}
+			}
+		}
+	}
+
+	return &ParseError{Err: evalProofErr.err}
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Writes v in the same layout as the plonky2 JSON fixtures, 4 space indented with a trailing
+// newline, so that unmodified data is written back byte for byte.
+func marshal(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	// The gate ids contain '<' and '>', which must not be escaped.
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(v)
+}
+
+// Writes the CommonCircuitData as plonky2 JSON, which ReadCommonCircuitData and
+// ParseCommonCircuitData read back.
+func WriteCommonCircuitData(w io.Writer, commonCircuitData *CommonCircuitData) error {
+	return marshal(w, commonCircuitDataToRaw(commonCircuitData))
+}
+
+func WriteProofWithPublicInputs(w io.Writer, proofWithPis *ProofWithPublicInputsRaw) error {
+	return marshal(w, proofWithPis)
+}
+
+func WriteVerifierOnlyCircuitData(w io.Writer, verifierOnlyCircuitData *VerifierOnlyCircuitDataRaw) error {
+	return marshal(w, verifierOnlyCircuitData)
+}
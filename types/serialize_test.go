@@ -0,0 +1,148 @@
+package types
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWriteRoundTrip(t *testing.T) {
+	for _, testCase := range []string{"decode_block", "step"} {
+		dir := "../testdata/" + testCase + "/"
+
+		expected, err := os.ReadFile(dir + "common_circuit_data.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		commonCircuitData := ReadCommonCircuitData(dir + "common_circuit_data.json")
+		var buf bytes.Buffer
+		if err := WriteCommonCircuitData(&buf, &commonCircuitData); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Errorf("%s: the written common circuit data does not match the fixture", testCase)
+		}
+
+		expected, err = os.ReadFile(dir + "proof_with_public_inputs.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		proofWithPis := ReadProofWithPublicInputs(dir + "proof_with_public_inputs.json")
+		buf.Reset()
+		if err := WriteProofWithPublicInputs(&buf, &proofWithPis); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Errorf("%s: the written proof does not match the fixture", testCase)
+		}
+
+		expected, err = os.ReadFile(dir + "verifier_only_circuit_data.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		verifierOnlyCircuitData := ReadVerifierOnlyCircuitData(dir + "verifier_only_circuit_data.json")
+		buf.Reset()
+		if err := WriteVerifierOnlyCircuitData(&buf, &verifierOnlyCircuitData); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Errorf("%s: the written verifier only circuit data does not match the fixture", testCase)
+		}
+	}
+}
+
+func TestWriteEmptyLookupsRoundTrip(t *testing.T) {
+	// The step common data with lookup fields that are present but all zero, which have to be
+	// written back rather than left out like those of the step common data itself.
+	const commonDataPath = "../testdata/step/common_circuit_data_empty_lookups.json"
+
+	expected, err := os.ReadFile(commonDataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commonCircuitData := ReadCommonCircuitData(commonDataPath)
+	if !commonCircuitData.HasLookupFields {
+		t.Error("expected the lookup fields to be recorded as present")
+	}
+	var buf bytes.Buffer
+	if err := WriteCommonCircuitData(&buf, &commonCircuitData); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("the written common circuit data does not match the fixture:\n%s", buf.String())
+	}
+
+	// Only some of the lookup fields can't be told apart from a corrupted file.
+	value := readJSONValue(t, commonDataPath)
+	delete(value.(map[string]interface{}), "luts")
+	_, err = ParseCommonCircuitData(strings.NewReader(marshalJSONValue(t, value)))
+	if err == nil || !strings.Contains(err.Error(), "all be present or all be missing") {
+		t.Errorf("expected an error for partial lookup fields, got %v", err)
+	}
+}
+
+func TestWriteEmptyLookupOpeningsRoundTrip(t *testing.T) {
+	fixture, err := os.ReadFile("../testdata/step/proof_with_public_inputs.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The step proof as plonky2 serializes it since it supports lookups, with empty lookup
+	// openings, which have to be written back rather than left out like those of the step proof.
+	openingsEnd := "\n        },\n        \"opening_proof\""
+	if !bytes.Contains(fixture, []byte(openingsEnd)) {
+		t.Fatal("can't find the end of the openings in the fixture")
+	}
+	expected := bytes.Replace(
+		fixture,
+		[]byte(openingsEnd),
+		[]byte(",\n            \"lookup_zs\": [],\n            \"lookup_zs_next\": []"+openingsEnd),
+		1,
+	)
+
+	proofWithPis, err := ParseProofWithPublicInputs(bytes.NewReader(expected))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proofWithPis.Proof.Openings.LookupZs == nil || proofWithPis.Proof.Openings.LookupZsNext == nil {
+		t.Error("expected the lookup openings to be recorded as present")
+	}
+	var buf bytes.Buffer
+	if err := WriteProofWithPublicInputs(&buf, &proofWithPis); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Error("the written proof does not match the fixture with empty lookup openings")
+	}
+}
+
+func TestFriReductionStrategyRoundTrip(t *testing.T) {
+	maxArityBits := uint64(4)
+	for _, strategy := range []string{
+		`{"Fixed":[3,2,1]}`,
+		`{"ConstantArityBits":[4,5]}`,
+		`{"MinSize":null}`,
+		`{"MinSize":4}`,
+	} {
+		var s FriReductionStrategy
+		if err := s.UnmarshalJSON([]byte(strategy)); err != nil {
+			t.Fatalf("%s: %v", strategy, err)
+		}
+		written, err := s.MarshalJSON()
+		if err != nil {
+			t.Fatalf("%s: %v", strategy, err)
+		}
+		if string(written) != strategy {
+			t.Errorf("expected %s, got %s", strategy, written)
+		}
+	}
+
+	var s FriReductionStrategy
+	if err := s.UnmarshalJSON([]byte(`{"MinSize":4}`)); err != nil || s.Params[0] != maxArityBits {
+		t.Errorf("expected MinSize(Some(4)), got %v, %v", s, err)
+	}
+	if err := s.UnmarshalJSON([]byte(`{"Unknown":1}`)); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Errorf("expected an unknown strategy to be rejected, got %v", err)
+	}
+}
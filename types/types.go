@@ -4,14 +4,30 @@ import (
 	"github.com/succinctlabs/gnark-plonky2-verifier/plonk/gates"
 )
 
+type FriReductionStrategyKind uint8
+
+const (
+	FriReductionStrategyFixed FriReductionStrategyKind = iota
+	FriReductionStrategyConstantArityBits
+	FriReductionStrategyMinSize
+)
+
+// plonky2's FriReductionStrategy enum.  Params holds the arity bits of each step for Fixed,
+// (arity_bits, final_poly_bits) for ConstantArityBits, and the optional max arity bits for MinSize.
+type FriReductionStrategy struct {
+	Kind   FriReductionStrategyKind
+	Params []uint64
+}
+
 type FriConfig struct {
 	RateBits        uint64
 	CapHeight       uint64
 	ProofOfWorkBits uint64
 	NumQueryRounds  uint64
-	// Note that we do not need `reduction_strategy` of type FriReductionStrategy as the plonky2 FriConfig has.
-	// reduction_strategy is only used for computing `reduction_arity_bits`, which is serialized in the
-	// CommonCircuitData.
+	// Note that the verifier does not need `reduction_strategy`, as it is only used for computing
+	// `reduction_arity_bits`, which is serialized in the CommonCircuitData.  It is kept so that the
+	// CommonCircuitData can be written back out.
+	ReductionStrategy FriReductionStrategy
 }
 
 func (fc *FriConfig) Rate() float64 {
@@ -25,6 +41,17 @@ type FriParams struct {
 	ReductionArityBits []uint64
 }
 
+// The number of random elements that plonky2 appends to each leaf of a blinded oracle when hiding
+// is enabled.
+const SALT_SIZE = 4
+
+func (p *FriParams) SaltSize(blinding bool) uint64 {
+	if blinding && p.Hiding {
+		return SALT_SIZE
+	}
+	return 0
+}
+
 func (p *FriParams) TotalArities() int {
 	res := 0
 	for _, b := range p.ReductionArityBits {
@@ -71,6 +98,9 @@ type CircuitConfig struct {
 	FriConfig               FriConfig
 }
 
+// A lookup table, as the (input, output) pairs of its entries.
+type LookupTable [][2]uint16
+
 type CommonCircuitData struct {
 	Config CircuitConfig
 	FriParams
@@ -83,4 +113,17 @@ type CommonCircuitData struct {
 	NumPublicInputs      uint64
 	KIs                  []uint64
 	NumPartialProducts   uint64
+	NumLookupPolys       uint64
+	NumLookupSelectors   uint64
+	Luts                 []LookupTable
+	// Whether the serialized common data had the lookup fields, even if they're all zero.  Common
+	// data serialized before plonky2 supported lookups doesn't, and WriteCommonCircuitData leaves
+	// them out again.
+	HasLookupFields bool
+}
+
+// The number of lookup polynomials over all of the num_challenges repetitions of the lookup
+// argument.  It's 0 for circuits without lookups.
+func (c *CommonCircuitData) NumAllLookupPolys() uint64 {
+	return c.Config.NumChallenges * c.NumLookupPolys
 }
@@ -0,0 +1,337 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/plonk/gates"
+)
+
+// A value of the deserialized common circuit data, proof or verifier only circuit data that can't
+// be used with the circuit.  Path uses the same dotted JSON path as ParseError.
+type Violation struct {
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Path, v.Message)
+}
+
+// The number of polynomials committed to within each of the constants/sigmas, wires,
+// zs/partial products/lookups and quotient oracles, i.e. the number of leaf elements of each of
+// the initial trees.
+func (c *CommonCircuitData) OracleNumPolys() []uint64 {
+	return []uint64{
+		c.NumConstants + c.Config.NumRoutedWires,
+		c.Config.NumWires,
+		c.Config.NumChallenges*(1+c.NumPartialProducts) + c.NumAllLookupPolys(),
+		c.Config.NumChallenges * c.QuotientDegreeFactor,
+	}
+}
+
+// The length of the leaves of each of the initial trees.  With hiding enabled, the leaves of every
+// oracle but the constants/sigmas are salted.
+func (c *CommonCircuitData) OracleLeafLens() []uint64 {
+	numPolys := c.OracleNumPolys()
+	blinding := []bool{false, true, true, true}
+
+	leafLens := make([]uint64, len(numPolys))
+	for i := range numPolys {
+		leafLens[i] = numPolys[i] + c.FriParams.SaltSize(blinding[i])
+	}
+	return leafLens
+}
+
+type validator struct {
+	violations []Violation
+}
+
+func (v *validator) addViolation(path string, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) checkLen(path string, actualLen int, expectedLen uint64) bool {
+	if uint64(actualLen) != expectedLen {
+		v.addViolation(path, "expected %d elements, got %d", expectedLen, actualLen)
+		return false
+	}
+	return true
+}
+
+func (v *validator) checkGoldilocks(path string, value uint64) {
+	if value >= gl.MODULUS.Uint64() {
+		v.addViolation(path, "%d is not a canonical goldilocks element", value)
+	}
+}
+
+func (v *validator) checkGoldilocksArray(path string, values []uint64, expectedLen uint64) {
+	v.checkLen(path, len(values), expectedLen)
+	for i, value := range values {
+		v.checkGoldilocks(path+"."+strconv.Itoa(i), value)
+	}
+}
+
+func (v *validator) checkQuadraticExtensionArray(path string, values [][]uint64, expectedLen uint64) {
+	v.checkLen(path, len(values), expectedLen)
+	for i, value := range values {
+		v.checkGoldilocksArray(path+"."+strconv.Itoa(i), value, gl.D)
+	}
+}
+
+func (v *validator) checkBN254(path string, value string) {
+	bigValue, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		v.addViolation(path, "%q is not a decimal integer", value)
+		return
+	}
+	if bigValue.Sign() < 0 || bigValue.Cmp(fr.Modulus()) >= 0 {
+		v.addViolation(path, "%s is not a canonical bn254 element", value)
+	}
+}
+
+func (v *validator) checkBN254Array(path string, values []string, expectedLen uint64) {
+	v.checkLen(path, len(values), expectedLen)
+	for i, value := range values {
+		v.checkBN254(path+"."+strconv.Itoa(i), value)
+	}
+}
+
+// Checks that every value of the proof is canonical, and that every array has the length implied
+// by commonData.  Returns all of the violations found, or nil if the proof can be deserialized
+// into the circuit's witness.
+func ValidateProofWithPublicInputs(raw *ProofWithPublicInputsRaw, commonData *CommonCircuitData) []Violation {
+	var v validator
+
+	friParams := commonData.FriParams
+	capHeight := friParams.Config.CapHeight
+	capLen := uint64(1) << capHeight
+	ldeBits := uint64(friParams.LdeBits())
+	numChallenges := commonData.Config.NumChallenges
+
+	v.checkBN254Array("proof.wires_cap", raw.Proof.WiresCap, capLen)
+	v.checkBN254Array("proof.plonk_zs_partial_products_cap", raw.Proof.PlonkZsPartialProductsCap, capLen)
+	v.checkBN254Array("proof.quotient_polys_cap", raw.Proof.QuotientPolysCap, capLen)
+
+	openings := raw.Proof.Openings
+	v.checkQuadraticExtensionArray("proof.openings.constants", openings.Constants, commonData.NumConstants)
+	v.checkQuadraticExtensionArray("proof.openings.plonk_sigmas", openings.PlonkSigmas, commonData.Config.NumRoutedWires)
+	v.checkQuadraticExtensionArray("proof.openings.wires", openings.Wires, commonData.Config.NumWires)
+	v.checkQuadraticExtensionArray("proof.openings.plonk_zs", openings.PlonkZs, numChallenges)
+	v.checkQuadraticExtensionArray("proof.openings.plonk_zs_next", openings.PlonkZsNext, numChallenges)
+	v.checkQuadraticExtensionArray(
+		"proof.openings.partial_products",
+		openings.PartialProducts,
+		numChallenges*commonData.NumPartialProducts,
+	)
+	v.checkQuadraticExtensionArray(
+		"proof.openings.quotient_polys",
+		openings.QuotientPolys,
+		numChallenges*commonData.QuotientDegreeFactor,
+	)
+	v.checkQuadraticExtensionArray("proof.openings.lookup_zs", LookupOpeningValues(openings.LookupZs), commonData.NumAllLookupPolys())
+	v.checkQuadraticExtensionArray("proof.openings.lookup_zs_next", LookupOpeningValues(openings.LookupZsNext), commonData.NumAllLookupPolys())
+
+	openingProof := raw.Proof.OpeningProof
+	numSteps := len(friParams.ReductionArityBits)
+	v.checkLen("proof.opening_proof.commit_phase_merkle_caps", len(openingProof.CommitPhaseMerkleCaps), uint64(numSteps))
+	for i, cap := range openingProof.CommitPhaseMerkleCaps {
+		v.checkBN254Array(fmt.Sprintf("proof.opening_proof.commit_phase_merkle_caps.%d", i), cap, capLen)
+	}
+
+	leafLens := commonData.OracleLeafLens()
+	v.checkLen("proof.opening_proof.query_round_proofs", len(openingProof.QueryRoundProofs), friParams.Config.NumQueryRounds)
+	for i, queryRound := range openingProof.QueryRoundProofs {
+		roundPath := fmt.Sprintf("proof.opening_proof.query_round_proofs.%d", i)
+
+		evalsProofsPath := roundPath + ".initial_trees_proof.evals_proofs"
+		evalsProofs := queryRound.InitialTreesProof.EvalsProofs
+		if v.checkLen(evalsProofsPath, len(evalsProofs), uint64(len(leafLens))) {
+			for j, evalsProof := range evalsProofs {
+				evalsProofPath := fmt.Sprintf("%s.%d", evalsProofsPath, j)
+				v.checkGoldilocksArray(evalsProofPath+".0", evalsProof.LeafElements, leafLens[j])
+				v.checkBN254Array(evalsProofPath+".1.siblings", evalsProof.MerkleProof.Hash, ldeBits-capHeight)
+			}
+		}
+
+		if v.checkLen(roundPath+".steps", len(queryRound.Steps), uint64(numSteps)) {
+			codewordLenBits := ldeBits
+			for j, step := range queryRound.Steps {
+				stepPath := fmt.Sprintf("%s.steps.%d", roundPath, j)
+				arityBits := friParams.ReductionArityBits[j]
+				codewordLenBits -= arityBits
+				v.checkQuadraticExtensionArray(stepPath+".evals", step.Evals, uint64(1)<<arityBits)
+				v.checkBN254Array(stepPath+".merkle_proof.siblings", step.MerkleProof.Siblings, codewordLenBits-capHeight)
+			}
+		}
+	}
+
+	v.checkQuadraticExtensionArray(
+		"proof.opening_proof.final_poly.coeffs",
+		openingProof.FinalPoly.Coeffs,
+		uint64(friParams.FinalPolyLen()),
+	)
+	v.checkGoldilocks("proof.opening_proof.pow_witness", openingProof.PowWitness)
+
+	v.checkGoldilocksArray("public_inputs", raw.PublicInputs, commonData.NumPublicInputs)
+
+	return v.violations
+}
+
+// Checks that the verifier only circuit data holds canonical hashes, and that its cap has the
+// length implied by commonData.
+func ValidateVerifierOnlyCircuitData(raw *VerifierOnlyCircuitDataRaw, commonData *CommonCircuitData) []Violation {
+	var v validator
+
+	capLen := uint64(1) << commonData.FriParams.Config.CapHeight
+	v.checkBN254Array("constants_sigmas_cap", raw.ConstantsSigmasCap, capLen)
+	v.checkBN254("circuit_digest", raw.CircuitDigest)
+
+	return v.violations
+}
+
+// Checks that the fields of the CommonCircuitData are consistent with each other, and that every
+// gate is supported.  Returns all of the violations found, or nil if the data can be used to
+// build the verifier.
+func (c *CommonCircuitData) Validate() []Violation {
+	return c.ValidateWithRegistry(gates.DefaultRegistry)
+}
+
+// Like Validate, but checks the gate IDs against the given registry, which may contain custom
+// gates.
+func (c *CommonCircuitData) ValidateWithRegistry(registry *gates.Registry) []Violation {
+	var v validator
+
+	if c.DegreeBits != c.FriParams.DegreeBits {
+		v.addViolation("fri_params.degree_bits", "%d does not match DegreeBits %d", c.FriParams.DegreeBits, c.DegreeBits)
+	}
+	if c.FriParams.Hiding != c.Config.ZeroKnowledge {
+		v.addViolation("fri_params.hiding", "%t does not match config.zero_knowledge", c.FriParams.Hiding)
+	}
+	if !reflect.DeepEqual(c.Config.FriConfig, c.FriParams.Config) {
+		v.addViolation("fri_params.config", "does not match config.fri_config")
+	}
+	if c.FriParams.TotalArities() > int(c.FriParams.DegreeBits) {
+		v.addViolation(
+			"fri_params.reduction_arity_bits",
+			"total arity bits %d exceed the degree bits %d",
+			c.FriParams.TotalArities(),
+			c.FriParams.DegreeBits,
+		)
+	} else if c.FriParams.Config.CapHeight > uint64(c.FriParams.LdeBits()-c.FriParams.TotalArities()) {
+		v.addViolation(
+			"fri_params.config.cap_height",
+			"%d exceeds the height of the last commit phase tree",
+			c.FriParams.Config.CapHeight,
+		)
+	}
+
+	if c.Config.NumRoutedWires > c.Config.NumWires {
+		v.addViolation("config.num_routed_wires", "%d exceeds num_wires %d", c.Config.NumRoutedWires, c.Config.NumWires)
+	}
+	v.checkLen("k_is", len(c.KIs), c.Config.NumRoutedWires)
+
+	if c.QuotientDegreeFactor < 2 || c.QuotientDegreeFactor > c.Config.MaxQuotientDegreeFactor {
+		v.addViolation(
+			"quotient_degree_factor",
+			"%d is not between 2 and max_quotient_degree_factor %d",
+			c.QuotientDegreeFactor,
+			c.Config.MaxQuotientDegreeFactor,
+		)
+	} else {
+		// The routed wires are split into chunks of QuotientDegreeFactor, and the last chunk's
+		// product is the Z polynomial itself.
+		numChunks := (c.Config.NumRoutedWires + c.QuotientDegreeFactor - 1) / c.QuotientDegreeFactor
+		if numChunks == 0 || c.NumPartialProducts != numChunks-1 {
+			v.addViolation(
+				"num_partial_products",
+				"%d does not match the %d routed wires with a quotient degree factor of %d",
+				c.NumPartialProducts,
+				c.Config.NumRoutedWires,
+				c.QuotientDegreeFactor,
+			)
+		}
+	}
+
+	if len(c.Luts) == 0 {
+		if c.NumLookupPolys != 0 {
+			v.addViolation("num_lookup_polys", "%d should be 0 without lookup tables", c.NumLookupPolys)
+		}
+		if c.NumLookupSelectors != 0 {
+			v.addViolation("num_lookup_selectors", "%d should be 0 without lookup tables", c.NumLookupSelectors)
+		}
+	} else {
+		// One running evaluation polynomial, and enough sum/logarithmic derivative polynomials for
+		// the lookups of a row to fit within the max quotient degree.
+		if c.Config.MaxQuotientDegreeFactor > 1 {
+			lookupDegree := c.Config.MaxQuotientDegreeFactor - 1
+			numLookupSlots := gates.LookupGateNumSlots(c.Config.NumRoutedWires)
+			expectedNumLookupPolys := (numLookupSlots+lookupDegree-1)/lookupDegree + 1
+			if c.NumLookupPolys != expectedNumLookupPolys {
+				v.addViolation(
+					"num_lookup_polys",
+					"%d does not match the %d lookups of a row with a max quotient degree factor of %d",
+					c.NumLookupPolys,
+					numLookupSlots,
+					c.Config.MaxQuotientDegreeFactor,
+				)
+			}
+		}
+		if c.NumLookupSelectors != gates.LOOKUP_SELECTOR_START_END+uint64(len(c.Luts)) {
+			v.addViolation(
+				"num_lookup_selectors",
+				"%d does not match the %d lookup tables",
+				c.NumLookupSelectors,
+				len(c.Luts),
+			)
+		}
+	}
+
+	numSelectors := c.SelectorsInfo.NumSelectors()
+	if c.NumConstants != numSelectors+c.NumLookupSelectors+c.Config.NumConstants {
+		v.addViolation(
+			"num_constants",
+			"%d does not match the %d selectors, %d lookup selectors and %d constants of the config",
+			c.NumConstants,
+			numSelectors,
+			c.NumLookupSelectors,
+			c.Config.NumConstants,
+		)
+	}
+
+	groups := c.SelectorsInfo.Groups()
+	for i, group := range groups {
+		if group.Start() >= group.End() || group.End() > uint64(len(c.GateIds)) {
+			v.addViolation(
+				fmt.Sprintf("selectors_info.groups.%d", i),
+				"[%d, %d) is not a non empty range of the %d gates",
+				group.Start(),
+				group.End(),
+				len(c.GateIds),
+			)
+		}
+	}
+
+	selectorIndices := c.SelectorsInfo.SelectorIndices()
+	v.checkLen("selectors_info.selector_indices", len(selectorIndices), uint64(len(c.GateIds)))
+	for i, selectorIndex := range selectorIndices {
+		path := fmt.Sprintf("selectors_info.selector_indices.%d", i)
+		if selectorIndex >= uint64(len(groups)) {
+			v.addViolation(path, "%d is not one of the %d selector groups", selectorIndex, len(groups))
+		} else if group := groups[selectorIndex]; uint64(i) < group.Start() || uint64(i) >= group.End() {
+			v.addViolation(path, "gate %d is not within the range [%d, %d) of group %d", i, group.Start(), group.End(), selectorIndex)
+		}
+	}
+
+	for i, gateId := range c.GateIds {
+		if _, err := registry.TryGateInstanceFromId(gateId); err != nil {
+			v.addViolation(fmt.Sprintf("gates.%d", i), "%v", err)
+		}
+	}
+
+	return v.violations
+}
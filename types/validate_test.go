@@ -0,0 +1,112 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/succinctlabs/gnark-plonky2-verifier/plonk/gates"
+)
+
+func TestValidate(t *testing.T) {
+	for _, testCase := range []string{"decode_block", "step"} {
+		commonCircuitData := ReadCommonCircuitData("../testdata/" + testCase + "/common_circuit_data.json")
+		proofWithPis := ReadProofWithPublicInputs("../testdata/" + testCase + "/proof_with_public_inputs.json")
+		verifierOnlyCircuitData := ReadVerifierOnlyCircuitData("../testdata/" + testCase + "/verifier_only_circuit_data.json")
+
+		for _, violation := range ValidateProofWithPublicInputs(&proofWithPis, &commonCircuitData) {
+			t.Errorf("%s: %s", testCase, violation)
+		}
+		for _, violation := range ValidateVerifierOnlyCircuitData(&verifierOnlyCircuitData, &commonCircuitData) {
+			t.Errorf("%s: %s", testCase, violation)
+		}
+	}
+}
+
+func TestValidateReportsViolations(t *testing.T) {
+	commonCircuitData := ReadCommonCircuitData("../testdata/step/common_circuit_data.json")
+	proofWithPis := ReadProofWithPublicInputs("../testdata/step/proof_with_public_inputs.json")
+	verifierOnlyCircuitData := ReadVerifierOnlyCircuitData("../testdata/step/verifier_only_circuit_data.json")
+
+	proofWithPis.Proof.WiresCap[1] = "0x1234"
+	proofWithPis.Proof.Openings.Wires[3][1] = 0xffffffff00000001
+	proofWithPis.Proof.Openings.QuotientPolys = proofWithPis.Proof.Openings.QuotientPolys[1:]
+	proofWithPis.Proof.OpeningProof.QueryRoundProofs[2].InitialTreesProof.EvalsProofs[0].MerkleProof.Hash[4] =
+		"21888242871839275222246405745257275088548364400416034343698204186575808495617"
+	proofWithPis.Proof.OpeningProof.QueryRoundProofs[5].Steps[1].Evals[0] = []uint64{1}
+	proofWithPis.PublicInputs = append(proofWithPis.PublicInputs, 1)
+	verifierOnlyCircuitData.CircuitDigest = "-1"
+
+	expectedPaths := []string{
+		"proof.wires_cap.1",
+		"proof.openings.wires.3.1",
+		"proof.openings.quotient_polys",
+		"proof.opening_proof.query_round_proofs.2.initial_trees_proof.evals_proofs.0.1.siblings.4",
+		"proof.opening_proof.query_round_proofs.5.steps.1.evals.0",
+		"public_inputs",
+	}
+	violations := ValidateProofWithPublicInputs(&proofWithPis, &commonCircuitData)
+	if len(violations) != len(expectedPaths) {
+		t.Fatalf("expected %d violations, got %v", len(expectedPaths), violations)
+	}
+	for i, violation := range violations {
+		if violation.Path != expectedPaths[i] {
+			t.Errorf("expected a violation at %s, got %s", expectedPaths[i], violation)
+		}
+	}
+
+	violations = ValidateVerifierOnlyCircuitData(&verifierOnlyCircuitData, &commonCircuitData)
+	if len(violations) != 1 || violations[0].Path != "circuit_digest" {
+		t.Errorf("expected a violation at circuit_digest, got %v", violations)
+	}
+}
+
+func TestCommonCircuitDataValidate(t *testing.T) {
+	for _, testCase := range []string{"decode_block", "step"} {
+		commonCircuitData := ReadCommonCircuitData("../testdata/" + testCase + "/common_circuit_data.json")
+		for _, violation := range commonCircuitData.Validate() {
+			t.Errorf("%s: %s", testCase, violation)
+		}
+	}
+
+	commonCircuitData := ReadCommonCircuitData("../testdata/step/common_circuit_data.json")
+	commonCircuitData.DegreeBits++
+	commonCircuitData.Config.FriConfig.NumQueryRounds++
+	commonCircuitData.Config.NumRoutedWires++
+	commonCircuitData.GateIds = append([]string{}, commonCircuitData.GateIds...)
+	commonCircuitData.GateIds[3] = "UnknownGate"
+
+	expectedPaths := []string{
+		"fri_params.degree_bits",
+		"fri_params.config",
+		"k_is",
+		"num_partial_products",
+		"gates.3",
+	}
+	violations := commonCircuitData.Validate()
+	if len(violations) != len(expectedPaths) {
+		t.Fatalf("expected %d violations, got %v", len(expectedPaths), violations)
+	}
+	for i, violation := range violations {
+		if violation.Path != expectedPaths[i] {
+			t.Errorf("expected a violation at %s, got %s", expectedPaths[i], violation)
+		}
+	}
+
+	// Move the last gate into the first selector group, without updating its selector index.
+	commonCircuitData = ReadCommonCircuitData("../testdata/step/common_circuit_data.json")
+	lastGate := uint64(len(commonCircuitData.GateIds) - 1)
+	selectorIndices := append([]uint64{}, commonCircuitData.SelectorsInfo.SelectorIndices()...)
+	selectorIndices[lastGate] = 0
+	groupStarts := []uint64{}
+	groupEnds := []uint64{}
+	for _, group := range commonCircuitData.SelectorsInfo.Groups() {
+		groupStarts = append(groupStarts, group.Start())
+		groupEnds = append(groupEnds, group.End())
+	}
+	commonCircuitData.SelectorsInfo = *gates.NewSelectorsInfo(selectorIndices, groupStarts, groupEnds)
+
+	violations = commonCircuitData.Validate()
+	if len(violations) != 1 || violations[0].Path != fmt.Sprintf("selectors_info.selector_indices.%d", lastGate) {
+		t.Errorf("expected a violation at the last selector index, got %v", violations)
+	}
+}
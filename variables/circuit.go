@@ -1,8 +1,11 @@
 package variables
 
 import (
+	"reflect"
+
 	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
 	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
 )
 
 type Proof struct {
@@ -13,11 +16,65 @@ type Proof struct {
 	OpeningProof              FriProof
 }
 
+// Creates a Proof whose caps, openings and FRI proof are sized from commonData.  None of the
+// variables are assigned, so it is meant to be used as the placeholder of a circuit definition.
+func NewProof(commonData *types.CommonCircuitData) Proof {
+	capHeight := commonData.Config.FriConfig.CapHeight
+	return Proof{
+		WiresCap:                  NewFriMerkleCap(capHeight),
+		PlonkZsPartialProductsCap: NewFriMerkleCap(capHeight),
+		QuotientPolysCap:          NewFriMerkleCap(capHeight),
+		Openings: NewOpeningSet(
+			commonData.NumConstants,
+			commonData.Config.NumRoutedWires,
+			commonData.Config.NumWires,
+			commonData.Config.NumChallenges,
+			commonData.NumPartialProducts,
+			commonData.QuotientDegreeFactor,
+			commonData.NumLookupPolys,
+		),
+		OpeningProof: NewFriProof(commonData),
+	}
+}
+
 type ProofWithPublicInputs struct {
 	Proof        Proof
 	PublicInputs []gl.Variable // Length = CommonCircuitData.NumPublicInputs
 }
 
+func NewProofWithPublicInputs(commonData *types.CommonCircuitData) ProofWithPublicInputs {
+	return ProofWithPublicInputs{
+		Proof:        NewProof(commonData),
+		PublicInputs: make([]gl.Variable, commonData.NumPublicInputs),
+	}
+}
+
+// Like NewProofWithPublicInputs, but every variable is assigned 0, so that it can fill the witness
+// of a proof that a circuit doesn't check, e.g. an unselected slot of a MultiVerifierCircuit.
+func NewZeroProofWithPublicInputs(commonData *types.CommonCircuitData) ProofWithPublicInputs {
+	proofWithPis := NewProofWithPublicInputs(commonData)
+	assignZeros(reflect.ValueOf(&proofWithPis).Elem())
+	return proofWithPis
+}
+
+// Assigns 0 to all of the unassigned variables in v.
+func assignZeros(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			v.Set(reflect.ValueOf(0))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			assignZeros(v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			assignZeros(v.Index(i))
+		}
+	}
+}
+
 type VerifierOnlyCircuitData struct {
 	ConstantSigmasCap FriMerkleCap
 	CircuitDigest     poseidon.BN254HashOut
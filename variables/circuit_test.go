@@ -0,0 +1,64 @@
+package variables
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+)
+
+// Checks that the placeholder allocated from the common circuit data has the same shape as a deserialized proof.
+func TestNewProofWithPublicInputs(t *testing.T) {
+	plonky2Circuits := []string{"step", "decode_block"}
+	for _, plonky2Circuit := range plonky2Circuits {
+		commonCircuitData := types.ReadCommonCircuitData("../testdata/" + plonky2Circuit + "/common_circuit_data.json")
+		proofWithPis, err := DeserializeProofWithPublicInputs(types.ReadProofWithPublicInputs("../testdata/" + plonky2Circuit + "/proof_with_public_inputs.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		placeholder := NewProofWithPublicInputs(&commonCircuitData)
+		checkSameShape(t, plonky2Circuit, reflect.ValueOf(placeholder), reflect.ValueOf(proofWithPis))
+	}
+}
+
+func checkSameShape(t *testing.T, path string, placeholder reflect.Value, expected reflect.Value) {
+	switch expected.Kind() {
+	case reflect.Slice, reflect.Array:
+		if placeholder.Len() != expected.Len() {
+			t.Fatalf("%s: expected length %d, got %d", path, expected.Len(), placeholder.Len())
+		}
+		for i := 0; i < expected.Len(); i++ {
+			checkSameShape(t, fmt.Sprintf("%s[%d]", path, i), placeholder.Index(i), expected.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < expected.NumField(); i++ {
+			checkSameShape(t, path+"."+expected.Type().Field(i).Name, placeholder.Field(i), expected.Field(i))
+		}
+	}
+}
+
+func TestNewZeroProofWithPublicInputs(t *testing.T) {
+	commonCircuitData := types.ReadCommonCircuitData("../testdata/decode_block/common_circuit_data.json")
+	zeroProofWithPis := NewZeroProofWithPublicInputs(&commonCircuitData)
+	checkSameShape(t, "decode_block", reflect.ValueOf(zeroProofWithPis), reflect.ValueOf(NewProofWithPublicInputs(&commonCircuitData)))
+	checkAllZeros(t, "decode_block", reflect.ValueOf(zeroProofWithPis))
+}
+
+func checkAllZeros(t *testing.T, path string, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() || v.Elem().Interface() != 0 {
+			t.Fatalf("%s: expected 0, got %v", path, v)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			checkAllZeros(t, fmt.Sprintf("%s[%d]", path, i), v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			checkAllZeros(t, path+"."+v.Type().Field(i).Name, v.Field(i))
+		}
+	}
+}
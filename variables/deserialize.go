@@ -1,22 +1,56 @@
 package variables
 
 import (
+	"fmt"
 	"math/big"
+	"strconv"
 
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark/frontend"
 	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
 	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
 	"github.com/succinctlabs/gnark-plonky2-verifier/types"
 )
 
-func DeserializeMerkleCap(merkleCapRaw []string) FriMerkleCap {
-	n := len(merkleCapRaw)
-	merkleCap := make([]poseidon.BN254HashOut, n)
-	for i := 0; i < n; i++ {
-		capBigInt, _ := new(big.Int).SetString(merkleCapRaw[i], 10)
-		merkleCap[i] = frontend.Variable(capBigInt)
+// Append a field name or an array index to a dotted JSON path, as used by types.ParseError.
+func fieldPath(path string, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func indexPath(path string, i int) string {
+	return fieldPath(path, strconv.Itoa(i))
+}
+
+// Parses a decimal BN254 hash.  Returns a types.ParseError holding path if it isn't a canonical
+// BN254 element.
+func stringToHashBN254(path string, rawHash string) (poseidon.BN254HashOut, error) {
+	hashBigInt, ok := new(big.Int).SetString(rawHash, 10)
+	if !ok {
+		return nil, &types.ParseError{Path: path, Err: fmt.Errorf("%q is not a decimal integer", rawHash)}
+	}
+	if hashBigInt.Sign() < 0 || hashBigInt.Cmp(fr.Modulus()) >= 0 {
+		return nil, &types.ParseError{Path: path, Err: fmt.Errorf("%s is not a canonical bn254 element", rawHash)}
+	}
+	return poseidon.BN254HashOut(frontend.Variable(hashBigInt)), nil
+}
+
+func stringArrayToHashBN254Array(path string, rawHashes []string) ([]poseidon.BN254HashOut, error) {
+	hashes := make([]poseidon.BN254HashOut, len(rawHashes))
+	for i := range rawHashes {
+		var err error
+		hashes[i], err = stringToHashBN254(indexPath(path, i), rawHashes[i])
+		if err != nil {
+			return nil, err
+		}
 	}
-	return merkleCap
+	return hashes, nil
+}
+
+func DeserializeMerkleCap(merkleCapRaw []string) (FriMerkleCap, error) {
+	return stringArrayToHashBN254Array("", merkleCapRaw)
 }
 
 func DeserializeMerkleProof(merkleProofRaw struct{ Siblings []interface{} }) FriMerkleProof {
@@ -38,6 +72,8 @@ func DeserializeOpeningSet(openingSetRaw struct {
 	PlonkZsNext     [][]uint64
 	PartialProducts [][]uint64
 	QuotientPolys   [][]uint64
+	LookupZs        *[][]uint64
+	LookupZsNext    *[][]uint64
 }) OpeningSet {
 	return OpeningSet{
 		Constants:       gl.Uint64ArrayToQuadraticExtensionArray(openingSetRaw.Constants),
@@ -47,75 +83,98 @@ func DeserializeOpeningSet(openingSetRaw struct {
 		PlonkZsNext:     gl.Uint64ArrayToQuadraticExtensionArray(openingSetRaw.PlonkZsNext),
 		PartialProducts: gl.Uint64ArrayToQuadraticExtensionArray(openingSetRaw.PartialProducts),
 		QuotientPolys:   gl.Uint64ArrayToQuadraticExtensionArray(openingSetRaw.QuotientPolys),
+		LookupZs:        gl.Uint64ArrayToQuadraticExtensionArray(types.LookupOpeningValues(openingSetRaw.LookupZs)),
+		LookupZsNext:    gl.Uint64ArrayToQuadraticExtensionArray(types.LookupOpeningValues(openingSetRaw.LookupZsNext)),
 	}
 }
 
-func StringArrayToHashBN254Array(rawHashes []string) []poseidon.BN254HashOut {
-	hashes := []poseidon.BN254HashOut{}
-
-	for i := 0; i < len(rawHashes); i++ {
-		hashBigInt, _ := new(big.Int).SetString(rawHashes[i], 10)
-		hashVar := frontend.Variable(hashBigInt)
-		hashes = append(hashes, poseidon.BN254HashOut(hashVar))
-	}
-
-	return hashes
+func StringArrayToHashBN254Array(rawHashes []string) ([]poseidon.BN254HashOut, error) {
+	return stringArrayToHashBN254Array("", rawHashes)
 }
 
 func DeserializeFriProof(openingProofRaw struct {
 	CommitPhaseMerkleCaps [][]string
-	QueryRoundProofs      []struct {
-		InitialTreesProof struct {
-			EvalsProofs []types.EvalProofRaw
-		}
-		Steps []struct {
-			Evals       [][]uint64
-			MerkleProof struct {
-				Siblings []string
-			}
-		}
+	QueryRoundProofs      []types.FriQueryRoundRaw
+	FinalPoly             struct {
+		Coeffs [][]uint64
 	}
-	FinalPoly struct {
+	PowWitness uint64
+}) (FriProof, error) {
+	return deserializeFriProof("", openingProofRaw)
+}
+
+func deserializeFriProof(path string, openingProofRaw struct {
+	CommitPhaseMerkleCaps [][]string
+	QueryRoundProofs      []types.FriQueryRoundRaw
+	FinalPoly             struct {
 		Coeffs [][]uint64
 	}
 	PowWitness uint64
-}) FriProof {
+}) (FriProof, error) {
 	var openingProof FriProof
+	var err error
 	openingProof.PowWitness = gl.NewVariable(openingProofRaw.PowWitness)
 	openingProof.FinalPoly.Coeffs = gl.Uint64ArrayToQuadraticExtensionArray(openingProofRaw.FinalPoly.Coeffs)
 
 	openingProof.CommitPhaseMerkleCaps = make([]FriMerkleCap, len(openingProofRaw.CommitPhaseMerkleCaps))
 	for i := 0; i < len(openingProofRaw.CommitPhaseMerkleCaps); i++ {
-		openingProof.CommitPhaseMerkleCaps[i] = StringArrayToHashBN254Array(openingProofRaw.CommitPhaseMerkleCaps[i])
+		capPath := indexPath(fieldPath(path, "commit_phase_merkle_caps"), i)
+		openingProof.CommitPhaseMerkleCaps[i], err = stringArrayToHashBN254Array(capPath, openingProofRaw.CommitPhaseMerkleCaps[i])
+		if err != nil {
+			return openingProof, err
+		}
 	}
 
 	numQueryRoundProofs := len(openingProofRaw.QueryRoundProofs)
 	openingProof.QueryRoundProofs = make([]FriQueryRound, numQueryRoundProofs)
 
 	for i := 0; i < numQueryRoundProofs; i++ {
+		roundPath := indexPath(fieldPath(path, "query_round_proofs"), i)
+
 		numEvalProofs := len(openingProofRaw.QueryRoundProofs[i].InitialTreesProof.EvalsProofs)
 		openingProof.QueryRoundProofs[i].InitialTreesProof.EvalsProofs = make([]FriEvalProof, numEvalProofs)
 		for j := 0; j < numEvalProofs; j++ {
+			siblingsPath := indexPath(roundPath+".initial_trees_proof.evals_proofs", j) + ".1.siblings"
 			openingProof.QueryRoundProofs[i].InitialTreesProof.EvalsProofs[j].Elements = gl.Uint64ArrayToVariableArray(openingProofRaw.QueryRoundProofs[i].InitialTreesProof.EvalsProofs[j].LeafElements)
-			openingProof.QueryRoundProofs[i].InitialTreesProof.EvalsProofs[j].MerkleProof.Siblings = StringArrayToHashBN254Array(openingProofRaw.QueryRoundProofs[i].InitialTreesProof.EvalsProofs[j].MerkleProof.Hash)
+			openingProof.QueryRoundProofs[i].InitialTreesProof.EvalsProofs[j].MerkleProof.Siblings, err = stringArrayToHashBN254Array(siblingsPath, openingProofRaw.QueryRoundProofs[i].InitialTreesProof.EvalsProofs[j].MerkleProof.Hash)
+			if err != nil {
+				return openingProof, err
+			}
 		}
 
 		numSteps := len(openingProofRaw.QueryRoundProofs[i].Steps)
 		openingProof.QueryRoundProofs[i].Steps = make([]FriQueryStep, numSteps)
 		for j := 0; j < numSteps; j++ {
+			siblingsPath := indexPath(roundPath+".steps", j) + ".merkle_proof.siblings"
 			openingProof.QueryRoundProofs[i].Steps[j].Evals = gl.Uint64ArrayToQuadraticExtensionArray(openingProofRaw.QueryRoundProofs[i].Steps[j].Evals)
-			openingProof.QueryRoundProofs[i].Steps[j].MerkleProof.Siblings = StringArrayToHashBN254Array(openingProofRaw.QueryRoundProofs[i].Steps[j].MerkleProof.Siblings)
+			openingProof.QueryRoundProofs[i].Steps[j].MerkleProof.Siblings, err = stringArrayToHashBN254Array(siblingsPath, openingProofRaw.QueryRoundProofs[i].Steps[j].MerkleProof.Siblings)
+			if err != nil {
+				return openingProof, err
+			}
 		}
 	}
 
-	return openingProof
+	return openingProof, nil
 }
 
-func DeserializeProofWithPublicInputs(raw types.ProofWithPublicInputsRaw) ProofWithPublicInputs {
+// Deserializes a proof into the circuit's witness.  Returns a types.ParseError with the JSON path
+// of the first hash that isn't a canonical BN254 element.  Run types.ValidateProofWithPublicInputs
+// first to check the rest of the values and the lengths against the common circuit data.
+func DeserializeProofWithPublicInputs(raw types.ProofWithPublicInputsRaw) (ProofWithPublicInputs, error) {
 	var proofWithPis ProofWithPublicInputs
-	proofWithPis.Proof.WiresCap = DeserializeMerkleCap(raw.Proof.WiresCap)
-	proofWithPis.Proof.PlonkZsPartialProductsCap = DeserializeMerkleCap(raw.Proof.PlonkZsPartialProductsCap)
-	proofWithPis.Proof.QuotientPolysCap = DeserializeMerkleCap(raw.Proof.QuotientPolysCap)
+	var err error
+	proofWithPis.Proof.WiresCap, err = stringArrayToHashBN254Array("proof.wires_cap", raw.Proof.WiresCap)
+	if err != nil {
+		return proofWithPis, err
+	}
+	proofWithPis.Proof.PlonkZsPartialProductsCap, err = stringArrayToHashBN254Array("proof.plonk_zs_partial_products_cap", raw.Proof.PlonkZsPartialProductsCap)
+	if err != nil {
+		return proofWithPis, err
+	}
+	proofWithPis.Proof.QuotientPolysCap, err = stringArrayToHashBN254Array("proof.quotient_polys_cap", raw.Proof.QuotientPolysCap)
+	if err != nil {
+		return proofWithPis, err
+	}
 	proofWithPis.Proof.Openings = DeserializeOpeningSet(struct {
 		Constants       [][]uint64
 		PlonkSigmas     [][]uint64
@@ -124,33 +183,32 @@ func DeserializeProofWithPublicInputs(raw types.ProofWithPublicInputsRaw) ProofW
 		PlonkZsNext     [][]uint64
 		PartialProducts [][]uint64
 		QuotientPolys   [][]uint64
+		LookupZs        *[][]uint64
+		LookupZsNext    *[][]uint64
 	}(raw.Proof.Openings))
-	proofWithPis.Proof.OpeningProof = DeserializeFriProof(struct {
+	proofWithPis.Proof.OpeningProof, err = deserializeFriProof("proof.opening_proof", struct {
 		CommitPhaseMerkleCaps [][]string
-		QueryRoundProofs      []struct {
-			InitialTreesProof struct {
-				EvalsProofs []types.EvalProofRaw
-			}
-			Steps []struct {
-				Evals       [][]uint64
-				MerkleProof struct {
-					Siblings []string
-				}
-			}
-		}
-		FinalPoly  struct{ Coeffs [][]uint64 }
-		PowWitness uint64
+		QueryRoundProofs      []types.FriQueryRoundRaw
+		FinalPoly             struct{ Coeffs [][]uint64 }
+		PowWitness            uint64
 	}(raw.Proof.OpeningProof))
+	if err != nil {
+		return proofWithPis, err
+	}
 	proofWithPis.PublicInputs = gl.Uint64ArrayToVariableArray(raw.PublicInputs)
 
-	return proofWithPis
+	return proofWithPis, nil
 }
 
-func DeserializeVerifierOnlyCircuitData(raw types.VerifierOnlyCircuitDataRaw) VerifierOnlyCircuitData {
+// Deserializes verifier only circuit data into the circuit's witness.  Returns a types.ParseError
+// with the JSON path of the first hash that isn't a canonical BN254 element.
+func DeserializeVerifierOnlyCircuitData(raw types.VerifierOnlyCircuitDataRaw) (VerifierOnlyCircuitData, error) {
 	var verifierOnlyCircuitData VerifierOnlyCircuitData
-	verifierOnlyCircuitData.ConstantSigmasCap = DeserializeMerkleCap(raw.ConstantsSigmasCap)
-	circuitDigestBigInt, _ := new(big.Int).SetString(raw.CircuitDigest, 10)
-	circuitDigestVar := frontend.Variable(circuitDigestBigInt)
-	verifierOnlyCircuitData.CircuitDigest = poseidon.BN254HashOut(circuitDigestVar)
-	return verifierOnlyCircuitData
+	var err error
+	verifierOnlyCircuitData.ConstantSigmasCap, err = stringArrayToHashBN254Array("constants_sigmas_cap", raw.ConstantsSigmasCap)
+	if err != nil {
+		return verifierOnlyCircuitData, err
+	}
+	verifierOnlyCircuitData.CircuitDigest, err = stringToHashBN254("circuit_digest", raw.CircuitDigest)
+	return verifierOnlyCircuitData, err
 }
@@ -1,17 +1,69 @@
 package variables
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/succinctlabs/gnark-plonky2-verifier/types"
 )
 
 func TestDeserializeProofWithPublicInputs(t *testing.T) {
-	proofWithPis := DeserializeProofWithPublicInputs(types.ReadProofWithPublicInputs("../testdata/decode_block/proof_with_public_inputs.json"))
+	proofWithPis, err := DeserializeProofWithPublicInputs(types.ReadProofWithPublicInputs("../testdata/decode_block/proof_with_public_inputs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
 	t.Logf("%+v\n", proofWithPis)
 }
 
 func TestDeserializeVerifierOnlyCircuitData(t *testing.T) {
-	verifierOnlyCircuitData := DeserializeVerifierOnlyCircuitData(types.ReadVerifierOnlyCircuitData("../testdata/decode_block/verifier_only_circuit_data.json"))
+	verifierOnlyCircuitData, err := DeserializeVerifierOnlyCircuitData(types.ReadVerifierOnlyCircuitData("../testdata/decode_block/verifier_only_circuit_data.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
 	t.Logf("%+v\n", verifierOnlyCircuitData)
 }
+
+func TestDeserializeMalformedHashes(t *testing.T) {
+	proofPath := "../testdata/decode_block/proof_with_public_inputs.json"
+	verifierOnlyCircuitDataPath := "../testdata/decode_block/verifier_only_circuit_data.json"
+
+	for _, test := range []struct {
+		path   string
+		mutate func(proof *types.ProofWithPublicInputsRaw, verifierOnlyCircuitData *types.VerifierOnlyCircuitDataRaw)
+	}{
+		{"proof.wires_cap.2", func(proof *types.ProofWithPublicInputsRaw, _ *types.VerifierOnlyCircuitDataRaw) {
+			proof.Proof.WiresCap[2] = "0x1234"
+		}},
+		{"proof.opening_proof.commit_phase_merkle_caps.1.0", func(proof *types.ProofWithPublicInputsRaw, _ *types.VerifierOnlyCircuitDataRaw) {
+			// The BN254 scalar field modulus.
+			proof.Proof.OpeningProof.CommitPhaseMerkleCaps[1][0] = "21888242871839275222246405745257275088548364400416034343698204186575808495617"
+		}},
+		{"proof.opening_proof.query_round_proofs.3.initial_trees_proof.evals_proofs.2.1.siblings.0", func(proof *types.ProofWithPublicInputsRaw, _ *types.VerifierOnlyCircuitDataRaw) {
+			proof.Proof.OpeningProof.QueryRoundProofs[3].InitialTreesProof.EvalsProofs[2].MerkleProof.Hash[0] = ""
+		}},
+		{"proof.opening_proof.query_round_proofs.0.steps.1.merkle_proof.siblings.2", func(proof *types.ProofWithPublicInputsRaw, _ *types.VerifierOnlyCircuitDataRaw) {
+			proof.Proof.OpeningProof.QueryRoundProofs[0].Steps[1].MerkleProof.Siblings[2] = "-1"
+		}},
+		{"circuit_digest", func(_ *types.ProofWithPublicInputsRaw, verifierOnlyCircuitData *types.VerifierOnlyCircuitDataRaw) {
+			verifierOnlyCircuitData.CircuitDigest = "digest"
+		}},
+	} {
+		proof := types.ReadProofWithPublicInputs(proofPath)
+		verifierOnlyCircuitData := types.ReadVerifierOnlyCircuitData(verifierOnlyCircuitDataPath)
+		test.mutate(&proof, &verifierOnlyCircuitData)
+
+		_, err := DeserializeProofWithPublicInputs(proof)
+		if err == nil {
+			_, err = DeserializeVerifierOnlyCircuitData(verifierOnlyCircuitData)
+		}
+
+		var parseErr *types.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s: expected a ParseError, got %v", test.path, err)
+			continue
+		}
+		if parseErr.Path != test.path {
+			t.Errorf("expected an error at %s, got %v", test.path, err)
+		}
+	}
+}
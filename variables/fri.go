@@ -3,6 +3,7 @@ package variables
 import (
 	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
 	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
 )
 
 type PolynomialCoeffs struct {
@@ -72,6 +73,46 @@ type FriProof struct {
 	PowWitness            gl.Variable
 }
 
+// Creates a FriProof with every cap, opening and query round allocated according to the
+// FriParams and CircuitConfig within commonData.  The returned proof's variables are unassigned,
+// so it can be used as the placeholder when compiling a circuit.
+func NewFriProof(commonData *types.CommonCircuitData) FriProof {
+	friParams := commonData.FriParams
+	capHeight := friParams.Config.CapHeight
+	ldeBits := uint64(friParams.LdeBits())
+
+	commitPhaseMerkleCaps := make([]FriMerkleCap, len(friParams.ReductionArityBits))
+	for i := range commitPhaseMerkleCaps {
+		commitPhaseMerkleCaps[i] = NewFriMerkleCap(capHeight)
+	}
+
+	queryRoundProofs := make([]FriQueryRound, friParams.Config.NumQueryRounds)
+	for i := range queryRoundProofs {
+		evalsProofs := []FriEvalProof{}
+		for _, leafLen := range commonData.OracleLeafLens() {
+			evalsProofs = append(
+				evalsProofs,
+				NewFriEvalProof(make([]gl.Variable, leafLen), NewFriMerkleProof(ldeBits-capHeight)),
+			)
+		}
+
+		steps := []FriQueryStep{}
+		codewordLenBits := ldeBits
+		for _, arityBits := range friParams.ReductionArityBits {
+			codewordLenBits -= arityBits
+			steps = append(steps, NewFriQueryStep(arityBits, codewordLenBits-capHeight))
+		}
+
+		queryRoundProofs[i] = NewFriQueryRound(steps, NewFriInitialTreeProof(evalsProofs))
+	}
+
+	return FriProof{
+		CommitPhaseMerkleCaps: commitPhaseMerkleCaps,
+		QueryRoundProofs:      queryRoundProofs,
+		FinalPoly:             NewPolynomialCoeffs(uint64(friParams.FinalPolyLen())),
+	}
+}
+
 type FriChallenges struct {
 	FriAlpha        gl.QuadraticExtensionVariable
 	FriBetas        []gl.QuadraticExtensionVariable
@@ -10,12 +10,37 @@ type OpeningSet struct {
 	PlonkZsNext     []gl.QuadraticExtensionVariable // Length = CommonCircuitData.NumChallenges
 	PartialProducts []gl.QuadraticExtensionVariable // Length = CommonCircuitData.NumChallenges * CommonCircuitData.NumPartialProducts
 	QuotientPolys   []gl.QuadraticExtensionVariable // Length = CommonCircuitData.NumChallenges * CommonCircuitData.QuotientDegreeFactor
+	LookupZs        []gl.QuadraticExtensionVariable // Length = CommonCircuitData.NumChallenges * CommonCircuitData.NumLookupPolys
+	LookupZsNext    []gl.QuadraticExtensionVariable // Length = CommonCircuitData.NumChallenges * CommonCircuitData.NumLookupPolys
+}
+
+func NewOpeningSet(
+	numConstants uint64,
+	numRoutedWires uint64,
+	numWires uint64,
+	numChallenges uint64,
+	numPartialProducts uint64,
+	quotientDegreeFactor uint64,
+	numLookupPolys uint64,
+) OpeningSet {
+	return OpeningSet{
+		Constants:       make([]gl.QuadraticExtensionVariable, numConstants),
+		PlonkSigmas:     make([]gl.QuadraticExtensionVariable, numRoutedWires),
+		Wires:           make([]gl.QuadraticExtensionVariable, numWires),
+		PlonkZs:         make([]gl.QuadraticExtensionVariable, numChallenges),
+		PlonkZsNext:     make([]gl.QuadraticExtensionVariable, numChallenges),
+		PartialProducts: make([]gl.QuadraticExtensionVariable, numChallenges*numPartialProducts),
+		QuotientPolys:   make([]gl.QuadraticExtensionVariable, numChallenges*quotientDegreeFactor),
+		LookupZs:        make([]gl.QuadraticExtensionVariable, numChallenges*numLookupPolys),
+		LookupZsNext:    make([]gl.QuadraticExtensionVariable, numChallenges*numLookupPolys),
+	}
 }
 
 type ProofChallenges struct {
 	PlonkBetas    []gl.Variable
 	PlonkGammas   []gl.Variable
 	PlonkAlphas   []gl.Variable
+	PlonkDeltas   []gl.Variable // Empty for circuits without lookups
 	PlonkZeta     gl.QuadraticExtensionVariable
 	FriChallenges FriChallenges
 }
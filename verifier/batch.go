@@ -0,0 +1,117 @@
+package verifier
+
+import (
+	"github.com/consensys/gnark/frontend"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+	"github.com/succinctlabs/gnark-plonky2-verifier/variables"
+)
+
+// Verifies every proof with the same chip, so that the gates and the FRI parameters that are
+// derived from the common circuit data are only set up once.
+func (c *VerifierChip) VerifyBatch(
+	proofs []variables.Proof,
+	publicInputs [][]gl.Variable,
+	verifierData variables.VerifierOnlyCircuitData,
+) {
+	if len(proofs) != len(publicInputs) {
+		panic("the number of proofs and of public inputs don't match")
+	}
+
+	for i := range proofs {
+		c.Verify(proofs[i], publicInputs[i], verifierData)
+	}
+}
+
+// Returns the Poseidon BN254 hash of the concatenation of the proofs' public inputs.  It matches
+// poseidon.BN254Hasher.HashNoPad, so the commitment can be recomputed out of circuit.
+func (c *VerifierChip) GetBatchPublicInputsCommitment(publicInputs [][]gl.Variable) poseidon.BN254HashOut {
+	allPublicInputs := []gl.Variable{}
+	for _, proofPublicInputs := range publicInputs {
+		allPublicInputs = append(allPublicInputs, proofPublicInputs...)
+	}
+	return c.poseidonBN254Chip.HashNoPad(allPublicInputs)
+}
+
+// Verifies a fixed number of proofs of the same plonky2 circuit, and exposes all of their public
+// inputs as public inputs of the gnark circuit.
+type BatchVerifierCircuit struct {
+	PublicInputs            [][]gl.Variable `gnark:",public"`
+	Proofs                  []variables.Proof
+	VerifierOnlyCircuitData variables.VerifierOnlyCircuitData `gnark:"-"`
+
+	// This is configuration for the circuit, it is a constant not a variable
+	CommonCircuitData types.CommonCircuitData
+}
+
+// Creates the placeholder BatchVerifierCircuit for numProofs proofs that should be compiled.  Like
+// NewExampleVerifierCircuit, the proofs are allocated from commonCircuitData only.
+func NewBatchVerifierCircuit(
+	numProofs int,
+	verifierOnlyCircuitData variables.VerifierOnlyCircuitData,
+	commonCircuitData types.CommonCircuitData,
+) BatchVerifierCircuit {
+	publicInputs, proofs := newBatch(numProofs, &commonCircuitData)
+	return BatchVerifierCircuit{
+		PublicInputs:            publicInputs,
+		Proofs:                  proofs,
+		VerifierOnlyCircuitData: verifierOnlyCircuitData,
+		CommonCircuitData:       commonCircuitData,
+	}
+}
+
+func (c *BatchVerifierCircuit) Define(api frontend.API) error {
+	verifierChip := NewVerifierChip(api, c.CommonCircuitData)
+	verifierChip.VerifyBatch(c.Proofs, c.PublicInputs, c.VerifierOnlyCircuitData)
+
+	return nil
+}
+
+// Like BatchVerifierCircuit, but the proofs' public inputs are private and the only public input
+// of the gnark circuit is their commitment (see VerifierChip.GetBatchPublicInputsCommitment).
+// This keeps the cost of verifying the gnark proof independent of the number of proofs.
+type CommittedBatchVerifierCircuit struct {
+	PublicInputsCommitment  frontend.Variable `gnark:",public"`
+	PublicInputs            [][]gl.Variable
+	Proofs                  []variables.Proof
+	VerifierOnlyCircuitData variables.VerifierOnlyCircuitData `gnark:"-"`
+
+	// This is configuration for the circuit, it is a constant not a variable
+	CommonCircuitData types.CommonCircuitData
+}
+
+// Creates the placeholder CommittedBatchVerifierCircuit for numProofs proofs that should be
+// compiled.
+func NewCommittedBatchVerifierCircuit(
+	numProofs int,
+	verifierOnlyCircuitData variables.VerifierOnlyCircuitData,
+	commonCircuitData types.CommonCircuitData,
+) CommittedBatchVerifierCircuit {
+	publicInputs, proofs := newBatch(numProofs, &commonCircuitData)
+	return CommittedBatchVerifierCircuit{
+		PublicInputs:            publicInputs,
+		Proofs:                  proofs,
+		VerifierOnlyCircuitData: verifierOnlyCircuitData,
+		CommonCircuitData:       commonCircuitData,
+	}
+}
+
+func (c *CommittedBatchVerifierCircuit) Define(api frontend.API) error {
+	verifierChip := NewVerifierChip(api, c.CommonCircuitData)
+	verifierChip.VerifyBatch(c.Proofs, c.PublicInputs, c.VerifierOnlyCircuitData)
+	api.AssertIsEqual(verifierChip.GetBatchPublicInputsCommitment(c.PublicInputs), c.PublicInputsCommitment)
+
+	return nil
+}
+
+func newBatch(numProofs int, commonCircuitData *types.CommonCircuitData) ([][]gl.Variable, []variables.Proof) {
+	publicInputs := make([][]gl.Variable, numProofs)
+	proofs := make([]variables.Proof, numProofs)
+	for i := 0; i < numProofs; i++ {
+		proofWithPis := variables.NewProofWithPublicInputs(commonCircuitData)
+		publicInputs[i] = proofWithPis.PublicInputs
+		proofs[i] = proofWithPis.Proof
+	}
+	return publicInputs, proofs
+}
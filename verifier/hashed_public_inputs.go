@@ -0,0 +1,140 @@
+package verifier
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/hash/sha3"
+	"github.com/consensys/gnark/std/math/uints"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+	"github.com/succinctlabs/gnark-plonky2-verifier/variables"
+	cryptosha3 "golang.org/x/crypto/sha3"
+)
+
+// The hash functions that the public inputs can be committed to with.
+type PublicInputsHash int
+
+const (
+	KECCAK256 PublicInputsHash = iota
+	SHA256
+)
+
+func (h PublicInputsHash) newHasher(api frontend.API) (hash.BinaryHasher, error) {
+	switch h {
+	case KECCAK256:
+		return sha3.NewLegacyKeccak256(api)
+	case SHA256:
+		return sha2.New(api)
+	default:
+		return nil, fmt.Errorf("unknown public inputs hash %d", h)
+	}
+}
+
+// Returns the digest of the public inputs encoded as big endian u64s, i.e. the hash of
+// abi.encodePacked(uint64[]) in Solidity, split into its high and low 128 bits so that each half
+// fits in a BN254 field element.
+//
+// The public inputs are range checked to be canonical Goldilocks elements, since they aren't
+// public inputs of the gnark circuit anymore and the caller can't check them.
+func (c *VerifierChip) GetPublicInputsDigest(publicInputs []gl.Variable, publicInputsHash PublicInputsHash) [2]frontend.Variable {
+	hasher, err := publicInputsHash.newHasher(c.api)
+	if err != nil {
+		panic(err)
+	}
+	u64Api, err := uints.New[uints.U64](c.api)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, publicInput := range publicInputs {
+		c.glChip.RangeCheck(publicInput)
+
+		// ValueOf doesn't check that the bytes add up to the value.
+		publicInputBytes := u64Api.ValueOf(publicInput.Limb)
+		c.api.AssertIsEqual(u64Api.ToValue(publicInputBytes), publicInput.Limb)
+
+		hasher.Write(u64Api.UnpackMSB(publicInputBytes))
+	}
+
+	digest := hasher.Sum()
+	var halves [2]frontend.Variable
+	for i := range halves {
+		half := frontend.Variable(0)
+		for _, digestByte := range digest[16*i : 16*(i+1)] {
+			half = c.api.Add(c.api.Mul(half, 256), digestByte.Val)
+		}
+		halves[i] = half
+	}
+	return halves
+}
+
+// The out of circuit counterpart of VerifierChip.GetPublicInputsDigest.
+func ComputePublicInputsDigest(publicInputs []uint64, publicInputsHash PublicInputsHash) ([2]*big.Int, error) {
+	encoded := make([]byte, 8*len(publicInputs))
+	for i, publicInput := range publicInputs {
+		binary.BigEndian.PutUint64(encoded[8*i:], publicInput)
+	}
+
+	var digest []byte
+	switch publicInputsHash {
+	case KECCAK256:
+		hasher := cryptosha3.NewLegacyKeccak256()
+		hasher.Write(encoded)
+		digest = hasher.Sum(nil)
+	case SHA256:
+		sum := sha256.Sum256(encoded)
+		digest = sum[:]
+	default:
+		return [2]*big.Int{}, fmt.Errorf("unknown public inputs hash %d", publicInputsHash)
+	}
+
+	return [2]*big.Int{new(big.Int).SetBytes(digest[:16]), new(big.Int).SetBytes(digest[16:])}, nil
+}
+
+// Like ExampleVerifierCircuit, but the public inputs are private and the only public inputs of the
+// gnark circuit are the two halves of their digest (see VerifierChip.GetPublicInputsDigest).  This
+// saves calldata and MSM work in the Solidity verifier, which recomputes the digest with the
+// keccak256 or sha256 precompile instead.
+type HashedPublicInputsVerifierCircuit struct {
+	PublicInputsDigest      [2]frontend.Variable `gnark:",public"`
+	PublicInputs            []gl.Variable
+	Proof                   variables.Proof
+	VerifierOnlyCircuitData variables.VerifierOnlyCircuitData `gnark:"-"`
+
+	// This is configuration for the circuit, it is a constant not a variable
+	CommonCircuitData types.CommonCircuitData
+	PublicInputsHash  PublicInputsHash `gnark:"-"`
+}
+
+// Creates the placeholder HashedPublicInputsVerifierCircuit that should be compiled.
+func NewHashedPublicInputsVerifierCircuit(
+	verifierOnlyCircuitData variables.VerifierOnlyCircuitData,
+	commonCircuitData types.CommonCircuitData,
+	publicInputsHash PublicInputsHash,
+) HashedPublicInputsVerifierCircuit {
+	proofWithPis := variables.NewProofWithPublicInputs(&commonCircuitData)
+	return HashedPublicInputsVerifierCircuit{
+		PublicInputs:            proofWithPis.PublicInputs,
+		Proof:                   proofWithPis.Proof,
+		VerifierOnlyCircuitData: verifierOnlyCircuitData,
+		CommonCircuitData:       commonCircuitData,
+		PublicInputsHash:        publicInputsHash,
+	}
+}
+
+func (c *HashedPublicInputsVerifierCircuit) Define(api frontend.API) error {
+	verifierChip := NewVerifierChip(api, c.CommonCircuitData)
+	verifierChip.Verify(c.Proof, c.PublicInputs, c.VerifierOnlyCircuitData)
+
+	digest := verifierChip.GetPublicInputsDigest(c.PublicInputs, c.PublicInputsHash)
+	api.AssertIsEqual(digest[0], c.PublicInputsDigest[0])
+	api.AssertIsEqual(digest[1], c.PublicInputsDigest[1])
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package verifier
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/selector"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/plonk/gates"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+	"github.com/succinctlabs/gnark-plonky2-verifier/variables"
+)
+
+// One of the plonky2 circuits that a MultiVerifierCircuit accepts proofs of.
+type AllowedCircuit struct {
+	CommonCircuitData       types.CommonCircuitData
+	VerifierOnlyCircuitData variables.VerifierOnlyCircuitData
+}
+
+// Verifies a proof of one of several plonky2 circuits, which may have different common circuit
+// data, and exposes the circuit digest of the selected circuit so that a contract can dispatch on
+// it.
+//
+// A gnark circuit can't skip constraints, so there's a proof slot for every allowed circuit, and
+// the circuit is about as large as K ExampleVerifierCircuits with K allowed circuits, and so is the
+// cost of proving it, whichever circuit is selected.  If the allowed circuits share their common
+// circuit data, UniversalVerifierCircuit verifies a single proof instead.
+//
+// Only the proof in the selected slot is checked: the other slots can hold any proof whose values
+// are canonical, and NewMultiVerifierWitness fills them with zeros.  The public inputs of the
+// selected proof are exposed in PublicInputs, padded with zeros to the largest number of public
+// inputs of the allowed circuits.
+type MultiVerifierCircuit struct {
+	CircuitDigest      frontend.Variable `gnark:",public"`
+	PublicInputs       []gl.Variable     `gnark:",public"`
+	Selector           frontend.Variable
+	ProofsPublicInputs [][]gl.Variable
+	Proofs             []variables.Proof
+
+	// This is configuration for the circuit, it is a constant not a variable
+	AllowedCircuits []AllowedCircuit `gnark:"-"`
+}
+
+// Creates the placeholder MultiVerifierCircuit that should be compiled, with a proof slot for each
+// of the allowed circuits.
+func NewMultiVerifierCircuit(allowedCircuits []AllowedCircuit) MultiVerifierCircuit {
+	numPublicInputs := uint64(0)
+	proofsPublicInputs := make([][]gl.Variable, len(allowedCircuits))
+	proofs := make([]variables.Proof, len(allowedCircuits))
+	for i := range allowedCircuits {
+		proofWithPis := variables.NewProofWithPublicInputs(&allowedCircuits[i].CommonCircuitData)
+		proofsPublicInputs[i] = proofWithPis.PublicInputs
+		proofs[i] = proofWithPis.Proof
+		if allowedCircuits[i].CommonCircuitData.NumPublicInputs > numPublicInputs {
+			numPublicInputs = allowedCircuits[i].CommonCircuitData.NumPublicInputs
+		}
+	}
+
+	return MultiVerifierCircuit{
+		PublicInputs:       make([]gl.Variable, numPublicInputs),
+		ProofsPublicInputs: proofsPublicInputs,
+		Proofs:             proofs,
+		AllowedCircuits:    allowedCircuits,
+	}
+}
+
+// Creates the witness of a MultiVerifierCircuit for a proof of allowedCircuits[selector].  The
+// slots of the other circuits are filled with all-zero proofs.
+func NewMultiVerifierWitness(
+	allowedCircuits []AllowedCircuit,
+	selector int,
+	proofWithPis variables.ProofWithPublicInputs,
+) MultiVerifierCircuit {
+	witness := NewMultiVerifierCircuit(allowedCircuits)
+	for i := range allowedCircuits {
+		if i == selector {
+			witness.ProofsPublicInputs[i] = proofWithPis.PublicInputs
+			witness.Proofs[i] = proofWithPis.Proof
+		} else {
+			zeroProofWithPis := variables.NewZeroProofWithPublicInputs(&allowedCircuits[i].CommonCircuitData)
+			witness.ProofsPublicInputs[i] = zeroProofWithPis.PublicInputs
+			witness.Proofs[i] = zeroProofWithPis.Proof
+		}
+	}
+
+	for i := range witness.PublicInputs {
+		witness.PublicInputs[i] = gl.Zero()
+		if i < len(proofWithPis.PublicInputs) {
+			witness.PublicInputs[i] = proofWithPis.PublicInputs[i]
+		}
+	}
+	witness.CircuitDigest = allowedCircuits[selector].VerifierOnlyCircuitData.CircuitDigest
+	witness.Selector = selector
+	return witness
+}
+
+func (c *MultiVerifierCircuit) Define(api frontend.API) error {
+	if len(c.AllowedCircuits) == 0 {
+		panic("MultiVerifierCircuit needs at least one allowed circuit")
+	}
+
+	circuitDigests := make([]frontend.Variable, len(c.AllowedCircuits))
+	for i, allowedCircuit := range c.AllowedCircuits {
+		selected := api.IsZero(api.Sub(c.Selector, i))
+		verifierChip, err := NewConditionalVerifierChip(api, allowedCircuit.CommonCircuitData, gates.DefaultRegistry, selected)
+		if err != nil {
+			return err
+		}
+		verifierChip.Verify(c.Proofs[i], c.ProofsPublicInputs[i], allowedCircuit.VerifierOnlyCircuitData)
+		circuitDigests[i] = allowedCircuit.VerifierOnlyCircuitData.CircuitDigest
+	}
+
+	// Mux also checks that the selector is one of the allowed circuits, so exactly one proof is
+	// checked.
+	api.AssertIsEqual(c.CircuitDigest, selector.Mux(api, c.Selector, circuitDigests...))
+
+	for j := range c.PublicInputs {
+		publicInputs := make([]frontend.Variable, len(c.AllowedCircuits))
+		for i := range c.AllowedCircuits {
+			publicInputs[i] = 0
+			if j < len(c.ProofsPublicInputs[i]) {
+				publicInputs[i] = c.ProofsPublicInputs[i][j].Limb
+			}
+		}
+		api.AssertIsEqual(c.PublicInputs[j].Limb, selector.Mux(api, c.Selector, publicInputs...))
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package verifier
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+	"github.com/succinctlabs/gnark-plonky2-verifier/variables"
+)
+
+// The number of Goldilocks public inputs that are packed into each BN254 element.  Like
+// poseidon.BN254Chip.HashNoPad, they're packed in base 2^64 with the first one in the least
+// significant limb, and 3 * 64 bits fit in the 254 bits of a BN254 element without wrapping around.
+const PUBLIC_INPUTS_PER_PACKED_ELEMENT = 3
+
+func init() {
+	solver.RegisterHint(UnpackPublicInputsHint)
+}
+
+// Returns the number of BN254 elements that numPublicInputs Goldilocks public inputs are packed
+// into.
+func NumPackedPublicInputs(numPublicInputs uint64) uint64 {
+	return (numPublicInputs + PUBLIC_INPUTS_PER_PACKED_ELEMENT - 1) / PUBLIC_INPUTS_PER_PACKED_ELEMENT
+}
+
+// Splits a packed element into its 64 bit limbs, least significant first.
+func UnpackPublicInputsHint(_ *big.Int, inputs []*big.Int, results []*big.Int) error {
+	if len(inputs) != 1 {
+		panic("UnpackPublicInputsHint expects 1 input operand")
+	}
+
+	packed := new(big.Int).Set(inputs[0])
+	mask := new(big.Int).SetUint64(^uint64(0))
+	for i := range results {
+		results[i].And(packed, mask)
+		packed.Rsh(packed, 64)
+	}
+	if packed.Sign() != 0 {
+		return fmt.Errorf("packed public input has more than %d limbs", len(results))
+	}
+
+	return nil
+}
+
+// Unpacks the public inputs of the plonky2 proof from their packed BN254 elements.  Every unpacked
+// public input is range checked to be a canonical Goldilocks element, so that each packed element
+// has a single unpacking.
+func (c *VerifierChip) UnpackPublicInputs(packedPublicInputs []frontend.Variable, numPublicInputs uint64) []gl.Variable {
+	if uint64(len(packedPublicInputs)) != NumPackedPublicInputs(numPublicInputs) {
+		panic("the number of packed public inputs doesn't match the number of public inputs")
+	}
+
+	publicInputs := make([]gl.Variable, 0, numPublicInputs)
+	for i, packed := range packedPublicInputs {
+		numLimbs := numPublicInputs - uint64(i)*PUBLIC_INPUTS_PER_PACKED_ELEMENT
+		if numLimbs > PUBLIC_INPUTS_PER_PACKED_ELEMENT {
+			numLimbs = PUBLIC_INPUTS_PER_PACKED_ELEMENT
+		}
+
+		limbs, err := c.api.Compiler().NewHint(UnpackPublicInputsHint, int(numLimbs), packed)
+		if err != nil {
+			panic(err)
+		}
+
+		repacked := frontend.Variable(0)
+		for j := len(limbs) - 1; j >= 0; j-- {
+			publicInput := gl.NewVariable(limbs[j])
+			c.glChip.RangeCheck(publicInput)
+			repacked = c.api.Add(c.api.Mul(repacked, new(big.Int).Lsh(big.NewInt(1), 64)), publicInput.Limb)
+		}
+		c.api.AssertIsEqual(repacked, packed)
+
+		for _, limb := range limbs {
+			publicInputs = append(publicInputs, gl.NewVariable(limb))
+		}
+	}
+
+	return publicInputs
+}
+
+// Packs the public inputs of a plonky2 proof into BN254 elements, the way
+// VerifierChip.UnpackPublicInputs expects them.
+func PackPublicInputs(publicInputs []uint64) []*big.Int {
+	packedPublicInputs := make([]*big.Int, NumPackedPublicInputs(uint64(len(publicInputs))))
+	for i := range packedPublicInputs {
+		end := (i + 1) * PUBLIC_INPUTS_PER_PACKED_ELEMENT
+		if end > len(publicInputs) {
+			end = len(publicInputs)
+		}
+
+		packed := new(big.Int)
+		for j := end - 1; j >= i*PUBLIC_INPUTS_PER_PACKED_ELEMENT; j-- {
+			packed.Lsh(packed, 64)
+			packed.Or(packed, new(big.Int).SetUint64(publicInputs[j]))
+		}
+		packedPublicInputs[i] = packed
+	}
+	return packedPublicInputs
+}
+
+// The inverse of PackPublicInputs.  Returns an error if the packed elements don't hold exactly
+// numPublicInputs canonical Goldilocks elements.
+func UnpackPublicInputs(packedPublicInputs []*big.Int, numPublicInputs uint64) ([]uint64, error) {
+	if uint64(len(packedPublicInputs)) != NumPackedPublicInputs(numPublicInputs) {
+		return nil, fmt.Errorf(
+			"expected %d packed public inputs for %d public inputs, got %d",
+			NumPackedPublicInputs(numPublicInputs),
+			numPublicInputs,
+			len(packedPublicInputs),
+		)
+	}
+
+	publicInputs := make([]uint64, 0, numPublicInputs)
+	for i, packed := range packedPublicInputs {
+		if packed.Sign() < 0 {
+			return nil, fmt.Errorf("packed public input %d is negative", i)
+		}
+
+		numLimbs := numPublicInputs - uint64(i)*PUBLIC_INPUTS_PER_PACKED_ELEMENT
+		if numLimbs > PUBLIC_INPUTS_PER_PACKED_ELEMENT {
+			numLimbs = PUBLIC_INPUTS_PER_PACKED_ELEMENT
+		}
+
+		limbs := make([]*big.Int, numLimbs)
+		for j := range limbs {
+			limbs[j] = new(big.Int)
+		}
+		if err := UnpackPublicInputsHint(nil, []*big.Int{packed}, limbs); err != nil {
+			return nil, fmt.Errorf("packed public input %d: %w", i, err)
+		}
+
+		for j, limb := range limbs {
+			if limb.Cmp(gl.MODULUS) >= 0 {
+				return nil, fmt.Errorf("public input %d is not a canonical goldilocks element", uint64(i)*PUBLIC_INPUTS_PER_PACKED_ELEMENT+uint64(j))
+			}
+			publicInputs = append(publicInputs, limb.Uint64())
+		}
+	}
+
+	return publicInputs, nil
+}
+
+// Like ExampleVerifierCircuit, but the public inputs are packed, PUBLIC_INPUTS_PER_PACKED_ELEMENT at
+// a time, into the public inputs of the gnark circuit (see PackPublicInputs).
+type PackedPublicInputsVerifierCircuit struct {
+	PackedPublicInputs      []frontend.Variable `gnark:",public"`
+	Proof                   variables.Proof
+	VerifierOnlyCircuitData variables.VerifierOnlyCircuitData `gnark:"-"`
+
+	// This is configuration for the circuit, it is a constant not a variable
+	CommonCircuitData types.CommonCircuitData
+}
+
+// Creates the placeholder PackedPublicInputsVerifierCircuit that should be compiled.
+func NewPackedPublicInputsVerifierCircuit(
+	verifierOnlyCircuitData variables.VerifierOnlyCircuitData,
+	commonCircuitData types.CommonCircuitData,
+) PackedPublicInputsVerifierCircuit {
+	return PackedPublicInputsVerifierCircuit{
+		PackedPublicInputs:      make([]frontend.Variable, NumPackedPublicInputs(commonCircuitData.NumPublicInputs)),
+		Proof:                   variables.NewProof(&commonCircuitData),
+		VerifierOnlyCircuitData: verifierOnlyCircuitData,
+		CommonCircuitData:       commonCircuitData,
+	}
+}
+
+func (c *PackedPublicInputsVerifierCircuit) Define(api frontend.API) error {
+	verifierChip := NewVerifierChip(api, c.CommonCircuitData)
+	publicInputs := verifierChip.UnpackPublicInputs(c.PackedPublicInputs, c.CommonCircuitData.NumPublicInputs)
+	verifierChip.Verify(c.Proof, publicInputs, c.VerifierOnlyCircuitData)
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package verifier
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/frontend"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+	"github.com/succinctlabs/gnark-plonky2-verifier/variables"
+)
+
+// Packs the Poseidon Goldilocks hash of the public inputs into a single BN254 element, in base 2^64
+// with the first element in the least significant limb.  The 256 bits of the hash don't fit in a
+// BN254 element, so the packing wraps around the BN254 scalar field r.  A contract that recomputes
+// the packed hash from the four hash elements h[0..3] has to reduce it the same way:
+//
+//	packed = (h[3] << 192 | h[2] << 128 | h[1] << 64 | h[0]) % r
+//
+// where the shifted value fits in a uint256, and
+// r = 21888242871839275222246405745257275088548364400416034343698204186575808495617.  At most 6
+// hashes pack to the same value, which doesn't make collisions meaningfully easier to find.
+//
+// The elements of the hash are canonical Goldilocks elements, since they come out of
+// poseidon.GoldilocksChip.
+func (c *VerifierChip) PackPublicInputsHash(publicInputsHash poseidon.GoldilocksHashOut) frontend.Variable {
+	packed := frontend.Variable(0)
+	for i := len(publicInputsHash) - 1; i >= 0; i-- {
+		packed = c.api.MulAcc(publicInputsHash[i].Limb, packed, new(big.Int).Lsh(big.NewInt(1), 64))
+	}
+	return packed
+}
+
+// Computes the packed hash of the public inputs that VerifierChip.PackPublicInputsHash returns,
+// e.g. to fill in the witness of a PublicInputsHashVerifierCircuit.
+func ComputePackedPublicInputsHash(publicInputs []uint64) *big.Int {
+	elements := make([]goldilocks.Element, len(publicInputs))
+	for i, publicInput := range publicInputs {
+		elements[i] = goldilocks.NewElement(publicInput)
+	}
+	publicInputsHash := poseidon.NewGoldilocksHasher().HashNoPad(elements)
+
+	packed := new(big.Int)
+	for i := len(publicInputsHash) - 1; i >= 0; i-- {
+		packed.Lsh(packed, 64)
+		packed.Add(packed, new(big.Int).SetUint64(publicInputsHash[i].Uint64()))
+	}
+	return packed.Mod(packed, ecc.BN254.ScalarField())
+}
+
+// Like ExampleVerifierCircuit, but the only public input of the gnark circuit is the packed hash of
+// the plonky2 public inputs (see VerifierChip.PackPublicInputsHash), and the plonky2 public inputs
+// are private.  This keeps the cost of verifying the gnark proof constant however many public
+// inputs the plonky2 circuit has.
+type PublicInputsHashVerifierCircuit struct {
+	PublicInputsHash        frontend.Variable `gnark:",public"`
+	PublicInputs            []gl.Variable
+	Proof                   variables.Proof
+	VerifierOnlyCircuitData variables.VerifierOnlyCircuitData `gnark:"-"`
+
+	// This is configuration for the circuit, it is a constant not a variable
+	CommonCircuitData types.CommonCircuitData
+}
+
+// Creates the placeholder PublicInputsHashVerifierCircuit that should be compiled.
+func NewPublicInputsHashVerifierCircuit(
+	verifierOnlyCircuitData variables.VerifierOnlyCircuitData,
+	commonCircuitData types.CommonCircuitData,
+) PublicInputsHashVerifierCircuit {
+	return PublicInputsHashVerifierCircuit{
+		PublicInputs:            make([]gl.Variable, commonCircuitData.NumPublicInputs),
+		Proof:                   variables.NewProof(&commonCircuitData),
+		VerifierOnlyCircuitData: verifierOnlyCircuitData,
+		CommonCircuitData:       commonCircuitData,
+	}
+}
+
+func (c *PublicInputsHashVerifierCircuit) Define(api frontend.API) error {
+	verifierChip := NewVerifierChip(api, c.CommonCircuitData)
+	publicInputsHash := verifierChip.GetPublicInputsHash(c.PublicInputs)
+	verifierChip.VerifyWithPublicInputsHash(c.Proof, publicInputsHash, c.VerifierOnlyCircuitData)
+	api.AssertIsEqual(verifierChip.PackPublicInputsHash(publicInputsHash), c.PublicInputsHash)
+
+	return nil
+}
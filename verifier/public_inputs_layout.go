@@ -0,0 +1,74 @@
+package verifier
+
+import (
+	"fmt"
+	"math/big"
+
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+)
+
+// The bit width of the public inputs that are only checked to be canonical Goldilocks elements.
+const CANONICAL_PUBLIC_INPUT_BITS = 64
+
+// The commit based range checker can only check widths that are a multiple of this.
+const rangeCheckAlignment = 16
+
+// The bit width that each public input is range checked to by VerifierChip.RangeCheckPublicInputs.
+// A width of CANONICAL_PUBLIC_INPUT_BITS only checks that the public input is a canonical
+// Goldilocks element.
+type PublicInputsLayout []uint64
+
+// Returns a layout in which all of the numPublicInputs public inputs are canonical Goldilocks
+// elements.
+func NewPublicInputsLayout(numPublicInputs uint64) PublicInputsLayout {
+	layout := make(PublicInputsLayout, numPublicInputs)
+	for i := range layout {
+		layout[i] = CANONICAL_PUBLIC_INPUT_BITS
+	}
+	return layout
+}
+
+// Returns a copy of the layout in which the public inputs [start, end) are bits wide.  For
+// example, a step proof's first 32 public inputs are bytes:
+//
+//	NewPublicInputsLayout(36).WithBitWidth(0, 32, 8)
+func (l PublicInputsLayout) WithBitWidth(start uint64, end uint64, bits uint64) PublicInputsLayout {
+	if start > end || end > uint64(len(l)) {
+		panic(fmt.Sprintf("[%d, %d) is not a range of the %d public inputs", start, end, len(l)))
+	}
+	if bits > CANONICAL_PUBLIC_INPUT_BITS {
+		panic(fmt.Sprintf("public inputs can't be wider than %d bits", CANONICAL_PUBLIC_INPUT_BITS))
+	}
+
+	layout := append(PublicInputsLayout{}, l...)
+	for i := start; i < end; i++ {
+		layout[i] = bits
+	}
+	return layout
+}
+
+// Checks that each public input fits in its bit width in the layout, so that the caller of the
+// verifier doesn't have to.
+func (c *VerifierChip) RangeCheckPublicInputs(publicInputs []gl.Variable, layout PublicInputsLayout) {
+	if len(publicInputs) != len(layout) {
+		panic(fmt.Sprintf("the public inputs layout has %d entries for %d public inputs", len(layout), len(publicInputs)))
+	}
+
+	for i, publicInput := range publicInputs {
+		bits := layout[i]
+		c.glChip.RangeCheck(publicInput)
+		if bits >= CANONICAL_PUBLIC_INPUT_BITS {
+			continue
+		}
+		if bits == 0 {
+			c.api.AssertIsEqual(publicInput.Limb, 0)
+			continue
+		}
+
+		// The public input is less than 2^64, so shifting it left by less than rangeCheckAlignment
+		// bits can't wrap around, and x < 2^bits iff x * 2^shift < 2^(bits + shift).
+		shift := (rangeCheckAlignment - bits%rangeCheckAlignment) % rangeCheckAlignment
+		shifted := c.api.Mul(publicInput.Limb, new(big.Int).Lsh(big.NewInt(1), uint(shift)))
+		c.glChip.RangeCheckWithMaxBits(gl.NewVariable(shifted), bits+shift)
+	}
+}
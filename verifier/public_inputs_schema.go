@@ -0,0 +1,303 @@
+package verifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
+)
+
+// The types of the fields of a PublicInputsSchema.  A type followed by "[]" is an array of
+// PublicInputField.Len values of that type, e.g. "u32[]".
+const (
+	// A Goldilocks element, in one public input.
+	GOLDILOCKS_FIELD = "goldilocks"
+	// A byte, in one public input.
+	U8_FIELD = "u8"
+	// A u32 word, in one public input.
+	U32_FIELD = "u32"
+	// 32 bytes, one per public input, e.g. a keccak256 or sha256 digest.
+	BYTES32_FIELD = "bytes32"
+	// A Goldilocks Poseidon hash, in POSEIDON_GL_HASH_SIZE public inputs.
+	GOLDILOCKS_HASH_FIELD = "goldilocks_hash"
+)
+
+const arrayFieldSuffix = "[]"
+
+// The number of public inputs that a value of each field type takes, and the bit width that each
+// of them is range checked to.
+var publicInputFieldTypes = map[string]struct {
+	numPublicInputs uint64
+	bits            uint64
+}{
+	GOLDILOCKS_FIELD:      {1, CANONICAL_PUBLIC_INPUT_BITS},
+	U8_FIELD:              {1, 8},
+	U32_FIELD:             {1, 32},
+	BYTES32_FIELD:         {32, 8},
+	GOLDILOCKS_HASH_FIELD: {poseidon.POSEIDON_GL_HASH_SIZE, CANONICAL_PUBLIC_INPUT_BITS},
+}
+
+type PublicInputField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// The number of values of an array field.  Must be left out for the other fields.
+	Len uint64 `json:"len,omitempty"`
+}
+
+// Returns the type of the field's values, and whether the field is an array of them.
+func (f PublicInputField) elementType() (string, bool) {
+	if strings.HasSuffix(f.Type, arrayFieldSuffix) {
+		return strings.TrimSuffix(f.Type, arrayFieldSuffix), true
+	}
+	return f.Type, false
+}
+
+func (f PublicInputField) numValues() uint64 {
+	if _, isArray := f.elementType(); isArray {
+		return f.Len
+	}
+	return 1
+}
+
+func (f PublicInputField) validate() error {
+	if f.Name == "" {
+		return fmt.Errorf("public input field of type %q has no name", f.Type)
+	}
+
+	elementType, isArray := f.elementType()
+	if _, ok := publicInputFieldTypes[elementType]; !ok {
+		return fmt.Errorf("public input field %s has unknown type %q", f.Name, f.Type)
+	}
+	if isArray && f.Len == 0 {
+		return fmt.Errorf("public input field %s is an array without a len", f.Name)
+	}
+	if !isArray && f.Len != 0 {
+		return fmt.Errorf("public input field %s has a len but isn't an array", f.Name)
+	}
+
+	return nil
+}
+
+// Describes how a plonky2 circuit lays out values in its public inputs: the fields take up
+// consecutive public inputs, in order.  It's usually read from JSON such as
+//
+//	{"fields": [
+//		{"name": "block_hash", "type": "bytes32"},
+//		{"name": "words", "type": "u32[]", "len": 4},
+//		{"name": "state_root", "type": "goldilocks_hash"}
+//	]}
+type PublicInputsSchema struct {
+	Fields []PublicInputField `json:"fields"`
+}
+
+// Reads a PublicInputsSchema serialized as JSON.  Panics if the file can't be read or parsed, see
+// ParsePublicInputsSchema for a version that returns an error.
+func ReadPublicInputsSchema(path string) PublicInputsSchema {
+	jsonFile, err := os.Open(path)
+	if err != nil {
+		panic(err)
+	}
+	defer jsonFile.Close()
+
+	schema, err := ParsePublicInputsSchema(jsonFile)
+	if err != nil {
+		panic(err)
+	}
+
+	return schema
+}
+
+func ParsePublicInputsSchema(r io.Reader) (PublicInputsSchema, error) {
+	var schema PublicInputsSchema
+
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&schema); err != nil {
+		return schema, fmt.Errorf("failed to parse public inputs schema: %w", err)
+	}
+
+	if err := schema.Validate(); err != nil {
+		return schema, err
+	}
+
+	return schema, nil
+}
+
+// Checks that the field types are known and that the field names are unique.
+func (s PublicInputsSchema) Validate() error {
+	names := make(map[string]bool, len(s.Fields))
+	for _, field := range s.Fields {
+		if err := field.validate(); err != nil {
+			return err
+		}
+		if names[field.Name] {
+			return fmt.Errorf("duplicate public input field %s", field.Name)
+		}
+		names[field.Name] = true
+	}
+	return nil
+}
+
+// Like Validate, but also checks that the fields take up exactly numPublicInputs public inputs, the
+// CommonCircuitData.NumPublicInputs of the circuit whose public inputs the schema describes.
+func (s PublicInputsSchema) ValidateFor(numPublicInputs uint64) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if s.NumPublicInputs() != numPublicInputs {
+		return fmt.Errorf(
+			"the public inputs schema's fields take up %d public inputs, but the circuit has %d",
+			s.NumPublicInputs(),
+			numPublicInputs,
+		)
+	}
+	return nil
+}
+
+func (s PublicInputsSchema) NumPublicInputs() uint64 {
+	numPublicInputs := uint64(0)
+	for _, field := range s.Fields {
+		elementType, _ := field.elementType()
+		numPublicInputs += field.numValues() * publicInputFieldTypes[elementType].numPublicInputs
+	}
+	return numPublicInputs
+}
+
+// Returns the bit widths that the fields' public inputs are range checked to, e.g. to set
+// ExampleVerifierCircuit.PublicInputsLayout.
+func (s PublicInputsSchema) Layout() PublicInputsLayout {
+	if err := s.Validate(); err != nil {
+		panic(err)
+	}
+
+	layout := NewPublicInputsLayout(s.NumPublicInputs())
+	start := uint64(0)
+	for _, field := range s.Fields {
+		elementType, _ := field.elementType()
+		end := start + field.numValues()*publicInputFieldTypes[elementType].numPublicInputs
+		layout = layout.WithBitWidth(start, end, publicInputFieldTypes[elementType].bits)
+		start = end
+	}
+	return layout
+}
+
+// The public inputs of a proof, split into the fields of a PublicInputsSchema.  The accessors
+// panic if the schema doesn't have a field with that name and type, which happens while the
+// circuit is being defined.
+type DecodedPublicInputs struct {
+	fields map[string]decodedPublicInputField
+}
+
+type decodedPublicInputField struct {
+	fieldType    string
+	publicInputs []gl.Variable
+}
+
+// Range checks the public inputs against the schema and splits them into its fields, so that
+// constraints on them can be written against the fields' names.  The schema must cover every one of
+// the public inputs, otherwise the error of ValidateFor is returned.
+func (c *VerifierChip) DecodePublicInputs(publicInputs []gl.Variable, schema PublicInputsSchema) (DecodedPublicInputs, error) {
+	if err := schema.ValidateFor(uint64(len(publicInputs))); err != nil {
+		return DecodedPublicInputs{}, err
+	}
+	c.RangeCheckPublicInputs(publicInputs, schema.Layout())
+
+	decoded := DecodedPublicInputs{fields: make(map[string]decodedPublicInputField, len(schema.Fields))}
+	start := uint64(0)
+	for _, field := range schema.Fields {
+		elementType, _ := field.elementType()
+		end := start + field.numValues()*publicInputFieldTypes[elementType].numPublicInputs
+		decoded.fields[field.Name] = decodedPublicInputField{
+			fieldType:    field.Type,
+			publicInputs: publicInputs[start:end],
+		}
+		start = end
+	}
+	return decoded, nil
+}
+
+func (d DecodedPublicInputs) field(name string, fieldType string) []gl.Variable {
+	field, ok := d.fields[name]
+	if !ok {
+		panic(fmt.Sprintf("no public input field %s", name))
+	}
+	if field.fieldType != fieldType {
+		panic(fmt.Sprintf("public input field %s has type %s, not %s", name, field.fieldType, fieldType))
+	}
+	return field.publicInputs
+}
+
+func limbs(publicInputs []gl.Variable) []frontend.Variable {
+	values := make([]frontend.Variable, len(publicInputs))
+	for i, publicInput := range publicInputs {
+		values[i] = publicInput.Limb
+	}
+	return values
+}
+
+func (d DecodedPublicInputs) Goldilocks(name string) gl.Variable {
+	return d.field(name, GOLDILOCKS_FIELD)[0]
+}
+
+func (d DecodedPublicInputs) GoldilocksArray(name string) []gl.Variable {
+	return d.field(name, GOLDILOCKS_FIELD+arrayFieldSuffix)
+}
+
+func (d DecodedPublicInputs) U8(name string) frontend.Variable {
+	return d.field(name, U8_FIELD)[0].Limb
+}
+
+func (d DecodedPublicInputs) U8Array(name string) []frontend.Variable {
+	return limbs(d.field(name, U8_FIELD+arrayFieldSuffix))
+}
+
+func (d DecodedPublicInputs) U32(name string) frontend.Variable {
+	return d.field(name, U32_FIELD)[0].Limb
+}
+
+func (d DecodedPublicInputs) U32Array(name string) []frontend.Variable {
+	return limbs(d.field(name, U32_FIELD+arrayFieldSuffix))
+}
+
+func toBytes32(publicInputs []gl.Variable) [32]frontend.Variable {
+	var bytes [32]frontend.Variable
+	copy(bytes[:], limbs(publicInputs))
+	return bytes
+}
+
+func (d DecodedPublicInputs) Bytes32(name string) [32]frontend.Variable {
+	return toBytes32(d.field(name, BYTES32_FIELD))
+}
+
+func (d DecodedPublicInputs) Bytes32Array(name string) [][32]frontend.Variable {
+	publicInputs := d.field(name, BYTES32_FIELD+arrayFieldSuffix)
+	values := make([][32]frontend.Variable, len(publicInputs)/32)
+	for i := range values {
+		values[i] = toBytes32(publicInputs[32*i : 32*(i+1)])
+	}
+	return values
+}
+
+func toGoldilocksHash(publicInputs []gl.Variable) poseidon.GoldilocksHashOut {
+	var hash poseidon.GoldilocksHashOut
+	copy(hash[:], publicInputs)
+	return hash
+}
+
+func (d DecodedPublicInputs) GoldilocksHash(name string) poseidon.GoldilocksHashOut {
+	return toGoldilocksHash(d.field(name, GOLDILOCKS_HASH_FIELD))
+}
+
+func (d DecodedPublicInputs) GoldilocksHashArray(name string) []poseidon.GoldilocksHashOut {
+	publicInputs := d.field(name, GOLDILOCKS_HASH_FIELD+arrayFieldSuffix)
+	values := make([]poseidon.GoldilocksHashOut, len(publicInputs)/poseidon.POSEIDON_GL_HASH_SIZE)
+	for i := range values {
+		values[i] = toGoldilocksHash(publicInputs[poseidon.POSEIDON_GL_HASH_SIZE*i : poseidon.POSEIDON_GL_HASH_SIZE*(i+1)])
+	}
+	return values
+}
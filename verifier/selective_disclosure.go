@@ -0,0 +1,143 @@
+package verifier
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/frontend"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+	"github.com/succinctlabs/gnark-plonky2-verifier/variables"
+)
+
+// Returns the commitment to the public inputs that aren't disclosed, i.e. the Poseidon BN254 hash
+// TwoToOne(HashNoPad(privatePublicInputs), blinding).  The blinding keeps public inputs with few
+// possible values from being found by hashing all of them, so it should be random and kept
+// private.
+//
+// The private public inputs are range checked to be canonical Goldilocks elements, so that the
+// commitment can only be opened to the values that the plonky2 proof is for.
+func (c *VerifierChip) GetPrivatePublicInputsCommitment(
+	privatePublicInputs []gl.Variable,
+	blinding frontend.Variable,
+) poseidon.BN254HashOut {
+	for _, publicInput := range privatePublicInputs {
+		c.glChip.RangeCheck(publicInput)
+	}
+	return c.poseidonBN254Chip.TwoToOne(c.poseidonBN254Chip.HashNoPad(privatePublicInputs), blinding)
+}
+
+// The out of circuit counterpart of VerifierChip.GetPrivatePublicInputsCommitment.
+func ComputePrivatePublicInputsCommitment(privatePublicInputs []uint64, blinding *big.Int) *big.Int {
+	elements := make([]goldilocks.Element, len(privatePublicInputs))
+	for i, publicInput := range privatePublicInputs {
+		elements[i] = goldilocks.NewElement(publicInput)
+	}
+
+	var blindingElement fr.Element
+	blindingElement.SetBigInt(blinding)
+
+	hasher := poseidon.NewBN254Hasher()
+	commitment := hasher.TwoToOne(hasher.HashNoPad(elements), blindingElement)
+	return commitment.BigInt(new(big.Int))
+}
+
+// Splits the public inputs of a plonky2 proof into the disclosed ones and the private ones, in
+// order, the way a SelectiveDisclosureVerifierCircuit with this mask expects them.
+func SplitPublicInputs(publicInputs []uint64, disclosed []bool) ([]uint64, []uint64) {
+	if len(publicInputs) != len(disclosed) {
+		panic(fmt.Sprintf("the disclosure mask has %d entries for %d public inputs", len(disclosed), len(publicInputs)))
+	}
+
+	disclosedPublicInputs := []uint64{}
+	privatePublicInputs := []uint64{}
+	for i, publicInput := range publicInputs {
+		if disclosed[i] {
+			disclosedPublicInputs = append(disclosedPublicInputs, publicInput)
+		} else {
+			privatePublicInputs = append(privatePublicInputs, publicInput)
+		}
+	}
+	return disclosedPublicInputs, privatePublicInputs
+}
+
+// Like ExampleVerifierCircuit, but only the public inputs that Disclosed is set for are public
+// inputs of the gnark circuit.  The others are private, and the gnark circuit exposes a binding
+// and hiding commitment to them instead (see VerifierChip.GetPrivatePublicInputsCommitment).
+type SelectiveDisclosureVerifierCircuit struct {
+	DisclosedPublicInputs         []gl.Variable     `gnark:",public"`
+	PrivatePublicInputsCommitment frontend.Variable `gnark:",public"`
+	PrivatePublicInputs           []gl.Variable
+	Blinding                      frontend.Variable
+	Proof                         variables.Proof
+	VerifierOnlyCircuitData       variables.VerifierOnlyCircuitData `gnark:"-"`
+
+	// Whether each of the plonky2 public inputs is disclosed.  Like the common circuit data, it's a
+	// constant of the circuit.
+	Disclosed []bool `gnark:"-"`
+
+	// This is configuration for the circuit, it is a constant not a variable
+	CommonCircuitData types.CommonCircuitData
+}
+
+// Creates the placeholder SelectiveDisclosureVerifierCircuit that should be compiled, in which
+// the public inputs that disclosed is set for are public.
+func NewSelectiveDisclosureVerifierCircuit(
+	verifierOnlyCircuitData variables.VerifierOnlyCircuitData,
+	commonCircuitData types.CommonCircuitData,
+	disclosed []bool,
+) SelectiveDisclosureVerifierCircuit {
+	if uint64(len(disclosed)) != commonCircuitData.NumPublicInputs {
+		panic(fmt.Sprintf(
+			"the disclosure mask has %d entries for %d public inputs",
+			len(disclosed),
+			commonCircuitData.NumPublicInputs,
+		))
+	}
+
+	numDisclosed := 0
+	for _, isDisclosed := range disclosed {
+		if isDisclosed {
+			numDisclosed++
+		}
+	}
+
+	return SelectiveDisclosureVerifierCircuit{
+		DisclosedPublicInputs:   make([]gl.Variable, numDisclosed),
+		PrivatePublicInputs:     make([]gl.Variable, len(disclosed)-numDisclosed),
+		Proof:                   variables.NewProof(&commonCircuitData),
+		VerifierOnlyCircuitData: verifierOnlyCircuitData,
+		Disclosed:               append([]bool{}, disclosed...),
+		CommonCircuitData:       commonCircuitData,
+	}
+}
+
+func (c *SelectiveDisclosureVerifierCircuit) Define(api frontend.API) error {
+	if len(c.Disclosed) != len(c.DisclosedPublicInputs)+len(c.PrivatePublicInputs) {
+		panic("the disclosure mask doesn't match the number of public inputs")
+	}
+
+	// Put the public inputs back in the order of the plonky2 circuit.
+	publicInputs := make([]gl.Variable, len(c.Disclosed))
+	disclosedIdx, privateIdx := 0, 0
+	for i, isDisclosed := range c.Disclosed {
+		if isDisclosed {
+			publicInputs[i] = c.DisclosedPublicInputs[disclosedIdx]
+			disclosedIdx++
+		} else {
+			publicInputs[i] = c.PrivatePublicInputs[privateIdx]
+			privateIdx++
+		}
+	}
+
+	verifierChip := NewVerifierChip(api, c.CommonCircuitData)
+	verifierChip.Verify(c.Proof, publicInputs, c.VerifierOnlyCircuitData)
+
+	commitment := verifierChip.GetPrivatePublicInputsCommitment(c.PrivatePublicInputs, c.Blinding)
+	api.AssertIsEqual(commitment, c.PrivatePublicInputsCommitment)
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package verifier
+
+import (
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/frontend"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
+	"github.com/succinctlabs/gnark-plonky2-verifier/types"
+	"github.com/succinctlabs/gnark-plonky2-verifier/variables"
+)
+
+// The plonky2 hash rate that hash_pad pads its input to a multiple of, for the Poseidon BN254
+// hasher.
+const circuitDigestPadRate = 9
+
+// Returns the digest of the default (empty) domain separator, hash_pad([]) in plonky2, as the
+// goldilocks elements that go into the circuit digest.
+func domainSeparatorDigest() []goldilocks.Element {
+	padded := make([]goldilocks.Element, circuitDigestPadRate)
+	padded[0].SetOne()
+	padded[circuitDigestPadRate-1].SetOne()
+
+	hasher := poseidon.NewBN254Hasher()
+	return hasher.ToVec(hasher.HashNoPad(padded))
+}
+
+// Recomputes the circuit digest that plonky2 derives from the constants and sigmas cap when it
+// builds a circuit: the hash of the flattened cap, of the domain separator's digest and of the
+// degree bits.  Only circuits that were built with the default domain separator are supported.
+func (c *VerifierChip) GetCircuitDigest(constantSigmasCap variables.FriMerkleCap) poseidon.BN254HashOut {
+	inputs := []gl.Variable{}
+	for _, capHash := range constantSigmasCap {
+		inputs = append(inputs, c.poseidonBN254Chip.ToVec(capHash)...)
+	}
+	for _, element := range domainSeparatorDigest() {
+		inputs = append(inputs, gl.NewVariable(element.Uint64()))
+	}
+	inputs = append(inputs, gl.NewVariable(c.commonData.DegreeBits))
+
+	return c.poseidonBN254Chip.HashNoPad(inputs)
+}
+
+// Like Verify, but the verifier only data is a witness rather than a constant of the circuit.  The
+// circuit digest is checked against the constants and sigmas cap, so that it identifies the
+// plonky2 circuit that the proof is for.
+func (c *VerifierChip) VerifyUniversal(
+	proof variables.Proof,
+	publicInputs []gl.Variable,
+	verifierData variables.VerifierOnlyCircuitData,
+) {
+	c.api.AssertIsEqual(c.GetCircuitDigest(verifierData.ConstantSigmasCap), verifierData.CircuitDigest)
+	c.Verify(proof, publicInputs, verifierData)
+}
+
+// Verifies a proof of any plonky2 circuit with the given common circuit data.  The constants and
+// sigmas cap is a witness and the circuit digest is a public input, so one trusted setup (and one
+// Solidity verifier) serves every plonky2 circuit that shares the common data, and the caller
+// decides which of them to accept by checking the digest.
+type UniversalVerifierCircuit struct {
+	CircuitDigest     frontend.Variable `gnark:",public"`
+	PublicInputs      []gl.Variable     `gnark:",public"`
+	ConstantSigmasCap variables.FriMerkleCap
+	Proof             variables.Proof
+
+	// This is configuration for the circuit, it is a constant not a variable
+	CommonCircuitData types.CommonCircuitData
+}
+
+// Creates the placeholder UniversalVerifierCircuit that should be compiled.  Unlike
+// NewExampleVerifierCircuit, it doesn't take the verifier only data.
+func NewUniversalVerifierCircuit(commonCircuitData types.CommonCircuitData) UniversalVerifierCircuit {
+	proofWithPis := variables.NewProofWithPublicInputs(&commonCircuitData)
+	return UniversalVerifierCircuit{
+		PublicInputs:      proofWithPis.PublicInputs,
+		ConstantSigmasCap: variables.NewFriMerkleCap(commonCircuitData.Config.FriConfig.CapHeight),
+		Proof:             proofWithPis.Proof,
+		CommonCircuitData: commonCircuitData,
+	}
+}
+
+func (c *UniversalVerifierCircuit) Define(api frontend.API) error {
+	verifierChip := NewVerifierChip(api, c.CommonCircuitData)
+	verifierChip.VerifyUniversal(c.Proof, c.PublicInputs, variables.VerifierOnlyCircuitData{
+		ConstantSigmasCap: c.ConstantSigmasCap,
+		CircuitDigest:     c.CircuitDigest,
+	})
+
+	return nil
+}
@@ -8,16 +8,40 @@ import (
 )
 
 type ExampleVerifierCircuit struct {
-	PublicInputs            []gl.Variable                     `gnark:",public"`
-	Proof                   variables.Proof                   `gnark:"-"`
+	PublicInputs            []gl.Variable `gnark:",public"`
+	Proof                   variables.Proof
 	VerifierOnlyCircuitData variables.VerifierOnlyCircuitData `gnark:"-"`
 
 	// This is configuration for the circuit, it is a constant not a variable
 	CommonCircuitData types.CommonCircuitData
+
+	// If set, the public inputs are range checked in the circuit according to the layout.  Otherwise
+	// the caller must range check them.
+	PublicInputsLayout PublicInputsLayout `gnark:"-"`
+}
+
+// Creates the placeholder ExampleVerifierCircuit that should be compiled.  The public inputs and
+// the proof are allocated from commonCircuitData only, so the compiled circuit (and its proving key)
+// can be used to prove any proof of the plonky2 circuit described by commonCircuitData and
+// verifierOnlyCircuitData.  The verifier only data is still a constant within the circuit.
+func NewExampleVerifierCircuit(
+	verifierOnlyCircuitData variables.VerifierOnlyCircuitData,
+	commonCircuitData types.CommonCircuitData,
+) ExampleVerifierCircuit {
+	proofWithPis := variables.NewProofWithPublicInputs(&commonCircuitData)
+	return ExampleVerifierCircuit{
+		PublicInputs:            proofWithPis.PublicInputs,
+		Proof:                   proofWithPis.Proof,
+		VerifierOnlyCircuitData: verifierOnlyCircuitData,
+		CommonCircuitData:       commonCircuitData,
+	}
 }
 
 func (c *ExampleVerifierCircuit) Define(api frontend.API) error {
 	verifierChip := NewVerifierChip(api, c.CommonCircuitData)
+	if c.PublicInputsLayout != nil {
+		verifierChip.RangeCheckPublicInputs(c.PublicInputs, c.PublicInputsLayout)
+	}
 	verifierChip.Verify(c.Proof, c.PublicInputs, c.VerifierOnlyCircuitData)
 
 	return nil
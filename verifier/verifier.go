@@ -6,6 +6,7 @@ import (
 	"github.com/succinctlabs/gnark-plonky2-verifier/fri"
 	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
 	"github.com/succinctlabs/gnark-plonky2-verifier/plonk"
+	"github.com/succinctlabs/gnark-plonky2-verifier/plonk/gates"
 	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
 	"github.com/succinctlabs/gnark-plonky2-verifier/types"
 	"github.com/succinctlabs/gnark-plonky2-verifier/variables"
@@ -21,10 +22,56 @@ type VerifierChip struct {
 	commonData        types.CommonCircuitData  `gnark:"-"`
 }
 
+// Panics if one of the circuit's gate IDs is unknown or ambiguous.  See
+// NewVerifierChipWithRegistry for a version that returns an error.
 func NewVerifierChip(api frontend.API, commonCircuitData types.CommonCircuitData) *VerifierChip {
-	glChip := gl.New(api)
+	c, err := NewVerifierChipWithRegistry(api, commonCircuitData, gates.DefaultRegistry)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Like NewVerifierChip, but looks up the circuit's gates in the given registry, which may contain
+// custom gates, and returns an error when one of them isn't found.
+func NewVerifierChipWithRegistry(
+	api frontend.API,
+	commonCircuitData types.CommonCircuitData,
+	registry *gates.Registry,
+) (*VerifierChip, error) {
 	friChip := fri.NewChip(api, &commonCircuitData, &commonCircuitData.FriParams)
-	plonkChip := plonk.NewPlonkChip(api, commonCircuitData)
+	plonkChip, err := plonk.NewPlonkChipWithRegistry(api, commonCircuitData, registry)
+	if err != nil {
+		return nil, err
+	}
+	return newVerifierChip(api, commonCircuitData, plonkChip, friChip), nil
+}
+
+// Like NewVerifierChipWithRegistry, but the proofs are only checked when enabled is 1, so that a
+// proof doesn't have to be valid when enabled is 0.  enabled must be 0 or 1.  The proof's values
+// are still range checked and hashed, so they must be canonical even when enabled is 0, which an
+// all-zero proof is.
+func NewConditionalVerifierChip(
+	api frontend.API,
+	commonCircuitData types.CommonCircuitData,
+	registry *gates.Registry,
+	enabled frontend.Variable,
+) (*VerifierChip, error) {
+	friChip := fri.NewConditionalChip(api, &commonCircuitData, &commonCircuitData.FriParams, enabled)
+	plonkChip, err := plonk.NewConditionalPlonkChip(api, commonCircuitData, registry, enabled)
+	if err != nil {
+		return nil, err
+	}
+	return newVerifierChip(api, commonCircuitData, plonkChip, friChip), nil
+}
+
+func newVerifierChip(
+	api frontend.API,
+	commonCircuitData types.CommonCircuitData,
+	plonkChip *plonk.PlonkChip,
+	friChip *fri.Chip,
+) *VerifierChip {
+	glChip := gl.New(api)
 	poseidonGlChip := poseidon.NewGoldilocksChip(api)
 	poseidonBN254Chip := poseidon.NewBN254Chip(api)
 	return &VerifierChip{
@@ -59,6 +106,15 @@ func (c *VerifierChip) GetChallenges(
 	plonkBetas := challenger.GetNChallenges(numChallenges)
 	plonkGammas := challenger.GetNChallenges(numChallenges)
 
+	// The lookup argument reuses the betas and gammas as its first challenges.
+	var plonkDeltas []gl.Variable
+	if c.commonData.NumLookupPolys != 0 {
+		plonkDeltas = append(plonkDeltas, plonkBetas...)
+		plonkDeltas = append(plonkDeltas, plonkGammas...)
+		plonkDeltas = append(plonkDeltas, challenger.GetNChallenges((gates.NUM_COINS_LOOKUP-2)*numChallenges)...)
+	}
+
+	// The zs/partial products cap also commits to the lookup polynomials.
 	challenger.ObserveCap(proof.PlonkZsPartialProductsCap)
 	plonkAlphas := challenger.GetNChallenges(numChallenges)
 
@@ -71,6 +127,7 @@ func (c *VerifierChip) GetChallenges(
 		PlonkBetas:  plonkBetas,
 		PlonkGammas: plonkGammas,
 		PlonkAlphas: plonkAlphas,
+		PlonkDeltas: plonkDeltas,
 		PlonkZeta:   plonkZeta,
 		FriChallenges: challenger.GetFriChallenges(
 			proof.OpeningProof.CommitPhaseMerkleCaps,
@@ -85,7 +142,7 @@ func (c *VerifierChip) rangeCheckProof(proof variables.Proof) {
 	// Need to verify the plonky2 proof's openings, openings proof (other than the sibling elements), fri's final poly, pow witness.
 
 	// Note that this is NOT range checking the public inputs (first 32 elements should be no more than 8 bits and the last 4 elements should be no more than 64 bits).  Since this is currently being inputted via the smart contract,
-	// we will assume that caller is doing that check.
+	// we will assume that caller is doing that check, unless the circuit uses RangeCheckPublicInputs with a PublicInputsLayout.
 
 	// Range check the proof's openings.
 	for _, constant := range proof.Openings.Constants {
@@ -116,6 +173,14 @@ func (c *VerifierChip) rangeCheckProof(proof variables.Proof) {
 		c.glChip.RangeCheckQE(quotientPoly)
 	}
 
+	for _, lookupZ := range proof.Openings.LookupZs {
+		c.glChip.RangeCheckQE(lookupZ)
+	}
+
+	for _, lookupZNext := range proof.Openings.LookupZsNext {
+		c.glChip.RangeCheckQE(lookupZNext)
+	}
+
 	// Range check the openings proof.
 	for _, queryRound := range proof.OpeningProof.QueryRoundProofs {
 		for _, evalsProof := range queryRound.InitialTreesProof.EvalsProofs {
@@ -144,11 +209,20 @@ func (c *VerifierChip) Verify(
 	proof variables.Proof,
 	publicInputs []gl.Variable,
 	verifierData variables.VerifierOnlyCircuitData,
+) {
+	c.VerifyWithPublicInputsHash(proof, c.GetPublicInputsHash(publicInputs), verifierData)
+}
+
+// Like Verify, but takes the hash of the public inputs, which is all that the plonky2 proof depends
+// on, instead of the public inputs themselves.
+func (c *VerifierChip) VerifyWithPublicInputsHash(
+	proof variables.Proof,
+	publicInputsHash poseidon.GoldilocksHashOut,
+	verifierData variables.VerifierOnlyCircuitData,
 ) {
 	c.rangeCheckProof(proof)
 
 	// Generate the parts of the witness that is for the plonky2 proof input
-	publicInputsHash := c.GetPublicInputsHash(publicInputs)
 	proofChallenges := c.GetChallenges(proof, publicInputsHash, verifierData)
 
 	c.plonkChip.Verify(proofChallenges, proof.Openings, publicInputsHash)
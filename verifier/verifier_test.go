@@ -1,41 +1,790 @@
 package verifier_test
 
 import (
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/test"
+	gl "github.com/succinctlabs/gnark-plonky2-verifier/goldilocks"
+	"github.com/succinctlabs/gnark-plonky2-verifier/poseidon"
 	"github.com/succinctlabs/gnark-plonky2-verifier/types"
 	"github.com/succinctlabs/gnark-plonky2-verifier/variables"
 	"github.com/succinctlabs/gnark-plonky2-verifier/verifier"
 )
 
+func deserializeProofWithPublicInputs(t *testing.T, raw types.ProofWithPublicInputsRaw) variables.ProofWithPublicInputs {
+	t.Helper()
+	proofWithPis, err := variables.DeserializeProofWithPublicInputs(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proofWithPis
+}
+
+func deserializeVerifierOnlyCircuitData(t *testing.T, raw types.VerifierOnlyCircuitDataRaw) variables.VerifierOnlyCircuitData {
+	t.Helper()
+	verifierOnlyCircuitData, err := variables.DeserializeVerifierOnlyCircuitData(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return verifierOnlyCircuitData
+}
+
+// A plonky2 circuit's data and proof from testdata.
+type fixture struct {
+	commonCircuitData       types.CommonCircuitData
+	rawProofWithPis         types.ProofWithPublicInputsRaw
+	proofWithPis            variables.ProofWithPublicInputs
+	verifierOnlyCircuitData variables.VerifierOnlyCircuitData
+}
+
+func readFixture(t *testing.T, plonky2Circuit string) fixture {
+	t.Helper()
+	rawProofWithPis := types.ReadProofWithPublicInputs("../testdata/" + plonky2Circuit + "/proof_with_public_inputs.json")
+	return fixture{
+		commonCircuitData:       types.ReadCommonCircuitData("../testdata/" + plonky2Circuit + "/common_circuit_data.json"),
+		rawProofWithPis:         rawProofWithPis,
+		proofWithPis:            deserializeProofWithPublicInputs(t, rawProofWithPis),
+		verifierOnlyCircuitData: deserializeVerifierOnlyCircuitData(t, types.ReadVerifierOnlyCircuitData("../testdata/"+plonky2Circuit+"/verifier_only_circuit_data.json")),
+	}
+}
+
+// Checks that the witness built from the fixture solves the circuit built from it.
+func solveWithFixture(
+	t *testing.T,
+	plonky2Circuit string,
+	circuitFn func(fixture) frontend.Circuit,
+	witnessFn func(fixture) frontend.Circuit,
+) {
+	t.Helper()
+	f := readFixture(t, plonky2Circuit)
+	if err := test.IsSolved(circuitFn(f), witnessFn(f), ecc.BN254.ScalarField()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// The tests of chips that are much smaller than a verifier call this, as the commit based range
+// checker can't pick its base width for circuits this small.
+func useBitDecompositionRangeCheck(t *testing.T) {
+	t.Setenv("USE_BIT_DECOMPOSITION_RANGE_CHECK", "true")
+}
+
+func newExampleVerifierCircuit(f fixture) frontend.Circuit {
+	circuit := verifier.NewExampleVerifierCircuit(f.verifierOnlyCircuitData, f.commonCircuitData)
+	return &circuit
+}
+
+func newExampleVerifierWitness(f fixture) frontend.Circuit {
+	return &verifier.ExampleVerifierCircuit{
+		Proof:                   f.proofWithPis.Proof,
+		PublicInputs:            f.proofWithPis.PublicInputs,
+		VerifierOnlyCircuitData: f.verifierOnlyCircuitData,
+		CommonCircuitData:       f.commonCircuitData,
+	}
+}
+
 func TestStepVerifier(t *testing.T) {
+	solveWithFixture(t, "step", newExampleVerifierCircuit, newExampleVerifierWitness)
+}
+
+func TestLookupVerifier(t *testing.T) {
+	if _, err := os.Stat("../testdata/lookup"); os.IsNotExist(err) {
+		t.Fatal("no lookup fixture, see testdata/lookup.md")
+	}
+
+	f := readFixture(t, "lookup")
+	if len(f.commonCircuitData.Luts) == 0 {
+		t.Fatal("the lookup fixture doesn't have any lookup tables")
+	}
+	solveWithFixture(t, "lookup", newExampleVerifierCircuit, newExampleVerifierWitness)
+
+	witness := newExampleVerifierWitness(f).(*verifier.ExampleVerifierCircuit)
+	lookupZs := append([]gl.QuadraticExtensionVariable{}, witness.Proof.Openings.LookupZs...)
+	lookupZs[0] = gl.NewQuadraticExtensionVariable(gl.NewVariable(1), gl.NewVariable(2))
+	witness.Proof.Openings.LookupZs = lookupZs
+	if test.IsSolved(newExampleVerifierCircuit(f), witness, ecc.BN254.ScalarField()) == nil {
+		t.Error("a proof with a mutated lookup_zs opening verified")
+	}
+}
+
+func TestZkVerifier(t *testing.T) {
+	if _, err := os.Stat("../testdata/zk"); os.IsNotExist(err) {
+		t.Fatal("no zk fixture, see testdata/zk.md")
+	}
+
+	if !types.ReadCommonCircuitData("../testdata/zk/common_circuit_data.json").FriParams.Hiding {
+		t.Fatal("the zk fixture isn't hiding")
+	}
+	solveWithFixture(t, "zk", newExampleVerifierCircuit, newExampleVerifierWitness)
+}
+
+func TestCommittedBatchVerifier(t *testing.T) {
+	// Verify the same proof twice, and commit to both copies of its public inputs.
+	const numProofs = 2
+
+	solveWithFixture(
+		t,
+		"step",
+		func(f fixture) frontend.Circuit {
+			circuit := verifier.NewCommittedBatchVerifierCircuit(numProofs, f.verifierOnlyCircuitData, f.commonCircuitData)
+			return &circuit
+		},
+		func(f fixture) frontend.Circuit {
+			allPublicInputs := []goldilocks.Element{}
+			for i := 0; i < numProofs; i++ {
+				for _, publicInput := range f.rawProofWithPis.PublicInputs {
+					allPublicInputs = append(allPublicInputs, goldilocks.NewElement(publicInput))
+				}
+			}
+			commitment := poseidon.NewBN254Hasher().HashNoPad(allPublicInputs)
+
+			return &verifier.CommittedBatchVerifierCircuit{
+				PublicInputsCommitment:  commitment.BigInt(new(big.Int)),
+				PublicInputs:            [][]gl.Variable{f.proofWithPis.PublicInputs, f.proofWithPis.PublicInputs},
+				Proofs:                  []variables.Proof{f.proofWithPis.Proof, f.proofWithPis.Proof},
+				VerifierOnlyCircuitData: f.verifierOnlyCircuitData,
+				CommonCircuitData:       f.commonCircuitData,
+			}
+		},
+	)
+}
+
+type circuitDigestCircuit struct {
+	ConstantSigmasCap variables.FriMerkleCap
+	CircuitDigest     frontend.Variable
+	commonCircuitData types.CommonCircuitData
+}
+
+func (c *circuitDigestCircuit) Define(api frontend.API) error {
+	verifierChip := verifier.NewVerifierChip(api, c.commonCircuitData)
+	api.AssertIsEqual(verifierChip.GetCircuitDigest(c.ConstantSigmasCap), c.CircuitDigest)
+	return nil
+}
+
+func TestGetCircuitDigest(t *testing.T) {
+	useBitDecompositionRangeCheck(t)
+
+	assert := test.NewAssert(t)
+
+	for _, plonky2Circuit := range []string{"step", "decode_block"} {
+		f := readFixture(t, plonky2Circuit)
+
+		circuit := circuitDigestCircuit{
+			ConstantSigmasCap: variables.NewFriMerkleCap(f.commonCircuitData.Config.FriConfig.CapHeight),
+			commonCircuitData: f.commonCircuitData,
+		}
+		witness := circuitDigestCircuit{
+			ConstantSigmasCap: f.verifierOnlyCircuitData.ConstantSigmasCap,
+			CircuitDigest:     f.verifierOnlyCircuitData.CircuitDigest,
+		}
+		assert.NoError(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+
+		// The digest commits to the constants and sigmas cap.
+		tamperedCap := append(variables.FriMerkleCap{}, f.verifierOnlyCircuitData.ConstantSigmasCap...)
+		tamperedCap[0], tamperedCap[1] = tamperedCap[1], tamperedCap[0]
+		witness.ConstantSigmasCap = tamperedCap
+		assert.Error(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+	}
+}
+
+func TestUniversalVerifier(t *testing.T) {
+	solveWithFixture(
+		t,
+		"step",
+		func(f fixture) frontend.Circuit {
+			circuit := verifier.NewUniversalVerifierCircuit(f.commonCircuitData)
+			return &circuit
+		},
+		func(f fixture) frontend.Circuit {
+			return &verifier.UniversalVerifierCircuit{
+				CircuitDigest:     f.verifierOnlyCircuitData.CircuitDigest,
+				PublicInputs:      f.proofWithPis.PublicInputs,
+				ConstantSigmasCap: f.verifierOnlyCircuitData.ConstantSigmasCap,
+				Proof:             f.proofWithPis.Proof,
+				CommonCircuitData: f.commonCircuitData,
+			}
+		},
+	)
+}
+
+func TestMultiVerifier(t *testing.T) {
 	assert := test.NewAssert(t)
 
-	testCase := func() {
-		plonky2Circuit := "step"
-		commonCircuitData := types.ReadCommonCircuitData("../testdata/" + plonky2Circuit + "/common_circuit_data.json")
+	allowedCircuits := []verifier.AllowedCircuit{}
+	proofsWithPis := []variables.ProofWithPublicInputs{}
+	for _, plonky2Circuit := range []string{"step", "decode_block"} {
+		f := readFixture(t, plonky2Circuit)
+		allowedCircuits = append(allowedCircuits, verifier.AllowedCircuit{
+			CommonCircuitData:       f.commonCircuitData,
+			VerifierOnlyCircuitData: f.verifierOnlyCircuitData,
+		})
+		proofsWithPis = append(proofsWithPis, f.proofWithPis)
+	}
+
+	circuit := verifier.NewMultiVerifierCircuit(allowedCircuits)
 
-		proofWithPis := variables.DeserializeProofWithPublicInputs(types.ReadProofWithPublicInputs("../testdata/" + plonky2Circuit + "/proof_with_public_inputs.json"))
-		verifierOnlyCircuitData := variables.DeserializeVerifierOnlyCircuitData(types.ReadVerifierOnlyCircuitData("../testdata/" + plonky2Circuit + "/verifier_only_circuit_data.json"))
+	// Select decode_block, whose public inputs are padded to those of step, and leave the slot of
+	// step filled with zeros.
+	witness := verifier.NewMultiVerifierWitness(allowedCircuits, 1, proofsWithPis[1])
+	err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
 
-		circuit := verifier.ExampleVerifierCircuit{
-			Proof:                   proofWithPis.Proof,
-			PublicInputs:            proofWithPis.PublicInputs,
-			VerifierOnlyCircuitData: verifierOnlyCircuitData,
-			CommonCircuitData:       commonCircuitData,
+	// The proof in the slot that isn't selected isn't checked, even when it's invalid.
+	invalidStepProof := proofsWithPis[0].Proof
+	invalidStepProof.Openings.Wires = append([]gl.QuadraticExtensionVariable{}, invalidStepProof.Openings.Wires...)
+	invalidStepProof.Openings.Wires[0] = gl.NewQuadraticExtensionVariable(gl.NewVariable(1), gl.NewVariable(2))
+	witness.Proofs[0] = invalidStepProof
+	witness.ProofsPublicInputs[0] = proofsWithPis[0].PublicInputs
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// But it is when it's selected.
+	witness = verifier.NewMultiVerifierWitness(allowedCircuits, 0, proofsWithPis[0])
+	witness.Proofs[0] = invalidStepProof
+	err = test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type publicInputsDigestCircuit struct {
+	PublicInputs       []gl.Variable
+	PublicInputsDigest [2]frontend.Variable
+	publicInputsHash   verifier.PublicInputsHash
+	commonCircuitData  types.CommonCircuitData
+}
+
+func (c *publicInputsDigestCircuit) Define(api frontend.API) error {
+	verifierChip := verifier.NewVerifierChip(api, c.commonCircuitData)
+	digest := verifierChip.GetPublicInputsDigest(c.PublicInputs, c.publicInputsHash)
+	api.AssertIsEqual(digest[0], c.PublicInputsDigest[0])
+	api.AssertIsEqual(digest[1], c.PublicInputsDigest[1])
+	return nil
+}
+
+func TestComputePublicInputsDigest(t *testing.T) {
+	// keccak256 and sha256 of the empty string.
+	for publicInputsHash, expected := range map[verifier.PublicInputsHash]string{
+		verifier.KECCAK256: "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+		verifier.SHA256:    "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	} {
+		digest, err := verifier.ComputePublicInputsDigest(nil, publicInputsHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := fmt.Sprintf("%032x%032x", digest[0], digest[1]); actual != expected {
+			t.Errorf("expected digest %s, got %s", expected, actual)
+		}
+	}
+}
+
+func TestGetPublicInputsDigest(t *testing.T) {
+	useBitDecompositionRangeCheck(t)
+
+	assert := test.NewAssert(t)
+	commonCircuitData := types.ReadCommonCircuitData("../testdata/step/common_circuit_data.json")
+	publicInputs := []uint64{0, 1, 255, 1 << 32, 0xffffffff00000000}
+
+	for _, publicInputsHash := range []verifier.PublicInputsHash{verifier.KECCAK256, verifier.SHA256} {
+		digest, err := verifier.ComputePublicInputsDigest(publicInputs, publicInputsHash)
+		if err != nil {
+			t.Fatal(err)
 		}
 
-		witness := verifier.ExampleVerifierCircuit{
-			Proof:                   proofWithPis.Proof,
-			PublicInputs:            proofWithPis.PublicInputs,
-			VerifierOnlyCircuitData: verifierOnlyCircuitData,
-			CommonCircuitData:       commonCircuitData,
+		circuit := publicInputsDigestCircuit{
+			PublicInputs:      make([]gl.Variable, len(publicInputs)),
+			publicInputsHash:  publicInputsHash,
+			commonCircuitData: commonCircuitData,
 		}
+		witness := publicInputsDigestCircuit{
+			PublicInputs:       gl.Uint64ArrayToVariableArray(publicInputs),
+			PublicInputsDigest: [2]frontend.Variable{digest[0], digest[1]},
+		}
+		assert.NoError(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+
+		witness.PublicInputs = gl.Uint64ArrayToVariableArray([]uint64{0, 1, 255, 1 << 32, 0xffffffff00000001})
+		assert.Error(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+	}
+}
+
+func TestHashedPublicInputsVerifier(t *testing.T) {
+	solveWithFixture(
+		t,
+		"step",
+		func(f fixture) frontend.Circuit {
+			circuit := verifier.NewHashedPublicInputsVerifierCircuit(f.verifierOnlyCircuitData, f.commonCircuitData, verifier.KECCAK256)
+			return &circuit
+		},
+		func(f fixture) frontend.Circuit {
+			digest, err := verifier.ComputePublicInputsDigest(f.rawProofWithPis.PublicInputs, verifier.KECCAK256)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return &verifier.HashedPublicInputsVerifierCircuit{
+				PublicInputsDigest:      [2]frontend.Variable{digest[0], digest[1]},
+				PublicInputs:            f.proofWithPis.PublicInputs,
+				Proof:                   f.proofWithPis.Proof,
+				VerifierOnlyCircuitData: f.verifierOnlyCircuitData,
+				CommonCircuitData:       f.commonCircuitData,
+			}
+		},
+	)
+}
+
+func TestPackPublicInputs(t *testing.T) {
+	publicInputs := []uint64{1, 2, 3, 0xffffffff00000000, 5}
+	packedPublicInputs := verifier.PackPublicInputs(publicInputs)
+	if len(packedPublicInputs) != 2 {
+		t.Fatalf("expected 2 packed public inputs, got %d", len(packedPublicInputs))
+	}
+	// The first public input is in the least significant limb.
+	expected := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(5), 64), new(big.Int).SetUint64(0xffffffff00000000))
+	if packedPublicInputs[1].Cmp(expected) != 0 {
+		t.Errorf("expected packed public input %s, got %s", expected, packedPublicInputs[1])
+	}
+
+	unpacked, err := verifier.UnpackPublicInputs(packedPublicInputs, uint64(len(publicInputs)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(unpacked, publicInputs) {
+		t.Errorf("expected %v, got %v", publicInputs, unpacked)
+	}
+
+	if _, err := verifier.UnpackPublicInputs(packedPublicInputs, 7); err == nil {
+		t.Error("expected an error for a number of packed public inputs that doesn't match")
+	}
+	if _, err := verifier.UnpackPublicInputs(packedPublicInputs, 4); err == nil {
+		t.Error("expected an error for a packed element with too many public inputs")
+	}
+	if _, err := verifier.UnpackPublicInputs([]*big.Int{new(big.Int).Set(gl.MODULUS)}, 1); err == nil {
+		t.Error("expected an error for a non canonical public input")
+	}
+}
+
+type unpackPublicInputsCircuit struct {
+	PackedPublicInputs []frontend.Variable
+	PublicInputs       []gl.Variable
+	commonCircuitData  types.CommonCircuitData
+}
+
+func (c *unpackPublicInputsCircuit) Define(api frontend.API) error {
+	verifierChip := verifier.NewVerifierChip(api, c.commonCircuitData)
+	publicInputs := verifierChip.UnpackPublicInputs(c.PackedPublicInputs, uint64(len(c.PublicInputs)))
+	for i := range publicInputs {
+		api.AssertIsEqual(publicInputs[i].Limb, c.PublicInputs[i].Limb)
+	}
+	return nil
+}
+
+func TestUnpackPublicInputs(t *testing.T) {
+	useBitDecompositionRangeCheck(t)
+
+	assert := test.NewAssert(t)
+	publicInputs := []uint64{1, 2, 3, 0xffffffff00000000, 5}
+
+	circuit := unpackPublicInputsCircuit{
+		PackedPublicInputs: make([]frontend.Variable, 2),
+		PublicInputs:       make([]gl.Variable, len(publicInputs)),
+		commonCircuitData:  types.ReadCommonCircuitData("../testdata/step/common_circuit_data.json"),
+	}
+	witness := unpackPublicInputsCircuit{
+		PackedPublicInputs: []frontend.Variable{},
+		PublicInputs:       gl.Uint64ArrayToVariableArray(publicInputs),
+	}
+	for _, packed := range verifier.PackPublicInputs(publicInputs) {
+		witness.PackedPublicInputs = append(witness.PackedPublicInputs, packed)
+	}
+	assert.NoError(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+
+	// The modulus packs to the same value as 0 with a carry into the next limb, but isn't canonical.
+	nonCanonical := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(5), 64), gl.MODULUS)
+	witness.PackedPublicInputs[1] = nonCanonical
+	witness.PublicInputs = gl.Uint64ArrayToVariableArray([]uint64{1, 2, 3, gl.MODULUS.Uint64(), 5})
+	assert.Error(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+}
+
+func TestPackedPublicInputsVerifier(t *testing.T) {
+	solveWithFixture(
+		t,
+		"step",
+		func(f fixture) frontend.Circuit {
+			circuit := verifier.NewPackedPublicInputsVerifierCircuit(f.verifierOnlyCircuitData, f.commonCircuitData)
+			return &circuit
+		},
+		func(f fixture) frontend.Circuit {
+			witness := verifier.PackedPublicInputsVerifierCircuit{
+				Proof:                   f.proofWithPis.Proof,
+				VerifierOnlyCircuitData: f.verifierOnlyCircuitData,
+				CommonCircuitData:       f.commonCircuitData,
+			}
+			for _, packed := range verifier.PackPublicInputs(f.rawProofWithPis.PublicInputs) {
+				witness.PackedPublicInputs = append(witness.PackedPublicInputs, packed)
+			}
+			return &witness
+		},
+	)
+}
+
+type rangeCheckPublicInputsCircuit struct {
+	PublicInputs       []gl.Variable
+	publicInputsLayout verifier.PublicInputsLayout
+	commonCircuitData  types.CommonCircuitData
+}
+
+func (c *rangeCheckPublicInputsCircuit) Define(api frontend.API) error {
+	verifierChip := verifier.NewVerifierChip(api, c.commonCircuitData)
+	verifierChip.RangeCheckPublicInputs(c.PublicInputs, c.publicInputsLayout)
+	return nil
+}
+
+func TestRangeCheckPublicInputs(t *testing.T) {
+	useBitDecompositionRangeCheck(t)
+
+	assert := test.NewAssert(t)
+	publicInputsLayout := verifier.NewPublicInputsLayout(5).WithBitWidth(0, 2, 8).WithBitWidth(3, 4, 0).WithBitWidth(4, 5, 20)
 
+	circuit := rangeCheckPublicInputsCircuit{
+		PublicInputs:       make([]gl.Variable, 5),
+		publicInputsLayout: publicInputsLayout,
+		commonCircuitData:  types.ReadCommonCircuitData("../testdata/step/common_circuit_data.json"),
+	}
+
+	for _, testCase := range []struct {
+		publicInputs []uint64
+		valid        bool
+	}{
+		{[]uint64{0, 255, gl.MODULUS.Uint64() - 1, 0, 1<<20 - 1}, true},
+		{[]uint64{256, 0, 0, 0, 0}, false},
+		{[]uint64{0, 0, 0, 1, 0}, false},
+		{[]uint64{0, 0, 0, 0, 1 << 20}, false},
+	} {
+		witness := rangeCheckPublicInputsCircuit{PublicInputs: gl.Uint64ArrayToVariableArray(testCase.publicInputs)}
 		err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
-		assert.NoError(err)
+		if testCase.valid {
+			assert.NoError(err, "%v", testCase.publicInputs)
+		} else {
+			assert.Error(err, "%v", testCase.publicInputs)
+		}
+	}
+
+	// Goldilocks elements that aren't canonical are rejected even without a bit width.
+	witness := rangeCheckPublicInputsCircuit{PublicInputs: gl.Uint64ArrayToVariableArray([]uint64{0, 0, 0, 0, 0})}
+	witness.PublicInputs[2] = gl.NewVariable(gl.MODULUS)
+	assert.Error(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+}
+
+func TestStepVerifierWithPublicInputsLayout(t *testing.T) {
+	// The first 32 public inputs of a step proof are bytes.
+	publicInputsLayout := func(f fixture) verifier.PublicInputsLayout {
+		return verifier.NewPublicInputsLayout(f.commonCircuitData.NumPublicInputs).WithBitWidth(0, 32, 8)
+	}
+
+	solveWithFixture(
+		t,
+		"step",
+		func(f fixture) frontend.Circuit {
+			circuit := newExampleVerifierCircuit(f).(*verifier.ExampleVerifierCircuit)
+			circuit.PublicInputsLayout = publicInputsLayout(f)
+			return circuit
+		},
+		func(f fixture) frontend.Circuit {
+			witness := newExampleVerifierWitness(f).(*verifier.ExampleVerifierCircuit)
+			witness.PublicInputsLayout = publicInputsLayout(f)
+			return witness
+		},
+	)
+}
+
+const testPublicInputsSchema = `{"fields": [
+	{"name": "block_hash", "type": "bytes32"},
+	{"name": "words", "type": "u32[]", "len": 2},
+	{"name": "state_root", "type": "goldilocks_hash"},
+	{"name": "height", "type": "goldilocks"}
+]}`
+
+type decodePublicInputsCircuit struct {
+	PublicInputs      []gl.Variable
+	BlockHash         [32]frontend.Variable
+	LastWord          frontend.Variable
+	schema            verifier.PublicInputsSchema
+	commonCircuitData types.CommonCircuitData
+}
+
+func (c *decodePublicInputsCircuit) Define(api frontend.API) error {
+	verifierChip := verifier.NewVerifierChip(api, c.commonCircuitData)
+	decoded, err := verifierChip.DecodePublicInputs(c.PublicInputs, c.schema)
+	if err != nil {
+		return err
+	}
+
+	blockHash := decoded.Bytes32("block_hash")
+	for i := range blockHash {
+		api.AssertIsEqual(blockHash[i], c.BlockHash[i])
+	}
+	api.AssertIsEqual(decoded.U32Array("words")[1], c.LastWord)
+
+	// The state root and the height are the last public inputs.
+	stateRoot := decoded.GoldilocksHash("state_root")
+	api.AssertIsEqual(stateRoot[3].Limb, c.PublicInputs[len(c.PublicInputs)-2].Limb)
+	api.AssertIsEqual(decoded.Goldilocks("height").Limb, c.PublicInputs[len(c.PublicInputs)-1].Limb)
+	return nil
+}
+
+func TestParsePublicInputsSchema(t *testing.T) {
+	schema, err := verifier.ParsePublicInputsSchema(strings.NewReader(testPublicInputsSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.NumPublicInputs() != 39 {
+		t.Errorf("expected 39 public inputs, got %d", schema.NumPublicInputs())
+	}
+
+	expectedLayout := verifier.NewPublicInputsLayout(39).WithBitWidth(0, 32, 8).WithBitWidth(32, 34, 32)
+	if !reflect.DeepEqual(schema.Layout(), expectedLayout) {
+		t.Errorf("expected layout %v, got %v", expectedLayout, schema.Layout())
+	}
+
+	if err := schema.ValidateFor(39); err != nil {
+		t.Error(err)
+	}
+	if err := schema.ValidateFor(40); err == nil || !strings.Contains(err.Error(), "take up 39 public inputs, but the circuit has 40") {
+		t.Errorf("expected an error for a schema that doesn't cover every public input, got %v", err)
+	}
+
+	for _, invalidSchema := range []string{
+		`{"fields": [{"name": "x", "type": "u16"}]}`,
+		`{"fields": [{"name": "x", "type": "u32[]"}]}`,
+		`{"fields": [{"name": "x", "type": "u32", "len": 2}]}`,
+		`{"fields": [{"type": "u32"}]}`,
+		`{"fields": [{"name": "x", "type": "u32"}, {"name": "x", "type": "u8"}]}`,
+		`{"fields": [{"name": "x", "type": "u32", "bits": 2}]}`,
+	} {
+		if _, err := verifier.ParsePublicInputsSchema(strings.NewReader(invalidSchema)); err == nil {
+			t.Errorf("expected an error for %s", invalidSchema)
+		}
+	}
+}
+
+func TestDecodePublicInputs(t *testing.T) {
+	useBitDecompositionRangeCheck(t)
+
+	assert := test.NewAssert(t)
+	schema, err := verifier.ParsePublicInputsSchema(strings.NewReader(testPublicInputsSchema))
+	assert.NoError(err)
+
+	circuit := decodePublicInputsCircuit{
+		PublicInputs:      make([]gl.Variable, schema.NumPublicInputs()),
+		schema:            schema,
+		commonCircuitData: types.ReadCommonCircuitData("../testdata/step/common_circuit_data.json"),
+	}
+
+	newWitness := func(publicInputs []uint64) *decodePublicInputsCircuit {
+		witness := decodePublicInputsCircuit{
+			PublicInputs: gl.Uint64ArrayToVariableArray(publicInputs),
+			LastWord:     publicInputs[33],
+		}
+		for i := range witness.BlockHash {
+			witness.BlockHash[i] = publicInputs[i]
+		}
+		return &witness
+	}
+
+	publicInputs := make([]uint64, schema.NumPublicInputs())
+	for i := 0; i < 32; i++ {
+		publicInputs[i] = uint64(255 - i)
+	}
+	publicInputs[32], publicInputs[33] = 1<<32-1, 7
+	for i := 34; i < len(publicInputs); i++ {
+		publicInputs[i] = gl.MODULUS.Uint64() - uint64(i)
+	}
+	assert.NoError(test.IsSolved(&circuit, newWitness(publicInputs), ecc.BN254.ScalarField()))
+
+	// A byte that doesn't fit in 8 bits, and a word that doesn't fit in 32 bits.
+	for _, i := range []int{5, 32} {
+		invalidPublicInputs := append([]uint64{}, publicInputs...)
+		invalidPublicInputs[i] += 1 << 32
+		assert.Error(test.IsSolved(&circuit, newWitness(invalidPublicInputs), ecc.BN254.ScalarField()), "public input %d", i)
+	}
+
+	// A circuit with a public input that the schema doesn't cover.
+	circuit.PublicInputs = make([]gl.Variable, schema.NumPublicInputs()+1)
+	err = test.IsSolved(&circuit, newWitness(append(publicInputs, 0)), ecc.BN254.ScalarField())
+	if err == nil || !strings.Contains(err.Error(), "but the circuit has 40") {
+		t.Errorf("expected an error for a schema that doesn't cover every public input, got %v", err)
+	}
+}
+
+type packPublicInputsHashCircuit struct {
+	PublicInputs      []gl.Variable
+	PublicInputsHash  frontend.Variable
+	commonCircuitData types.CommonCircuitData
+}
+
+func (c *packPublicInputsHashCircuit) Define(api frontend.API) error {
+	verifierChip := verifier.NewVerifierChip(api, c.commonCircuitData)
+	publicInputsHash := verifierChip.GetPublicInputsHash(c.PublicInputs)
+	api.AssertIsEqual(verifierChip.PackPublicInputsHash(publicInputsHash), c.PublicInputsHash)
+	return nil
+}
+
+func TestPackPublicInputsHash(t *testing.T) {
+	useBitDecompositionRangeCheck(t)
+
+	assert := test.NewAssert(t)
+	commonCircuitData := types.ReadCommonCircuitData("../testdata/step/common_circuit_data.json")
+
+	for _, testCase := range []struct {
+		publicInputs []uint64
+		hash         []uint64
+		// Whether the hash, read as a 256 bit integer, is at least the BN254 scalar field modulus.
+		wraps  bool
+		packed string
+	}{
+		// See TestGoldilocksHasherPublicInputsHash.
+		{
+			[]uint64{0, 1, 3736710860384812976},
+			[]uint64{8416658900775745054, 12574228347150446423, 9629056739760131473, 3119289788404190010},
+			false,
+			"19580099343965893354322575727153271949234642565855326146626669748042740938270",
+		},
+		// The packed value is the hash minus 4 times the modulus.
+		{
+			[]uint64{0},
+			[]uint64{4330397376401421145, 14124799381142128323, 8742572140681234676, 14345658006221440202},
+			true,
+			"2496183278759332195734985104801459414814956179165592809308975002854420951893",
+		},
+	} {
+		// The value a contract computes, following the doc comment of PackPublicInputsHash.
+		hash := new(big.Int)
+		for i := len(testCase.hash) - 1; i >= 0; i-- {
+			hash.Or(hash.Lsh(hash, 64), new(big.Int).SetUint64(testCase.hash[i]))
+		}
+		assert.Equal(testCase.wraps, hash.Cmp(ecc.BN254.ScalarField()) >= 0)
+		expected := new(big.Int).Mod(hash, ecc.BN254.ScalarField())
+		assert.Equal(testCase.packed, expected.String())
+
+		packed := verifier.ComputePackedPublicInputsHash(testCase.publicInputs)
+		assert.Equal(testCase.packed, packed.String())
+
+		circuit := packPublicInputsHashCircuit{
+			PublicInputs:      make([]gl.Variable, len(testCase.publicInputs)),
+			commonCircuitData: commonCircuitData,
+		}
+
+		witness := packPublicInputsHashCircuit{
+			PublicInputs:     gl.Uint64ArrayToVariableArray(testCase.publicInputs),
+			PublicInputsHash: packed,
+		}
+		assert.NoError(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+
+		witness.PublicInputsHash = new(big.Int).Add(packed, big.NewInt(1))
+		assert.Error(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+	}
+}
+
+func TestPublicInputsHashVerifier(t *testing.T) {
+	solveWithFixture(
+		t,
+		"step",
+		func(f fixture) frontend.Circuit {
+			circuit := verifier.NewPublicInputsHashVerifierCircuit(f.verifierOnlyCircuitData, f.commonCircuitData)
+			return &circuit
+		},
+		func(f fixture) frontend.Circuit {
+			return &verifier.PublicInputsHashVerifierCircuit{
+				PublicInputsHash:        verifier.ComputePackedPublicInputsHash(f.rawProofWithPis.PublicInputs),
+				PublicInputs:            f.proofWithPis.PublicInputs,
+				Proof:                   f.proofWithPis.Proof,
+				VerifierOnlyCircuitData: f.verifierOnlyCircuitData,
+				CommonCircuitData:       f.commonCircuitData,
+			}
+		},
+	)
+}
+
+type privatePublicInputsCommitmentCircuit struct {
+	PrivatePublicInputs []gl.Variable
+	Blinding            frontend.Variable
+	Commitment          frontend.Variable
+	commonCircuitData   types.CommonCircuitData
+}
+
+func (c *privatePublicInputsCommitmentCircuit) Define(api frontend.API) error {
+	verifierChip := verifier.NewVerifierChip(api, c.commonCircuitData)
+	api.AssertIsEqual(verifierChip.GetPrivatePublicInputsCommitment(c.PrivatePublicInputs, c.Blinding), c.Commitment)
+	return nil
+}
+
+func TestPrivatePublicInputsCommitment(t *testing.T) {
+	useBitDecompositionRangeCheck(t)
+
+	assert := test.NewAssert(t)
+
+	disclosed, private := verifier.SplitPublicInputs([]uint64{1, 2, 3, 4, 5}, []bool{true, false, false, true, false})
+	if !reflect.DeepEqual(disclosed, []uint64{1, 4}) || !reflect.DeepEqual(private, []uint64{2, 3, 5}) {
+		t.Fatalf("unexpected split %v, %v", disclosed, private)
 	}
-	testCase()
+
+	blinding := big.NewInt(12345)
+	commitment := verifier.ComputePrivatePublicInputsCommitment(private, blinding)
+	if commitment.Cmp(verifier.ComputePrivatePublicInputsCommitment(private, big.NewInt(12346))) == 0 {
+		t.Error("the commitment doesn't depend on the blinding")
+	}
+
+	circuit := privatePublicInputsCommitmentCircuit{
+		PrivatePublicInputs: make([]gl.Variable, len(private)),
+		commonCircuitData:   types.ReadCommonCircuitData("../testdata/step/common_circuit_data.json"),
+	}
+
+	witness := privatePublicInputsCommitmentCircuit{
+		PrivatePublicInputs: gl.Uint64ArrayToVariableArray(private),
+		Blinding:            blinding,
+		Commitment:          commitment,
+	}
+	assert.NoError(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+
+	witness.PrivatePublicInputs[1] = gl.NewVariable(4)
+	assert.Error(test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField()))
+}
+
+func TestSelectiveDisclosureVerifier(t *testing.T) {
+	// Disclose the first 32 public inputs, and keep the rest private.
+	disclosed := func(f fixture) []bool {
+		disclosed := make([]bool, f.commonCircuitData.NumPublicInputs)
+		for i := 0; i < 32; i++ {
+			disclosed[i] = true
+		}
+		return disclosed
+	}
+	blinding := big.NewInt(42)
+
+	solveWithFixture(
+		t,
+		"step",
+		func(f fixture) frontend.Circuit {
+			circuit := verifier.NewSelectiveDisclosureVerifierCircuit(f.verifierOnlyCircuitData, f.commonCircuitData, disclosed(f))
+			return &circuit
+		},
+		func(f fixture) frontend.Circuit {
+			disclosedPublicInputs, privatePublicInputs := verifier.SplitPublicInputs(f.rawProofWithPis.PublicInputs, disclosed(f))
+			return &verifier.SelectiveDisclosureVerifierCircuit{
+				DisclosedPublicInputs:         gl.Uint64ArrayToVariableArray(disclosedPublicInputs),
+				PrivatePublicInputsCommitment: verifier.ComputePrivatePublicInputsCommitment(privatePublicInputs, blinding),
+				PrivatePublicInputs:           gl.Uint64ArrayToVariableArray(privatePublicInputs),
+				Blinding:                      blinding,
+				Proof:                         f.proofWithPis.Proof,
+				VerifierOnlyCircuitData:       f.verifierOnlyCircuitData,
+				Disclosed:                     disclosed(f),
+				CommonCircuitData:             f.commonCircuitData,
+			}
+		},
+	)
 }